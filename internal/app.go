@@ -10,6 +10,7 @@ func Start(context context.Context, cfg config.Config) {
 	app := server.NewServer(&cfg)
 
 	app.ConfigureRoutes()
+	go app.RunJobs(context)
 
 	app.Run(context)
 }
@@ -4,7 +4,11 @@ import (
 	"fmt"
 	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/viper"
+	"golang.org/x/crypto/bcrypt"
 	"log"
+	"net"
+	"reflect"
+	"slices"
 	"time"
 )
 
@@ -12,6 +16,106 @@ type Config struct {
 	DB   DB   `mapstructure:"database"`
 	HTTP HTTP `mapstructure:"http"`
 	JWT  JWT  `mapstructure:"jwt"`
+	Auth Auth `mapstructure:"auth"`
+	OIDC OIDC `mapstructure:"oidc"`
+	LDAP LDAP `mapstructure:"ldap"`
+
+	PaymentRequest PaymentRequest `mapstructure:"payment_request"`
+	Shop           Shop           `mapstructure:"shop"`
+	GroupPurchase  GroupPurchase  `mapstructure:"group_purchase"`
+	UserSearch     UserSearch     `mapstructure:"user_search"`
+	Achievements   Achievements   `mapstructure:"achievements"`
+}
+
+type Achievements struct {
+	Rules []AchievementRule `mapstructure:"rules"`
+}
+
+// AchievementRule awards Badge, and Bonus coins, once the user's Metric reaches Threshold.
+type AchievementRule struct {
+	Badge       string `mapstructure:"badge"`
+	Description string `mapstructure:"description"`
+	Metric      string `mapstructure:"metric"`
+	Threshold   uint   `mapstructure:"threshold"`
+	Bonus       uint   `mapstructure:"bonus"`
+}
+
+// AchievementMetrics lists the metrics achievement rules can be declared on.
+var AchievementMetrics = []string{"coins_sent", "coins_received", "colleagues_thanked", "purchases"}
+
+type UserSearch struct {
+	RateLimit  int           `mapstructure:"rate_limit"`
+	RateWindow time.Duration `mapstructure:"rate_window"`
+}
+
+type GroupPurchase struct {
+	ExpiryCheckInterval time.Duration `mapstructure:"expiry_check_interval"`
+}
+
+type Shop struct {
+	ReturnWindow time.Duration `mapstructure:"return_window"`
+}
+
+type PaymentRequest struct {
+	TTL time.Duration `mapstructure:"ttl"`
+}
+
+// Authenticators that can check the passwords of users signing in.
+const (
+	AuthenticatorPassword = "password"
+	AuthenticatorLDAP     = "ldap"
+)
+
+type Auth struct {
+	// Authenticator checks passwords: AuthenticatorPassword for the hashes stored by the shop,
+	// AuthenticatorLDAP for a bind against the LDAP directory.
+	Authenticator     string        `mapstructure:"authenticator"`
+	PasswordResetTTL  time.Duration `mapstructure:"password_reset_ttl"`
+	PasswordMinLength int           `mapstructure:"password_min_length"`
+	// PasswordDenyList is the path of a file with common or breached passwords, one per line.
+	PasswordDenyList string `mapstructure:"password_deny_list"`
+	BcryptCost       int    `mapstructure:"bcrypt_cost"`
+	// MaxFailedLogins locks a username out after that many failed sign-ins in a row; 0 turns it off.
+	MaxFailedLogins      uint          `mapstructure:"max_failed_logins"`
+	MaxFailedLoginsPerIP uint          `mapstructure:"max_failed_logins_per_ip"`
+	LockoutDuration      time.Duration `mapstructure:"lockout_duration"`
+	// LoginBackoff is the wait after the first failed sign-in of a username; it doubles with every further failure.
+	LoginBackoff time.Duration `mapstructure:"login_backoff"`
+	// TOTPIssuer names the shop in authenticator apps.
+	TOTPIssuer            string        `mapstructure:"totp_issuer"`
+	TwoFactorChallengeTTL time.Duration `mapstructure:"two_factor_challenge_ttl"`
+	// APIKeyRotationGrace is how long a rotated API key keeps working next to its replacement.
+	APIKeyRotationGrace time.Duration `mapstructure:"api_key_rotation_grace"`
+}
+
+// OIDC configures single sign-on with an OpenID Connect identity provider.
+type OIDC struct {
+	// Issuer turns SSO sign-in on; leave it empty to turn it off.
+	Issuer       string `mapstructure:"issuer"`
+	ClientID     string `mapstructure:"client_id"`
+	ClientSecret string `mapstructure:"client_secret"`
+	RedirectURL  string `mapstructure:"redirect_url"`
+	// AllowSignUp creates users on their first SSO sign-in; otherwise only existing users can sign in.
+	AllowSignUp bool          `mapstructure:"allow_sign_up"`
+	LoginTTL    time.Duration `mapstructure:"login_ttl"`
+}
+
+// LDAP configures the LDAP directory passwords are checked against when it is the authenticator.
+type LDAP struct {
+	// URL of the directory, ldap://host:389 or ldaps://host:636.
+	URL string `mapstructure:"url"`
+	// BindDN and BindPassword are the account users are searched with; leave them empty to search anonymously.
+	BindDN       string `mapstructure:"bind_dn"`
+	BindPassword string `mapstructure:"bind_password"`
+	BaseDN       string `mapstructure:"base_dn"`
+	// UserAttribute holds the username, such as uid or sAMAccountName.
+	UserAttribute string `mapstructure:"user_attribute"`
+	// GroupRoles maps groups to roles as role=group DN pairs separated by semicolons. The first listed
+	// group the user is a member of decides the role.
+	GroupRoles string `mapstructure:"group_roles"`
+	// DefaultRole is given to users in none of the groups; when it is empty they cannot sign in.
+	DefaultRole string        `mapstructure:"default_role"`
+	Timeout     time.Duration `mapstructure:"timeout"`
 }
 
 type JWT struct {
@@ -32,6 +136,9 @@ type DB struct {
 
 type HTTP struct {
 	Port string `mapstructure:"port"`
+	// TrustedProxies lists the IPs or CIDRs of reverse proxies whose X-Forwarded-For header is
+	// believed. Without any, the client IP is the address of the connection.
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 func LoadConfig() (Config, error) {
@@ -40,10 +147,48 @@ func LoadConfig() (Config, error) {
 	viper.AddConfigPath("config")
 	viper.AddConfigPath(".")
 
+	viper.SetDefault("http.trusted_proxies", "")
 	viper.SetDefault("database.max_idle_conns", 10)
 	viper.SetDefault("database.max_open_conns", 100)
 	viper.SetDefault("database.conn_max_life", time.Hour)
 	viper.SetDefault("jwt.duration", time.Hour*24)
+	viper.SetDefault("auth.authenticator", AuthenticatorPassword)
+	viper.SetDefault("auth.password_reset_ttl", time.Hour*24)
+	viper.SetDefault("auth.password_min_length", 8)
+	viper.SetDefault("auth.password_deny_list", "")
+	viper.SetDefault("auth.bcrypt_cost", bcrypt.DefaultCost)
+	viper.SetDefault("auth.max_failed_logins", 5)
+	viper.SetDefault("auth.max_failed_logins_per_ip", 20)
+	viper.SetDefault("auth.lockout_duration", time.Minute*15)
+	viper.SetDefault("auth.login_backoff", time.Second)
+	viper.SetDefault("auth.totp_issuer", "Merch Shop")
+	viper.SetDefault("auth.two_factor_challenge_ttl", time.Minute*5)
+	viper.SetDefault("auth.api_key_rotation_grace", time.Hour*24)
+	viper.SetDefault("oidc.issuer", "")
+	viper.SetDefault("oidc.client_id", "")
+	viper.SetDefault("oidc.client_secret", "")
+	viper.SetDefault("oidc.redirect_url", "")
+	viper.SetDefault("oidc.allow_sign_up", true)
+	viper.SetDefault("oidc.login_ttl", time.Minute*10)
+	viper.SetDefault("ldap.url", "")
+	viper.SetDefault("ldap.bind_dn", "")
+	viper.SetDefault("ldap.bind_password", "")
+	viper.SetDefault("ldap.base_dn", "")
+	viper.SetDefault("ldap.user_attribute", "uid")
+	viper.SetDefault("ldap.group_roles", "")
+	viper.SetDefault("ldap.default_role", "")
+	viper.SetDefault("ldap.timeout", time.Second*5)
+	viper.SetDefault("payment_request.ttl", time.Hour*24*7)
+	viper.SetDefault("shop.return_window", time.Hour*24*14)
+	viper.SetDefault("group_purchase.expiry_check_interval", time.Minute)
+	viper.SetDefault("user_search.rate_limit", 30)
+	viper.SetDefault("user_search.rate_window", time.Minute)
+	viper.SetDefault("achievements.rules", []map[string]interface{}{
+		{"badge": "first-purchase", "description": "Bought the first item", "metric": "purchases", "threshold": 1},
+		{"badge": "team-player", "description": "Thanked 10 distinct colleagues", "metric": "colleagues_thanked", "threshold": 10, "bonus": 0},
+		{"badge": "generous", "description": "Sent 1000 coins in total", "metric": "coins_sent", "threshold": 1000},
+		{"badge": "recognised", "description": "Received 1000 coins in total", "metric": "coins_received", "threshold": 1000, "bonus": 0},
+	})
 
 	viper.AutomaticEnv()
 	viper.BindEnv("database.host", "DB_HOST")
@@ -57,15 +202,102 @@ func LoadConfig() (Config, error) {
 	viper.BindEnv("jwt.signing_key", "JWT_SIGNING_KEY")
 	viper.BindEnv("jwt.duration", "JWT_DURATION")
 	viper.BindEnv("http.port", "HTTP_PORT")
+	viper.BindEnv("http.trusted_proxies", "HTTP_TRUSTED_PROXIES")
+	viper.BindEnv("auth.authenticator", "AUTHENTICATOR")
+	viper.BindEnv("auth.password_reset_ttl", "PASSWORD_RESET_TTL")
+	viper.BindEnv("auth.password_min_length", "PASSWORD_MIN_LENGTH")
+	viper.BindEnv("auth.password_deny_list", "PASSWORD_DENY_LIST")
+	viper.BindEnv("auth.bcrypt_cost", "BCRYPT_COST")
+	viper.BindEnv("auth.max_failed_logins", "MAX_FAILED_LOGINS")
+	viper.BindEnv("auth.max_failed_logins_per_ip", "MAX_FAILED_LOGINS_PER_IP")
+	viper.BindEnv("auth.lockout_duration", "LOCKOUT_DURATION")
+	viper.BindEnv("auth.login_backoff", "LOGIN_BACKOFF")
+	viper.BindEnv("auth.totp_issuer", "TOTP_ISSUER")
+	viper.BindEnv("auth.two_factor_challenge_ttl", "TWO_FACTOR_CHALLENGE_TTL")
+	viper.BindEnv("auth.api_key_rotation_grace", "API_KEY_ROTATION_GRACE")
+	viper.BindEnv("oidc.issuer", "OIDC_ISSUER")
+	viper.BindEnv("oidc.client_id", "OIDC_CLIENT_ID")
+	viper.BindEnv("oidc.client_secret", "OIDC_CLIENT_SECRET")
+	viper.BindEnv("oidc.redirect_url", "OIDC_REDIRECT_URL")
+	viper.BindEnv("oidc.allow_sign_up", "OIDC_ALLOW_SIGN_UP")
+	viper.BindEnv("oidc.login_ttl", "OIDC_LOGIN_TTL")
+	viper.BindEnv("ldap.url", "LDAP_URL")
+	viper.BindEnv("ldap.bind_dn", "LDAP_BIND_DN")
+	viper.BindEnv("ldap.bind_password", "LDAP_BIND_PASSWORD")
+	viper.BindEnv("ldap.base_dn", "LDAP_BASE_DN")
+	viper.BindEnv("ldap.user_attribute", "LDAP_USER_ATTRIBUTE")
+	viper.BindEnv("ldap.group_roles", "LDAP_GROUP_ROLES")
+	viper.BindEnv("ldap.default_role", "LDAP_DEFAULT_ROLE")
+	viper.BindEnv("ldap.timeout", "LDAP_TIMEOUT")
+	viper.BindEnv("payment_request.ttl", "PAYMENT_REQUEST_TTL")
+	viper.BindEnv("shop.return_window", "SHOP_RETURN_WINDOW")
+	viper.BindEnv("group_purchase.expiry_check_interval", "GROUP_PURCHASE_EXPIRY_CHECK_INTERVAL")
+	viper.BindEnv("user_search.rate_limit", "USER_SEARCH_RATE_LIMIT")
+	viper.BindEnv("user_search.rate_window", "USER_SEARCH_RATE_WINDOW")
 
 	if err := viper.ReadInConfig(); err != nil {
 		log.Printf("Warning: Could not read config file: %v", err)
 	}
 
 	var config Config
-	if err := viper.Unmarshal(&config, func(decoder *mapstructure.DecoderConfig) { decoder.ErrorUnset = true }); err != nil {
+	decodeHook := viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		decodeAchievements,
+	))
+	if err := viper.Unmarshal(&config, decodeHook, func(decoder *mapstructure.DecoderConfig) { decoder.ErrorUnset = true }); err != nil {
 		return Config{}, fmt.Errorf("unable to decode into struct, %w", err)
 	}
+	if config.Auth.BcryptCost < bcrypt.MinCost || config.Auth.BcryptCost > bcrypt.MaxCost {
+		return Config{}, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	if config.Auth.Authenticator != AuthenticatorPassword && config.Auth.Authenticator != AuthenticatorLDAP {
+		return Config{}, fmt.Errorf("unknown authenticator %q", config.Auth.Authenticator)
+	}
+	if config.Auth.Authenticator == AuthenticatorLDAP && (config.LDAP.URL == "" || config.LDAP.BaseDN == "" || config.LDAP.UserAttribute == "") {
+		return Config{}, fmt.Errorf("ldap url, base dn and user attribute are required for the ldap authenticator")
+	}
+	if config.OIDC.Issuer != "" && (config.OIDC.ClientID == "" || config.OIDC.RedirectURL == "") {
+		return Config{}, fmt.Errorf("oidc client id and redirect url are required when an issuer is set")
+	}
+	for _, proxy := range config.HTTP.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			return Config{}, fmt.Errorf("trusted proxy %q is not an IP or CIDR", proxy)
+		}
+	}
+	if config.GroupPurchase.ExpiryCheckInterval <= 0 {
+		return Config{}, fmt.Errorf("group purchase expiry check interval must be positive")
+	}
+	if err := validateAchievementRules(config.Achievements.Rules); err != nil {
+		return Config{}, err
+	}
 
 	return config, nil
 }
+
+func validateAchievementRules(rules []AchievementRule) error {
+	badges := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		if rule.Badge == "" {
+			return fmt.Errorf("achievement rule without badge")
+		}
+		if badges[rule.Badge] {
+			return fmt.Errorf("achievement badge %q is declared twice", rule.Badge)
+		}
+		badges[rule.Badge] = true
+		if !slices.Contains(AchievementMetrics, rule.Metric) {
+			return fmt.Errorf("achievement badge %q has unknown metric %q", rule.Badge, rule.Metric)
+		}
+	}
+	return nil
+}
+
+// decodeAchievements decodes achievement rules without ErrorUnset so optional rule fields may be left out.
+func decodeAchievements(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if to != reflect.TypeOf(Achievements{}) {
+		return data, nil
+	}
+	var achievements Achievements
+	err := mapstructure.Decode(data, &achievements)
+	return achievements, err
+}
@@ -3,63 +3,123 @@ package db
 import (
 	"gorm.io/gorm"
 	"merch_shop/internal/entity"
+	"merch_shop/internal/repository"
 )
 
 func InitDB(gormDB *gorm.DB) *gorm.DB {
-	err := gormDB.AutoMigrate(entity.InventoryItem{}, entity.Item{}, entity.User{}, entity.Transaction{})
+	// Inventory entries used to be unique per (user, item); variants made the old index too strict.
+	if gormDB.Migrator().HasIndex(&entity.InventoryItem{}, "entry") {
+		if err := gormDB.Migrator().DropIndex(&entity.InventoryItem{}, "entry"); err != nil {
+			return nil
+		}
+	}
+	err := gormDB.AutoMigrate(entity.InventoryItem{}, entity.Item{}, entity.User{}, entity.Transaction{},
+		entity.PaymentRequest{}, entity.ItemTransfer{}, entity.Purchase{}, entity.ItemVariant{}, entity.PromoCode{},
+		entity.ItemPrice{}, entity.Category{}, entity.ItemTag{},
+		entity.WishlistItem{}, entity.Notification{}, entity.GroupPurchase{}, entity.Pledge{},
+		entity.Wallet{}, entity.WalletMember{}, entity.WalletTransaction{}, entity.Team{}, entity.TeamMember{},
+		entity.DailyTransfer{}, entity.UserBadge{},
+		entity.LoginThrottle{}, entity.RecoveryCode{}, entity.APIKey{}, entity.OIDCLogin{})
 	if err != nil {
 		return nil
 	}
+	if err := backfillDailyTransfers(gormDB); err != nil {
+		return nil
+	}
 
 	gormDB = SeedData(gormDB)
 
 	return gormDB
 }
 
+// backfillDailyTransfers builds the daily transfer totals from existing transactions the first time they are needed.
+func backfillDailyTransfers(db *gorm.DB) error {
+	var count int64
+	if err := db.Model(&entity.DailyTransfer{}).Count(&count).Error; err != nil || count > 0 {
+		return err
+	}
+	var transactions []entity.Transaction
+	return db.Transaction(func(tx *gorm.DB) error {
+		repo := repository.NewGormTransactionRepository(tx)
+		return tx.Where("wallet_id IS NULL").FindInBatches(&transactions, 500, func(batch *gorm.DB, _ int) error {
+			for i := range transactions {
+				if err := repo.RecordDailyTransfer(&transactions[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+	})
+}
+
 func SeedData(db *gorm.DB) *gorm.DB {
 	var count int64
+	db.Model(&entity.Category{}).Count(&count)
+	if count == 0 {
+		categories := []entity.Category{
+			{Name: "apparel"},
+			{Name: "stationery"},
+			{Name: "electronics"},
+			{Name: "accessories"},
+		}
+
+		if db.Create(&categories).Error != nil {
+			return nil
+		}
+	}
+
 	db.Model(&entity.Item{}).Count(&count)
 	if count == 0 {
 		items := []entity.Item{
 			{
-				Name:  "t-shirt",
-				Price: 80,
+				Name:     "t-shirt",
+				Price:    80,
+				Category: "apparel",
 			},
 			{
-				Name:  "cup",
-				Price: 20,
+				Name:     "cup",
+				Price:    20,
+				Category: "accessories",
 			},
 			{
-				Name:  "book",
-				Price: 50,
+				Name:     "book",
+				Price:    50,
+				Category: "stationery",
 			},
 			{
-				Name:  "pen",
-				Price: 10,
+				Name:     "pen",
+				Price:    10,
+				Category: "stationery",
 			},
 			{
-				Name:  "powerbank",
-				Price: 200,
+				Name:     "powerbank",
+				Price:    200,
+				Category: "electronics",
 			},
 			{
-				Name:  "hoody",
-				Price: 300,
+				Name:     "hoody",
+				Price:    300,
+				Category: "apparel",
 			},
 			{
-				Name:  "umbrella",
-				Price: 200,
+				Name:     "umbrella",
+				Price:    200,
+				Category: "accessories",
 			},
 			{
-				Name:  "socks",
-				Price: 10,
+				Name:     "socks",
+				Price:    10,
+				Category: "apparel",
 			},
 			{
-				Name:  "wallet",
-				Price: 50,
+				Name:     "wallet",
+				Price:    50,
+				Category: "accessories",
 			},
 			{
-				Name:  "pink-hoody",
-				Price: 500,
+				Name:     "pink-hoody",
+				Price:    500,
+				Category: "apparel",
 			},
 		}
 
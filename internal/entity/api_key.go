@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+const (
+	ScopeCoinsGrant  = "coins:grant"
+	ScopeCatalogRead = "catalog:read"
+	ScopeAccountRead = "account:read"
+)
+
+// APIKeyScopes lists the scopes API keys can be granted.
+var APIKeyScopes = []string{ScopeCoinsGrant, ScopeCatalogRead, ScopeAccountRead}
+
+// APIKey authenticates a service account. Only the SHA-256 of the key is stored; Prefix identifies
+// the key in listings.
+type APIKey struct {
+	ID      uint `gorm:"primaryKey"`
+	UserID  uint `gorm:"index"`
+	Name    string
+	Prefix  string
+	KeyHash string `gorm:"uniqueIndex"`
+	// Scopes is the comma separated list of granted scopes.
+	Scopes     string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	// ExpiresAt is set on keys that were rotated and stay valid for a grace period.
+	ExpiresAt *time.Time
+	RevokedAt *time.Time
+}
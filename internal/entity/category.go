@@ -0,0 +1,8 @@
+package entity
+
+import "gorm.io/gorm"
+
+type Category struct {
+	gorm.Model
+	Name string `gorm:"uniqueIndex:category_name"`
+}
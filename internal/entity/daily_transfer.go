@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// DailyTransfer sums the coins one user sent another on one day (UTC) so leaderboards
+// read a few rows per pair instead of every transaction.
+type DailyTransfer struct {
+	Day    time.Time `gorm:"primaryKey"`
+	FromId uint      `gorm:"primaryKey;index"`
+	ToId   uint      `gorm:"primaryKey;index"`
+	Amount uint
+	Count  uint
+}
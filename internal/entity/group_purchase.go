@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	GroupPurchaseOpen      = "open"
+	GroupPurchaseCompleted = "completed"
+	GroupPurchaseExpired   = "expired"
+	GroupPurchaseCancelled = "cancelled"
+)
+
+// GroupPurchase collects pledges from several users towards an item for the recipient.
+// Pledged coins are held here until the purchase completes or the pledges are returned.
+type GroupPurchase struct {
+	gorm.Model
+	OrganizerId uint `gorm:"index"`
+	Organizer   User `gorm:"foreignKey:OrganizerId"`
+	RecipientId uint
+	Recipient   User `gorm:"foreignKey:RecipientId"`
+	ItemID      uint
+	Item        Item
+	VariantID   uint
+	Variant     ItemVariant `gorm:"foreignKey:VariantID;constraint:-"`
+	// Price is fixed when the group purchase is created.
+	Price       uint
+	Collected   uint
+	Status      string `gorm:"default:open;index"`
+	Deadline    time.Time
+	CompletedAt *time.Time
+	Pledges     []Pledge
+}
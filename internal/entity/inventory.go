@@ -4,9 +4,12 @@ import "gorm.io/gorm"
 
 type InventoryItem struct {
 	gorm.Model
-	UserID   uint `gorm:"index:entry,unique"`
-	User     User
-	ItemID   uint `gorm:"index:entry,unique"`
-	Item     Item
-	Quantity uint
+	UserID uint `gorm:"index:inventory_entry,unique"`
+	User   User
+	ItemID uint `gorm:"index:inventory_entry,unique"`
+	Item   Item
+	// VariantID is 0 for items without variants, so it has no foreign key constraint.
+	VariantID uint        `gorm:"index:inventory_entry,unique;default:0"`
+	Variant   ItemVariant `gorm:"foreignKey:VariantID;constraint:-"`
+	Quantity  uint
 }
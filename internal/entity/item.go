@@ -4,6 +4,14 @@ import "gorm.io/gorm"
 
 type Item struct {
 	gorm.Model
-	Name  string `gorm:"uniqueIndex:item_name"`
+	Name string `gorm:"uniqueIndex:item_name"`
+	// Price is the price before any entry in the price history took effect.
 	Price uint
+	// Category is the name of one of the categories, or empty for uncategorized items.
+	Category string `gorm:"index"`
+	Tags     []ItemTag
+	// Stock is nil for items that are never sold out.
+	Stock *uint
+	// PurchaseLimit caps how many units one user may buy; 0 means no limit.
+	PurchaseLimit uint
 }
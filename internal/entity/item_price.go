@@ -0,0 +1,16 @@
+package entity
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+// ItemPrice is an entry in the price history of an item. The price in effect at a given
+// moment is the one with the latest EffectiveFrom not after it; entries in the future are scheduled changes.
+type ItemPrice struct {
+	gorm.Model
+	ItemID        uint `gorm:"index:item_price_effective"`
+	Item          Item
+	Price         uint
+	EffectiveFrom time.Time `gorm:"index:item_price_effective"`
+}
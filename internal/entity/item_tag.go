@@ -0,0 +1,9 @@
+package entity
+
+import "gorm.io/gorm"
+
+type ItemTag struct {
+	gorm.Model
+	ItemID uint   `gorm:"uniqueIndex:item_tag"`
+	Tag    string `gorm:"uniqueIndex:item_tag;index"`
+}
@@ -0,0 +1,17 @@
+package entity
+
+import "gorm.io/gorm"
+
+type ItemTransfer struct {
+	gorm.Model
+	FromId    uint `gorm:"index"`
+	FromUser  User `gorm:"foreignKey:FromId"`
+	ToId      uint `gorm:"index"`
+	ToUser    User `gorm:"foreignKey:ToId"`
+	ItemID    uint
+	Item      Item
+	VariantID uint
+	Variant   ItemVariant `gorm:"foreignKey:VariantID;constraint:-"`
+	Quantity  uint
+	Gift      bool
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "gorm.io/gorm"
+
+type ItemVariant struct {
+	gorm.Model
+	ItemID uint `gorm:"uniqueIndex:item_variant"`
+	Item   Item
+	Name   string `gorm:"uniqueIndex:item_variant"`
+	// PriceDelta is added to the item price and may be negative.
+	PriceDelta int
+	// Stock is nil for variants that are never sold out.
+	Stock *uint
+}
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+const (
+	ThrottleUser = "user"
+	ThrottleIP   = "ip"
+)
+
+// LoginThrottle counts recent failed sign-ins for a username or a client IP.
+type LoginThrottle struct {
+	Kind          string `gorm:"primaryKey"`
+	Value         string `gorm:"primaryKey"`
+	Failures      uint
+	LastFailureAt time.Time `gorm:"index"`
+}
@@ -0,0 +1,14 @@
+package entity
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type Notification struct {
+	gorm.Model
+	UserID  uint `gorm:"index"`
+	User    User
+	Message string
+	ReadAt  *time.Time
+}
@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// OIDCLogin keeps the secrets of an SSO sign-in between redirecting the user to the identity
+// provider and the provider redirecting back. StateHash is the SHA-256 of the state parameter.
+type OIDCLogin struct {
+	StateHash    string `gorm:"primaryKey"`
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time `gorm:"index"`
+}
@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	PaymentRequestPending  = "pending"
+	PaymentRequestAccepted = "accepted"
+	PaymentRequestDeclined = "declined"
+	PaymentRequestExpired  = "expired"
+)
+
+type PaymentRequest struct {
+	gorm.Model
+	RequesterId   uint `gorm:"index"`
+	Requester     User `gorm:"foreignKey:RequesterId"`
+	PayerId       uint `gorm:"index"`
+	Payer         User `gorm:"foreignKey:PayerId"`
+	Amount        uint
+	Comment       string
+	Status        string `gorm:"default:pending"`
+	ExpiresAt     time.Time
+	TransactionId *uint
+}
@@ -0,0 +1,11 @@
+package entity
+
+import "gorm.io/gorm"
+
+type Pledge struct {
+	gorm.Model
+	GroupPurchaseID uint `gorm:"index"`
+	UserID          uint `gorm:"index"`
+	User            User
+	Amount          uint
+}
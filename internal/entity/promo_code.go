@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	DiscountPercent = "percent"
+	DiscountFixed   = "fixed"
+)
+
+type PromoCode struct {
+	gorm.Model
+	Code         string `gorm:"uniqueIndex:promo_code"`
+	DiscountType string
+	Discount     uint
+	// ItemID and Category restrict the code to one item or one category; both empty means any item.
+	ItemID   *uint
+	Item     *Item
+	Category string
+	// MaxRedemptions and MaxPerUser are 0 when unlimited.
+	MaxRedemptions uint
+	MaxPerUser     uint
+	Redemptions    uint
+	StartsAt       *time.Time
+	EndsAt         *time.Time
+}
@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	FulfillmentPlaced    = "placed"
+	FulfillmentPacked    = "packed"
+	FulfillmentReady     = "ready"
+	FulfillmentDelivered = "delivered"
+	FulfillmentCancelled = "cancelled"
+)
+
+type Purchase struct {
+	gorm.Model
+	BuyerId     uint `gorm:"index"`
+	Buyer       User `gorm:"foreignKey:BuyerId"`
+	OwnerId     uint `gorm:"index"`
+	Owner       User `gorm:"foreignKey:OwnerId"`
+	ItemID      uint
+	Item        Item
+	VariantID   uint
+	Variant     ItemVariant `gorm:"foreignKey:VariantID;constraint:-"`
+	Price       uint
+	Discount    uint
+	PromoCodeID *uint `gorm:"index"`
+	PromoCode   *PromoCode
+	// GroupPurchaseID is set for items paid for by several users; refunds go back to them.
+	GroupPurchaseID *uint
+	// WalletID is set for items paid from a team wallet; refunds go back to the wallet.
+	WalletID          *uint
+	RefundedAt        *time.Time
+	RefundedById      *uint
+	FulfillmentStatus string `gorm:"default:placed;index"`
+	PackedAt          *time.Time
+	ReadyAt           *time.Time
+	DeliveredAt       *time.Time
+	CancelledAt       *time.Time
+}
@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// RecoveryCode is a single-use code that replaces the authenticator code of a two-factor sign-in.
+type RecoveryCode struct {
+	ID       uint   `gorm:"primaryKey"`
+	UserID   uint   `gorm:"index"`
+	CodeHash string `gorm:"index"`
+	UsedAt   *time.Time
+}
@@ -0,0 +1,10 @@
+package entity
+
+import "gorm.io/gorm"
+
+type Team struct {
+	gorm.Model
+	Name       string `gorm:"uniqueIndex:team_name"`
+	Department string `gorm:"index"`
+	Members    []TeamMember
+}
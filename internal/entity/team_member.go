@@ -0,0 +1,12 @@
+package entity
+
+import "gorm.io/gorm"
+
+type TeamMember struct {
+	gorm.Model
+	TeamID uint `gorm:"uniqueIndex:team_member"`
+	UserID uint `gorm:"uniqueIndex:team_member;index"`
+	User   User
+	// Manager lets the member read the team's stats.
+	Manager bool
+}
@@ -9,4 +9,8 @@ type Transaction struct {
 	ToId     uint
 	ToUser   User `gorm:"foreignKey:ToId"`
 	Amount   uint
+
+	// WalletID is set when FromUser paid the coins out of a team wallet.
+	WalletID *uint
+	Wallet   *Wallet
 }
@@ -1,10 +1,43 @@
 package entity
 
-import "gorm.io/gorm"
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	RoleUser        = "user"
+	RoleAdmin       = "admin"
+	RoleFulfillment = "fulfillment"
+	// RoleService is the role of service accounts, which sign in with API keys only.
+	RoleService = "service"
+)
 
 type User struct {
 	gorm.Model
 	Name         string `gorm:"uniqueIndex:user_name"`
 	PasswordHash string
-	Balance      uint `gorm:"default:1000"`
+	Balance      uint   `gorm:"default:1000"`
+	Role         string `gorm:"default:user"`
+	// LeaderboardOptOut hides the user from leaderboards.
+	LeaderboardOptOut bool `gorm:"default:false"`
+	// DisplayName is shown instead of the login name when set.
+	DisplayName string
+	AvatarURL   string
+	Department  string
+	Bio         string
+	// SessionVersion is embedded in issued tokens; bumping it signs the user out everywhere.
+	SessionVersion uint `gorm:"default:0"`
+	// ResetTokenHash is the SHA-256 of the one-time password reset token issued by an admin.
+	ResetTokenHash      string `gorm:"index"`
+	ResetTokenExpiresAt *time.Time
+	// TOTPSecret is the base32 secret of the user's authenticator; it is pending until TOTPEnabled is set.
+	TOTPSecret  string
+	TOTPEnabled bool `gorm:"default:false"`
+	// TOTPLastStep is the period of the last accepted code, so that a code cannot be used twice.
+	TOTPLastStep int64 `gorm:"default:0"`
+
+	Email string `gorm:"index"`
+	// OIDCSubject is the identity provider's id of the user once they signed in with SSO.
+	OIDCSubject *string `gorm:"column:oidc_subject;uniqueIndex"`
 }
@@ -0,0 +1,11 @@
+package entity
+
+import "gorm.io/gorm"
+
+type UserBadge struct {
+	gorm.Model
+	UserID      uint   `gorm:"uniqueIndex:user_badge"`
+	Badge       string `gorm:"uniqueIndex:user_badge"`
+	Description string
+	Bonus       uint
+}
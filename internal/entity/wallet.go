@@ -0,0 +1,11 @@
+package entity
+
+import "gorm.io/gorm"
+
+// Wallet is a pooled balance shared by the members of a team.
+type Wallet struct {
+	gorm.Model
+	Name    string `gorm:"uniqueIndex:wallet_name"`
+	Balance uint
+	Members []WalletMember
+}
@@ -0,0 +1,17 @@
+package entity
+
+import "gorm.io/gorm"
+
+const (
+	WalletOwner   = "owner"
+	WalletSpender = "spender"
+	WalletViewer  = "viewer"
+)
+
+type WalletMember struct {
+	gorm.Model
+	WalletID uint `gorm:"uniqueIndex:wallet_member"`
+	UserID   uint `gorm:"uniqueIndex:wallet_member;index"`
+	User     User
+	Role     string
+}
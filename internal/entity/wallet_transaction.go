@@ -0,0 +1,30 @@
+package entity
+
+import "gorm.io/gorm"
+
+const (
+	WalletDeposit  = "deposit"
+	WalletSend     = "send"
+	WalletPurchase = "purchase"
+	WalletRefund   = "refund"
+)
+
+// WalletTransaction records a movement of coins into or out of a wallet.
+// UserID is the user who made it; ToId is the user who received coins or an item.
+// PurchaseID links purchases and their refunds.
+type WalletTransaction struct {
+	gorm.Model
+	WalletID  uint `gorm:"index"`
+	Type      string
+	UserID    uint
+	User      User
+	ToId      *uint
+	ToUser    *User `gorm:"foreignKey:ToId"`
+	Amount    uint
+	ItemID    *uint
+	Item      *Item
+	VariantID uint
+	Variant   ItemVariant `gorm:"foreignKey:VariantID;constraint:-"`
+
+	PurchaseID *uint
+}
@@ -0,0 +1,13 @@
+package entity
+
+import "gorm.io/gorm"
+
+type WishlistItem struct {
+	gorm.Model
+	UserID    uint `gorm:"uniqueIndex:wishlist_entry"`
+	User      User
+	ItemID    uint `gorm:"uniqueIndex:wishlist_entry"`
+	Item      Item
+	VariantID uint        `gorm:"uniqueIndex:wishlist_entry;default:0"`
+	Variant   ItemVariant `gorm:"foreignKey:VariantID;constraint:-"`
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"merch_shop/internal/middleware"
+	"merch_shop/internal/model"
+	"net/http"
+)
+
+type achievementService interface {
+	GetAchievements(userId uint) ([]model.Achievement, error)
+	EvaluateAll() (int, error)
+}
+
+type AchievementHandler struct {
+	achievementService achievementService
+}
+
+func NewAchievementHandler(achievementService achievementService) *AchievementHandler {
+	return &AchievementHandler{achievementService}
+}
+
+func (handler *AchievementHandler) Routes(c *gin.RouterGroup) {
+	c.GET("/achievements", handler.GetAchievements)
+}
+
+func (handler *AchievementHandler) AdminRoutes(c *gin.RouterGroup) {
+	c.POST("/achievements/evaluate", handler.EvaluateAll)
+}
+
+func (h AchievementHandler) GetAchievements(c *gin.Context) {
+	claims, _ := middleware.GetUser(c)
+	response, err := h.achievementService.GetAchievements(claims.UserId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h AchievementHandler) EvaluateAll(c *gin.Context) {
+	awarded, err := h.achievementService.EvaluateAll()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.AchievementEvaluation{Awarded: awarded})
+}
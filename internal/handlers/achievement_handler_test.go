@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merch_shop/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type MockAchievementService struct {
+	mock.Mock
+}
+
+func (m *MockAchievementService) GetAchievements(userId uint) ([]model.Achievement, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]model.Achievement), args.Error(1)
+}
+
+func (m *MockAchievementService) EvaluateAll() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func TestAchievementHandler_GetAchievements(t *testing.T) {
+	c, w := createTestContext()
+	setUserContext(c, 1)
+	c.Request = httptest.NewRequest("GET", "/achievements", nil)
+
+	mockService := new(MockAchievementService)
+	mockService.On("GetAchievements", uint(1)).Return([]model.Achievement{{Badge: "generous", Metric: "coins_sent", Threshold: 1000}}, nil)
+
+	handler := NewAchievementHandler(mockService)
+	handler.GetAchievements(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"badge":"generous"`)
+}
+
+func TestAchievementHandler_EvaluateAll(t *testing.T) {
+	c, w := createTestContext()
+	c.Request = httptest.NewRequest("POST", "/achievements/evaluate", nil)
+
+	mockService := new(MockAchievementService)
+	mockService.On("EvaluateAll").Return(3, nil)
+
+	handler := NewAchievementHandler(mockService)
+	handler.EvaluateAll(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"awarded":3}`, w.Body.String())
+}
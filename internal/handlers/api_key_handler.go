@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"merch_shop/internal/model"
+	"net/http"
+	"strconv"
+)
+
+type apiKeyService interface {
+	CreateServiceAccount(request model.ServiceAccountRequest) (model.ServiceAccount, error)
+	GetServiceAccount(name string) (model.ServiceAccount, error)
+	FundServiceAccount(name string, amount uint) error
+	CreateAPIKey(accountName string, request model.APIKeyRequest) (model.NewAPIKey, error)
+	RotateAPIKey(id uint) (model.NewAPIKey, error)
+	RevokeAPIKey(id uint) error
+}
+
+type APIKeyHandler struct {
+	apiKeyService apiKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService apiKeyService) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyService}
+}
+
+func (handler *APIKeyHandler) AdminRoutes(c *gin.RouterGroup) {
+	c.POST("/serviceAccounts", handler.CreateServiceAccount)
+	c.GET("/serviceAccounts/:name", handler.GetServiceAccount)
+	c.POST("/serviceAccounts/:name/fund", handler.FundServiceAccount)
+	c.POST("/serviceAccounts/:name/apiKeys", handler.CreateAPIKey)
+	c.POST("/apiKeys/:id/rotate", handler.RotateAPIKey)
+	c.DELETE("/apiKeys/:id", handler.RevokeAPIKey)
+}
+
+func (h APIKeyHandler) CreateServiceAccount(c *gin.Context) {
+	var request model.ServiceAccountRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	response, err := h.apiKeyService.CreateServiceAccount(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h APIKeyHandler) GetServiceAccount(c *gin.Context) {
+	response, err := h.apiKeyService.GetServiceAccount(c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h APIKeyHandler) FundServiceAccount(c *gin.Context) {
+	var request model.ServiceAccountFundRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	if err := h.apiKeyService.FundServiceAccount(c.Param("name"), request.Amount); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func (h APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var request model.APIKeyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	response, err := h.apiKeyService.CreateAPIKey(c.Param("name"), request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h APIKeyHandler) RotateAPIKey(c *gin.Context) {
+	keyId, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "api key id is not valid"})
+		return
+	}
+	response, err := h.apiKeyService.RotateAPIKey(uint(keyId))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	keyId, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "api key id is not valid"})
+		return
+	}
+	if err := h.apiKeyService.RevokeAPIKey(uint(keyId)); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merch_shop/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type MockAPIKeyService struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyService) CreateServiceAccount(request model.ServiceAccountRequest) (model.ServiceAccount, error) {
+	args := m.Called(request)
+	return args.Get(0).(model.ServiceAccount), args.Error(1)
+}
+
+func (m *MockAPIKeyService) GetServiceAccount(name string) (model.ServiceAccount, error) {
+	args := m.Called(name)
+	return args.Get(0).(model.ServiceAccount), args.Error(1)
+}
+
+func (m *MockAPIKeyService) FundServiceAccount(name string, amount uint) error {
+	args := m.Called(name, amount)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyService) CreateAPIKey(accountName string, request model.APIKeyRequest) (model.NewAPIKey, error) {
+	args := m.Called(accountName, request)
+	return args.Get(0).(model.NewAPIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyService) RotateAPIKey(id uint) (model.NewAPIKey, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.NewAPIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyService) RevokeAPIKey(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func TestAPIKeyHandler_CreateAPIKey(t *testing.T) {
+	c, w := createTestContext()
+	c.Params = gin.Params{{Key: "name", Value: "hr-bot"}}
+	c.Request = httptest.NewRequest("POST", "/admin/serviceAccounts/hr-bot/apiKeys",
+		strings.NewReader(`{"name":"payroll","scopes":["coins:grant"]}`))
+
+	mockService := new(MockAPIKeyService)
+	mockService.On("CreateAPIKey", "hr-bot", model.APIKeyRequest{Name: "payroll", Scopes: []string{"coins:grant"}}).
+		Return(model.NewAPIKey{APIKey: model.APIKey{ID: 1, Name: "payroll"}, Key: "msk_secret"}, nil)
+
+	handler := NewAPIKeyHandler(mockService)
+	handler.CreateAPIKey(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"key":"msk_secret"`)
+	assert.Contains(t, w.Body.String(), `"name":"payroll"`)
+}
+
+func TestAPIKeyHandler_RevokeAPIKey(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		c, w := createTestContext()
+		c.Params = gin.Params{{Key: "id", Value: "3"}}
+
+		mockService := new(MockAPIKeyService)
+		mockService.On("RevokeAPIKey", uint(3)).Return(nil)
+
+		handler := NewAPIKeyHandler(mockService)
+		handler.RevokeAPIKey(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("InvalidId", func(t *testing.T) {
+		c, w := createTestContext()
+		c.Params = gin.Params{{Key: "id", Value: "abc"}}
+
+		handler := NewAPIKeyHandler(new(MockAPIKeyService))
+		handler.RevokeAPIKey(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "api key id is not valid")
+	})
+}
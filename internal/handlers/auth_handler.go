@@ -1,13 +1,29 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
+	"math"
+	"merch_shop/internal/middleware"
 	"merch_shop/internal/model"
+	"merch_shop/internal/service"
 	"net/http"
 )
 
 type userService interface {
-	Authenticate(username, password string) (string, error)
+	Authenticate(username, password, ip string) (model.AuthResponse, error)
+	VerifyTwoFactor(challengeToken string, code string, ip string) (string, error)
+	ChangePassword(userId uint, oldPassword string, newPassword string, ip string) (string, error)
+	IssueResetToken(userName string) (model.PasswordResetToken, error)
+	ResetPassword(token string, newPassword string) (string, error)
+	GetLockouts() ([]model.Lockout, error)
+	ClearLockout(kind string, value string) error
+	GetTwoFactorStatus(userId uint) (model.TwoFactorStatus, error)
+	BeginTwoFactorEnrolment(userId uint) (model.TwoFactorEnrolment, error)
+	ConfirmTwoFactor(userId uint, code string) (model.RecoveryCodes, error)
+	RegenerateRecoveryCodes(userId uint, code string) (model.RecoveryCodes, error)
+	DisableTwoFactor(userId uint, password string, code string, ip string) error
 }
 
 type AuthHandler struct {
@@ -22,6 +38,23 @@ func NewAuthHandler(userService userService) AuthHandler {
 
 func (handler *AuthHandler) Routes(c *gin.RouterGroup) {
 	c.POST("/auth", handler.Authenticate)
+	c.POST("/auth/reset", handler.ResetPassword)
+	c.POST("/auth/twoFactor", handler.VerifyTwoFactor)
+}
+
+func (handler *AuthHandler) ProtectedRoutes(c *gin.RouterGroup) {
+	c.POST("/password", handler.ChangePassword)
+	c.GET("/twoFactor", handler.GetTwoFactorStatus)
+	c.POST("/twoFactor", handler.BeginTwoFactorEnrolment)
+	c.POST("/twoFactor/confirm", handler.ConfirmTwoFactor)
+	c.POST("/twoFactor/recoveryCodes", handler.RegenerateRecoveryCodes)
+	c.POST("/twoFactor/disable", handler.DisableTwoFactor)
+}
+
+func (handler *AuthHandler) AdminRoutes(c *gin.RouterGroup) {
+	c.POST("/users/:name/passwordReset", handler.IssueResetToken)
+	c.GET("/lockouts", handler.GetLockouts)
+	c.DELETE("/lockouts/:kind/:value", handler.ClearLockout)
 }
 
 func (h AuthHandler) Authenticate(c *gin.Context) {
@@ -30,10 +63,160 @@ func (h AuthHandler) Authenticate(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, model.ErrorResponse{"Required fields are empty or not valid"})
 		return
 	}
-	token, err := h.userService.Authenticate(request.Username, request.Password)
+	response, err := h.userService.Authenticate(request.Username, request.Password, c.ClientIP())
+	if tooManyAttempts(c, err) {
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, model.ErrorResponse{err.Error()})
 		return
 	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h AuthHandler) VerifyTwoFactor(c *gin.Context) {
+	var request model.TwoFactorRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	token, err := h.userService.VerifyTwoFactor(request.ChallengeToken, request.Code, c.ClientIP())
+	if tooManyAttempts(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.AuthResponse{Token: token})
+}
+
+// tooManyAttempts responds with 429 and reports true when err refuses a sign-in attempt.
+func tooManyAttempts(c *gin.Context, err error) bool {
+	var tooManyAttempts *service.TooManyAttemptsError
+	if !errors.As(err, &tooManyAttempts) {
+		return false
+	}
+	c.Header("Retry-After", fmt.Sprint(int(math.Ceil(tooManyAttempts.RetryAfter.Seconds()))))
+	c.JSON(http.StatusTooManyRequests, model.ErrorResponse{Errors: err.Error()})
+	return true
+}
+
+func (h AuthHandler) ChangePassword(c *gin.Context) {
+	var request model.PasswordChangeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	claims, _ := middleware.GetUser(c)
+	token, err := h.userService.ChangePassword(claims.UserId, request.OldPassword, request.NewPassword, c.ClientIP())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.AuthResponse{Token: token})
+}
+
+func (h AuthHandler) IssueResetToken(c *gin.Context) {
+	response, err := h.userService.IssueResetToken(c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h AuthHandler) ResetPassword(c *gin.Context) {
+	var request model.PasswordResetRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	token, err := h.userService.ResetPassword(request.Token, request.NewPassword)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
 	c.JSON(http.StatusOK, model.AuthResponse{Token: token})
 }
+
+func (h AuthHandler) GetLockouts(c *gin.Context) {
+	response, err := h.userService.GetLockouts()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h AuthHandler) ClearLockout(c *gin.Context) {
+	if err := h.userService.ClearLockout(c.Param("kind"), c.Param("value")); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func (h AuthHandler) GetTwoFactorStatus(c *gin.Context) {
+	claims, _ := middleware.GetUser(c)
+	response, err := h.userService.GetTwoFactorStatus(claims.UserId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h AuthHandler) BeginTwoFactorEnrolment(c *gin.Context) {
+	claims, _ := middleware.GetUser(c)
+	response, err := h.userService.BeginTwoFactorEnrolment(claims.UserId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h AuthHandler) ConfirmTwoFactor(c *gin.Context) {
+	var request model.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	claims, _ := middleware.GetUser(c)
+	response, err := h.userService.ConfirmTwoFactor(claims.UserId, request.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h AuthHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var request model.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	claims, _ := middleware.GetUser(c)
+	response, err := h.userService.RegenerateRecoveryCodes(claims.UserId, request.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h AuthHandler) DisableTwoFactor(c *gin.Context) {
+	var request model.TwoFactorDisableRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	claims, _ := middleware.GetUser(c)
+	if err := h.userService.DisableTwoFactor(claims.UserId, request.Password, request.Code, c.ClientIP()); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.Status(http.StatusOK)
+}
@@ -5,22 +5,80 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"merch_shop/internal/model"
 	"merch_shop/internal/provider"
+	"merch_shop/internal/service"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 type MockUserService struct {
 	mock.Mock
 }
 
-func (m *MockUserService) Authenticate(username, password string) (string, error) {
-	args := m.Called(username, password)
+func (m *MockUserService) Authenticate(username, password, ip string) (model.AuthResponse, error) {
+	args := m.Called(username, password, ip)
+	return args.Get(0).(model.AuthResponse), args.Error(1)
+}
+
+func (m *MockUserService) VerifyTwoFactor(challengeToken string, code string, ip string) (string, error) {
+	args := m.Called(challengeToken, code, ip)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockUserService) ChangePassword(userId uint, oldPassword string, newPassword string, ip string) (string, error) {
+	args := m.Called(userId, oldPassword, newPassword, ip)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockUserService) IssueResetToken(userName string) (model.PasswordResetToken, error) {
+	args := m.Called(userName)
+	return args.Get(0).(model.PasswordResetToken), args.Error(1)
+}
+
+func (m *MockUserService) ResetPassword(token string, newPassword string) (string, error) {
+	args := m.Called(token, newPassword)
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockUserService) GetLockouts() ([]model.Lockout, error) {
+	args := m.Called()
+	return args.Get(0).([]model.Lockout), args.Error(1)
+}
+
+func (m *MockUserService) ClearLockout(kind string, value string) error {
+	args := m.Called(kind, value)
+	return args.Error(0)
+}
+
+func (m *MockUserService) GetTwoFactorStatus(userId uint) (model.TwoFactorStatus, error) {
+	args := m.Called(userId)
+	return args.Get(0).(model.TwoFactorStatus), args.Error(1)
+}
+
+func (m *MockUserService) BeginTwoFactorEnrolment(userId uint) (model.TwoFactorEnrolment, error) {
+	args := m.Called(userId)
+	return args.Get(0).(model.TwoFactorEnrolment), args.Error(1)
+}
+
+func (m *MockUserService) ConfirmTwoFactor(userId uint, code string) (model.RecoveryCodes, error) {
+	args := m.Called(userId, code)
+	return args.Get(0).(model.RecoveryCodes), args.Error(1)
+}
+
+func (m *MockUserService) RegenerateRecoveryCodes(userId uint, code string) (model.RecoveryCodes, error) {
+	args := m.Called(userId, code)
+	return args.Get(0).(model.RecoveryCodes), args.Error(1)
+}
+
+func (m *MockUserService) DisableTwoFactor(userId uint, password string, code string, ip string) error {
+	args := m.Called(userId, password, code, ip)
+	return args.Error(0)
+}
+
 // Test Helpers
 
 func createTestContext() (*gin.Context, *httptest.ResponseRecorder) {
@@ -41,7 +99,7 @@ func TestAuthHandler_Authenticate(t *testing.T) {
 			strings.NewReader(`{"username":"alice","password":"secret"}`))
 
 		mockService := new(MockUserService)
-		mockService.On("Authenticate", "alice", "secret").Return("token123", nil)
+		mockService.On("Authenticate", "alice", "secret", "192.0.2.1").Return(model.AuthResponse{Token: "token123"}, nil)
 
 		handler := NewAuthHandler(mockService)
 		handler.Authenticate(c)
@@ -68,7 +126,7 @@ func TestAuthHandler_Authenticate(t *testing.T) {
 			strings.NewReader(`{"username":"bob","password":"wrong"}`))
 
 		mockService := new(MockUserService)
-		mockService.On("Authenticate", "bob", "wrong").Return("", errors.New("invalid credentials"))
+		mockService.On("Authenticate", "bob", "wrong", "192.0.2.1").Return(model.AuthResponse{}, errors.New("invalid credentials"))
 
 		handler := NewAuthHandler(mockService)
 		handler.Authenticate(c)
@@ -76,4 +134,80 @@ func TestAuthHandler_Authenticate(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		assert.Contains(t, w.Body.String(), "invalid credentials")
 	})
+
+	t.Run("TooManyAttempts", func(t *testing.T) {
+		c, w := createTestContext()
+		c.Request = httptest.NewRequest("POST", "/auth",
+			strings.NewReader(`{"username":"bob","password":"wrong"}`))
+
+		mockService := new(MockUserService)
+		mockService.On("Authenticate", "bob", "wrong", "192.0.2.1").
+			Return(model.AuthResponse{}, &service.TooManyAttemptsError{RetryAfter: 1500 * time.Millisecond})
+
+		handler := NewAuthHandler(mockService)
+		handler.Authenticate(c)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "2", w.Header().Get("Retry-After"))
+		assert.Contains(t, w.Body.String(), "too many failed attempts")
+	})
+}
+
+func TestAuthHandler_VerifyTwoFactor(t *testing.T) {
+	c, w := createTestContext()
+	c.Request = httptest.NewRequest("POST", "/auth/twoFactor",
+		strings.NewReader(`{"challengeToken":"challenge","code":"123456"}`))
+
+	mockService := new(MockUserService)
+	mockService.On("VerifyTwoFactor", "challenge", "123456", "192.0.2.1").Return("token789", nil)
+
+	handler := NewAuthHandler(mockService)
+	handler.VerifyTwoFactor(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"token":"token789"`)
+}
+
+func TestAuthHandler_ClearLockout(t *testing.T) {
+	c, w := createTestContext()
+	c.Params = gin.Params{{Key: "kind", Value: "user"}, {Key: "value", Value: "bob"}}
+
+	mockService := new(MockUserService)
+	mockService.On("ClearLockout", "user", "bob").Return(nil)
+
+	handler := NewAuthHandler(mockService)
+	handler.ClearLockout(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthHandler_ChangePassword(t *testing.T) {
+	c, w := createTestContext()
+	setUserContext(c, 1)
+	c.Request = httptest.NewRequest("POST", "/password",
+		strings.NewReader(`{"oldPassword":"secret","newPassword":"better secret"}`))
+
+	mockService := new(MockUserService)
+	mockService.On("ChangePassword", uint(1), "secret", "better secret", "192.0.2.1").Return("token456", nil)
+
+	handler := NewAuthHandler(mockService)
+	handler.ChangePassword(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"token":"token456"`)
+}
+
+func TestAuthHandler_ResetPassword(t *testing.T) {
+	c, w := createTestContext()
+	c.Request = httptest.NewRequest("POST", "/auth/reset",
+		strings.NewReader(`{"token":"abc"}`))
+
+	mockService := new(MockUserService)
+
+	handler := NewAuthHandler(mockService)
+	handler.ResetPassword(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "ResetPassword", mock.Anything, mock.Anything)
 }
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"merch_shop/internal/model"
+	"net/http"
+	"time"
+)
+
+type catalogService interface {
+	GetItems(category string, tags []string) ([]model.Item, error)
+	GetCategories() ([]model.Category, error)
+	CreateCategory(name string) (model.Category, error)
+	SetItemCategory(name string, category string) error
+	SetItemTags(name string, tags []string) error
+	GetPriceHistory(name string) ([]model.ItemPrice, error)
+	SchedulePrice(name string, price uint, effectiveFrom *time.Time) (model.ItemPrice, error)
+	Restock(name string, variant string, quantity uint, purchaseLimit *uint) (model.Item, error)
+	AddVariant(name string, variant model.ItemVariant) (model.ItemVariant, error)
+}
+
+type CatalogHandler struct {
+	catalogService catalogService
+}
+
+func NewCatalogHandler(catalogService catalogService) *CatalogHandler {
+	return &CatalogHandler{catalogService}
+}
+
+func (handler *CatalogHandler) Routes(c *gin.RouterGroup) {
+	c.GET("/items", handler.GetItems)
+	c.GET("/categories", handler.GetCategories)
+	c.GET("/items/:name/prices", handler.GetPriceHistory)
+}
+
+func (handler *CatalogHandler) AdminRoutes(c *gin.RouterGroup) {
+	c.POST("/items/:name/restock", handler.Restock)
+	c.POST("/items/:name/variants", handler.AddVariant)
+	c.POST("/items/:name/prices", handler.SchedulePrice)
+	c.PUT("/items/:name/category", handler.SetItemCategory)
+	c.PUT("/items/:name/tags", handler.SetItemTags)
+	c.POST("/categories", handler.CreateCategory)
+}
+
+func (h CatalogHandler) GetItems(c *gin.Context) {
+	response, err := h.catalogService.GetItems(c.Query("category"), c.QueryArray("tag"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h CatalogHandler) GetCategories(c *gin.Context) {
+	response, err := h.catalogService.GetCategories()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h CatalogHandler) CreateCategory(c *gin.Context) {
+	var request model.Category
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	response, err := h.catalogService.CreateCategory(request.Name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h CatalogHandler) SetItemCategory(c *gin.Context) {
+	var request model.ItemCategoryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	if err := h.catalogService.SetItemCategory(c.Param("name"), request.Category); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func (h CatalogHandler) SetItemTags(c *gin.Context) {
+	var request model.ItemTagsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	if err := h.catalogService.SetItemTags(c.Param("name"), request.Tags); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func (h CatalogHandler) GetPriceHistory(c *gin.Context) {
+	response, err := h.catalogService.GetPriceHistory(c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h CatalogHandler) SchedulePrice(c *gin.Context) {
+	var request model.SchedulePriceRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	response, err := h.catalogService.SchedulePrice(c.Param("name"), request.Price, request.EffectiveFrom)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h CatalogHandler) Restock(c *gin.Context) {
+	var request model.RestockRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	response, err := h.catalogService.Restock(c.Param("name"), request.Variant, request.Quantity, request.PurchaseLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h CatalogHandler) AddVariant(c *gin.Context) {
+	var request model.ItemVariant
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	response, err := h.catalogService.AddVariant(c.Param("name"), request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merch_shop/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type MockCatalogService struct {
+	mock.Mock
+}
+
+func (m *MockCatalogService) GetItems(category string, tags []string) ([]model.Item, error) {
+	args := m.Called(category, tags)
+	return args.Get(0).([]model.Item), args.Error(1)
+}
+
+func (m *MockCatalogService) GetCategories() ([]model.Category, error) {
+	args := m.Called()
+	return args.Get(0).([]model.Category), args.Error(1)
+}
+
+func (m *MockCatalogService) CreateCategory(name string) (model.Category, error) {
+	args := m.Called(name)
+	return args.Get(0).(model.Category), args.Error(1)
+}
+
+func (m *MockCatalogService) SetItemCategory(name string, category string) error {
+	args := m.Called(name, category)
+	return args.Error(0)
+}
+
+func (m *MockCatalogService) SetItemTags(name string, tags []string) error {
+	args := m.Called(name, tags)
+	return args.Error(0)
+}
+
+func (m *MockCatalogService) GetPriceHistory(name string) ([]model.ItemPrice, error) {
+	args := m.Called(name)
+	return args.Get(0).([]model.ItemPrice), args.Error(1)
+}
+
+func (m *MockCatalogService) SchedulePrice(name string, price uint, effectiveFrom *time.Time) (model.ItemPrice, error) {
+	args := m.Called(name, price, effectiveFrom)
+	return args.Get(0).(model.ItemPrice), args.Error(1)
+}
+
+func (m *MockCatalogService) Restock(name string, variant string, quantity uint, purchaseLimit *uint) (model.Item, error) {
+	args := m.Called(name, variant, quantity, purchaseLimit)
+	return args.Get(0).(model.Item), args.Error(1)
+}
+
+func (m *MockCatalogService) AddVariant(name string, variant model.ItemVariant) (model.ItemVariant, error) {
+	args := m.Called(name, variant)
+	return args.Get(0).(model.ItemVariant), args.Error(1)
+}
+
+func TestCatalogHandler_Restock(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		c, w := createTestContext()
+		c.Params = gin.Params{{Key: "name", Value: "pink-hoody"}}
+		c.Request = httptest.NewRequest("POST", "/admin/items/pink-hoody/restock",
+			strings.NewReader(`{"quantity":40,"purchaseLimit":2}`))
+
+		stock := uint(40)
+		mockService := new(MockCatalogService)
+		mockService.On("Restock", "pink-hoody", "", uint(40), mock.MatchedBy(func(limit *uint) bool {
+			return limit != nil && *limit == 2
+		})).Return(model.Item{Name: "pink-hoody", Stock: &stock, PurchaseLimit: 2}, nil)
+
+		handler := NewCatalogHandler(mockService)
+		handler.Restock(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"stock":40`)
+	})
+
+	t.Run("ServiceError", func(t *testing.T) {
+		c, w := createTestContext()
+		c.Params = gin.Params{{Key: "name", Value: "unicorn"}}
+		c.Request = httptest.NewRequest("POST", "/admin/items/unicorn/restock", strings.NewReader(`{"quantity":1}`))
+
+		mockService := new(MockCatalogService)
+		mockService.On("Restock", "unicorn", "", uint(1), (*uint)(nil)).Return(model.Item{}, errors.New("item not found"))
+
+		handler := NewCatalogHandler(mockService)
+		handler.Restock(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "item not found")
+	})
+}
+
+func TestCatalogHandler_AddVariant(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		c, w := createTestContext()
+		c.Params = gin.Params{{Key: "name", Value: "hoody"}}
+		c.Request = httptest.NewRequest("POST", "/admin/items/hoody/variants",
+			strings.NewReader(`{"name":"XL","priceDelta":20}`))
+
+		variant := model.ItemVariant{Name: "XL", PriceDelta: 20}
+		mockService := new(MockCatalogService)
+		mockService.On("AddVariant", "hoody", variant).Return(variant, nil)
+
+		handler := NewCatalogHandler(mockService)
+		handler.AddVariant(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"priceDelta":20`)
+	})
+
+	t.Run("MissingName", func(t *testing.T) {
+		c, w := createTestContext()
+		c.Params = gin.Params{{Key: "name", Value: "hoody"}}
+		c.Request = httptest.NewRequest("POST", "/admin/items/hoody/variants", strings.NewReader(`{"priceDelta":20}`))
+
+		handler := NewCatalogHandler(new(MockCatalogService))
+		handler.AddVariant(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestCatalogHandler_SchedulePrice(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Params = gin.Params{{Key: "name", Value: "cup"}}
+		c.Request = httptest.NewRequest("POST", "/items/cup/prices",
+			strings.NewReader(`{"price":30,"effectiveFrom":"2025-06-01T00:00:00Z"}`))
+
+		effectiveFrom := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+		mockService := new(MockCatalogService)
+		mockService.On("SchedulePrice", "cup", uint(30), mock.MatchedBy(func(at *time.Time) bool {
+			return at.Equal(effectiveFrom)
+		})).Return(model.ItemPrice{Price: 30, EffectiveFrom: effectiveFrom}, nil)
+
+		handler := NewCatalogHandler(mockService)
+		handler.SchedulePrice(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"price":30`)
+	})
+
+	t.Run("MissingPrice", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Params = gin.Params{{Key: "name", Value: "cup"}}
+		c.Request = httptest.NewRequest("POST", "/items/cup/prices", strings.NewReader(`{}`))
+
+		mockService := new(MockCatalogService)
+
+		handler := NewCatalogHandler(mockService)
+		handler.SchedulePrice(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "SchedulePrice", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestCatalogHandler_GetPriceHistory(t *testing.T) {
+	c, w := createTestContext()
+	setUserContext(c, 1)
+	c.Params = gin.Params{{Key: "name", Value: "cup"}}
+
+	mockService := new(MockCatalogService)
+	mockService.On("GetPriceHistory", "cup").Return([]model.ItemPrice{}, errors.New("item not found"))
+
+	handler := NewCatalogHandler(mockService)
+	handler.GetPriceHistory(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "item not found")
+}
+
+func TestCatalogHandler_GetItems(t *testing.T) {
+	c, w := createTestContext()
+	setUserContext(c, 1)
+	c.Request = httptest.NewRequest("GET", "/items?category=apparel&tag=new&tag=sale", nil)
+
+	mockService := new(MockCatalogService)
+	mockService.On("GetItems", "apparel", []string{"new", "sale"}).
+		Return([]model.Item{{Name: "hoody", Price: 300, Category: "apparel", Tags: []string{"new", "sale"}}}, nil)
+
+	handler := NewCatalogHandler(mockService)
+	handler.GetItems(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"tags":["new","sale"]`)
+}
+
+func TestCatalogHandler_SetItemTags(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Params = gin.Params{{Key: "name", Value: "hoody"}}
+		c.Request = httptest.NewRequest("PUT", "/items/hoody/tags", strings.NewReader(`{"tags":["new"]}`))
+
+		mockService := new(MockCatalogService)
+		mockService.On("SetItemTags", "hoody", []string{"new"}).Return(nil)
+
+		handler := NewCatalogHandler(mockService)
+		handler.SetItemTags(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("MissingTags", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Params = gin.Params{{Key: "name", Value: "hoody"}}
+		c.Request = httptest.NewRequest("PUT", "/items/hoody/tags", strings.NewReader(`{}`))
+
+		mockService := new(MockCatalogService)
+
+		handler := NewCatalogHandler(mockService)
+		handler.SetItemTags(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "SetItemTags", mock.Anything, mock.Anything)
+	})
+}
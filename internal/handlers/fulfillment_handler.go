@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"merch_shop/internal/middleware"
+	"merch_shop/internal/model"
+	"net/http"
+	"strconv"
+)
+
+type fulfillmentService interface {
+	GetQueue() ([]model.FulfillmentQueue, error)
+	AdvanceOrder(purchaseId uint, status string) (model.Purchase, error)
+	CancelOrder(userId uint, purchaseId uint) (model.Refund, error)
+}
+
+type FulfillmentHandler struct {
+	fulfillmentService fulfillmentService
+}
+
+func NewFulfillmentHandler(fulfillmentService fulfillmentService) *FulfillmentHandler {
+	return &FulfillmentHandler{fulfillmentService}
+}
+
+func (handler *FulfillmentHandler) Routes(c *gin.RouterGroup) {
+	c.GET("/queue", handler.GetQueue)
+	c.POST("/orders/:id/status", handler.AdvanceOrder)
+	c.POST("/orders/:id/cancel", handler.CancelOrder)
+}
+
+func (h FulfillmentHandler) GetQueue(c *gin.Context) {
+	response, err := h.fulfillmentService.GetQueue()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h FulfillmentHandler) AdvanceOrder(c *gin.Context) {
+	purchaseId, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "purchase id is not valid"})
+		return
+	}
+	var request model.FulfillmentStatusRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	response, err := h.fulfillmentService.AdvanceOrder(uint(purchaseId), request.Status)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h FulfillmentHandler) CancelOrder(c *gin.Context) {
+	purchaseId, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "purchase id is not valid"})
+		return
+	}
+	claims, _ := middleware.GetUser(c)
+	response, err := h.fulfillmentService.CancelOrder(claims.UserId, uint(purchaseId))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
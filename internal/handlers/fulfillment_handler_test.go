@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merch_shop/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type MockFulfillmentService struct {
+	mock.Mock
+}
+
+func (m *MockFulfillmentService) GetQueue() ([]model.FulfillmentQueue, error) {
+	args := m.Called()
+	return args.Get(0).([]model.FulfillmentQueue), args.Error(1)
+}
+
+func (m *MockFulfillmentService) AdvanceOrder(purchaseId uint, status string) (model.Purchase, error) {
+	args := m.Called(purchaseId, status)
+	return args.Get(0).(model.Purchase), args.Error(1)
+}
+
+func (m *MockFulfillmentService) CancelOrder(userId uint, purchaseId uint) (model.Refund, error) {
+	args := m.Called(userId, purchaseId)
+	return args.Get(0).(model.Refund), args.Error(1)
+}
+
+func TestFulfillmentHandler_AdvanceOrder(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Params = gin.Params{{Key: "id", Value: "9"}}
+		c.Request = httptest.NewRequest("POST", "/orders/9/status", strings.NewReader(`{"status":"packed"}`))
+
+		mockService := new(MockFulfillmentService)
+		mockService.On("AdvanceOrder", uint(9), "packed").Return(model.Purchase{Id: 9, Status: "packed"}, nil)
+
+		handler := NewFulfillmentHandler(mockService)
+		handler.AdvanceOrder(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"packed"`)
+	})
+
+	t.Run("UnknownStatus", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Params = gin.Params{{Key: "id", Value: "9"}}
+		c.Request = httptest.NewRequest("POST", "/orders/9/status", strings.NewReader(`{"status":"lost"}`))
+
+		mockService := new(MockFulfillmentService)
+
+		handler := NewFulfillmentHandler(mockService)
+		handler.AdvanceOrder(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "AdvanceOrder", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFulfillmentHandler_CancelOrder(t *testing.T) {
+	c, w := createTestContext()
+	setUserContext(c, 1)
+	c.Params = gin.Params{{Key: "id", Value: "9"}}
+	c.Request = httptest.NewRequest("POST", "/orders/9/cancel", nil)
+
+	mockService := new(MockFulfillmentService)
+	mockService.On("CancelOrder", uint(1), uint(9)).Return(model.Refund{}, errors.New("order is already delivered"))
+
+	handler := NewFulfillmentHandler(mockService)
+	handler.CancelOrder(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "already delivered")
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"merch_shop/internal/middleware"
+	"merch_shop/internal/model"
+	"net/http"
+	"strconv"
+)
+
+type groupPurchaseService interface {
+	GetGroupPurchases() ([]model.GroupPurchase, error)
+	GetGroupPurchase(groupPurchaseId uint) (model.GroupPurchase, error)
+	CreateGroupPurchase(userId uint, request model.GroupPurchaseRequest) (model.GroupPurchase, error)
+	Pledge(userId uint, groupPurchaseId uint, amount uint) (model.GroupPurchase, error)
+	CancelGroupPurchase(userId uint, groupPurchaseId uint) error
+}
+
+type GroupPurchaseHandler struct {
+	groupPurchaseService groupPurchaseService
+}
+
+func NewGroupPurchaseHandler(groupPurchaseService groupPurchaseService) *GroupPurchaseHandler {
+	return &GroupPurchaseHandler{groupPurchaseService}
+}
+
+func (handler *GroupPurchaseHandler) Routes(c *gin.RouterGroup) {
+	c.GET("/groupPurchases", handler.GetGroupPurchases)
+	c.POST("/groupPurchases", handler.CreateGroupPurchase)
+	c.GET("/groupPurchases/:id", handler.GetGroupPurchase)
+	c.POST("/groupPurchases/:id/pledge", handler.Pledge)
+	c.POST("/groupPurchases/:id/cancel", handler.CancelGroupPurchase)
+}
+
+func (h GroupPurchaseHandler) GetGroupPurchases(c *gin.Context) {
+	response, err := h.groupPurchaseService.GetGroupPurchases()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h GroupPurchaseHandler) GetGroupPurchase(c *gin.Context) {
+	groupPurchaseId, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "group purchase id is not valid"})
+		return
+	}
+	response, err := h.groupPurchaseService.GetGroupPurchase(uint(groupPurchaseId))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h GroupPurchaseHandler) CreateGroupPurchase(c *gin.Context) {
+	var request model.GroupPurchaseRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	claims, _ := middleware.GetUser(c)
+	response, err := h.groupPurchaseService.CreateGroupPurchase(claims.UserId, request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h GroupPurchaseHandler) Pledge(c *gin.Context) {
+	groupPurchaseId, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "group purchase id is not valid"})
+		return
+	}
+	var request model.PledgeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	claims, _ := middleware.GetUser(c)
+	response, err := h.groupPurchaseService.Pledge(claims.UserId, uint(groupPurchaseId), request.Amount)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h GroupPurchaseHandler) CancelGroupPurchase(c *gin.Context) {
+	groupPurchaseId, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "group purchase id is not valid"})
+		return
+	}
+	claims, _ := middleware.GetUser(c)
+	if err := h.groupPurchaseService.CancelGroupPurchase(claims.UserId, uint(groupPurchaseId)); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merch_shop/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type MockGroupPurchaseService struct {
+	mock.Mock
+}
+
+func (m *MockGroupPurchaseService) GetGroupPurchases() ([]model.GroupPurchase, error) {
+	args := m.Called()
+	return args.Get(0).([]model.GroupPurchase), args.Error(1)
+}
+
+func (m *MockGroupPurchaseService) GetGroupPurchase(groupPurchaseId uint) (model.GroupPurchase, error) {
+	args := m.Called(groupPurchaseId)
+	return args.Get(0).(model.GroupPurchase), args.Error(1)
+}
+
+func (m *MockGroupPurchaseService) CreateGroupPurchase(userId uint, request model.GroupPurchaseRequest) (model.GroupPurchase, error) {
+	args := m.Called(userId, request)
+	return args.Get(0).(model.GroupPurchase), args.Error(1)
+}
+
+func (m *MockGroupPurchaseService) Pledge(userId uint, groupPurchaseId uint, amount uint) (model.GroupPurchase, error) {
+	args := m.Called(userId, groupPurchaseId, amount)
+	return args.Get(0).(model.GroupPurchase), args.Error(1)
+}
+
+func (m *MockGroupPurchaseService) CancelGroupPurchase(userId uint, groupPurchaseId uint) error {
+	args := m.Called(userId, groupPurchaseId)
+	return args.Error(0)
+}
+
+func TestGroupPurchaseHandler_CreateGroupPurchase(t *testing.T) {
+	t.Run("MissingDeadline", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Request = httptest.NewRequest("POST", "/groupPurchases", strings.NewReader(`{"item":"hoody","toUser":"bob"}`))
+
+		mockService := new(MockGroupPurchaseService)
+
+		handler := NewGroupPurchaseHandler(mockService)
+		handler.CreateGroupPurchase(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "CreateGroupPurchase", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Request = httptest.NewRequest("POST", "/groupPurchases",
+			strings.NewReader(`{"item":"hoody","toUser":"bob","deadline":"2025-03-08T00:00:00Z"}`))
+
+		mockService := new(MockGroupPurchaseService)
+		mockService.On("CreateGroupPurchase", uint(1), mock.MatchedBy(func(request model.GroupPurchaseRequest) bool {
+			return request.Item == "hoody" && request.ToUser == "bob"
+		})).Return(model.GroupPurchase{Id: 7, Status: "open"}, nil)
+
+		handler := NewGroupPurchaseHandler(mockService)
+		handler.CreateGroupPurchase(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"id":7`)
+	})
+}
+
+func TestGroupPurchaseHandler_Pledge(t *testing.T) {
+	t.Run("InvalidId", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Params = gin.Params{{Key: "id", Value: "abc"}}
+		c.Request = httptest.NewRequest("POST", "/groupPurchases/abc/pledge", strings.NewReader(`{"amount":100}`))
+
+		mockService := new(MockGroupPurchaseService)
+
+		handler := NewGroupPurchaseHandler(mockService)
+		handler.Pledge(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "group purchase id is not valid")
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 2)
+		c.Params = gin.Params{{Key: "id", Value: "7"}}
+		c.Request = httptest.NewRequest("POST", "/groupPurchases/7/pledge", strings.NewReader(`{"amount":100}`))
+
+		mockService := new(MockGroupPurchaseService)
+		mockService.On("Pledge", uint(2), uint(7), uint(100)).Return(model.GroupPurchase{Id: 7, Collected: 100}, nil)
+
+		handler := NewGroupPurchaseHandler(mockService)
+		handler.Pledge(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"merch_shop/internal/middleware"
+	"merch_shop/internal/model"
+	"net/http"
+)
+
+type leaderboardService interface {
+	GetLeaderboard(metric string, period string, limit int) (model.Leaderboard, error)
+	SetLeaderboardVisibility(userId uint, hidden bool) error
+}
+
+type LeaderboardHandler struct {
+	leaderboardService leaderboardService
+}
+
+func NewLeaderboardHandler(leaderboardService leaderboardService) *LeaderboardHandler {
+	return &LeaderboardHandler{leaderboardService}
+}
+
+func (handler *LeaderboardHandler) Routes(c *gin.RouterGroup) {
+	c.GET("/leaderboards/:metric", handler.GetLeaderboard)
+	c.PUT("/leaderboards/visibility", handler.SetLeaderboardVisibility)
+}
+
+func (h LeaderboardHandler) GetLeaderboard(c *gin.Context) {
+	query := model.LeaderboardQuery{Period: "month", Limit: 10}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	response, err := h.leaderboardService.GetLeaderboard(c.Param("metric"), query.Period, query.Limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h LeaderboardHandler) SetLeaderboardVisibility(c *gin.Context) {
+	var request model.LeaderboardVisibilityRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	claims, _ := middleware.GetUser(c)
+	if err := h.leaderboardService.SetLeaderboardVisibility(claims.UserId, *request.Hidden); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merch_shop/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type MockLeaderboardService struct {
+	mock.Mock
+}
+
+func (m *MockLeaderboardService) GetLeaderboard(metric string, period string, limit int) (model.Leaderboard, error) {
+	args := m.Called(metric, period, limit)
+	return args.Get(0).(model.Leaderboard), args.Error(1)
+}
+
+func (m *MockLeaderboardService) SetLeaderboardVisibility(userId uint, hidden bool) error {
+	args := m.Called(userId, hidden)
+	return args.Error(0)
+}
+
+func TestLeaderboardHandler_GetLeaderboard(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		c, w := createTestContext()
+		c.Params = gin.Params{{Key: "metric", Value: "received"}}
+		c.Request = httptest.NewRequest("GET", "/leaderboards/received", nil)
+
+		mockService := new(MockLeaderboardService)
+		mockService.On("GetLeaderboard", "received", "month", 10).Return(model.Leaderboard{Metric: "received"}, nil)
+
+		handler := NewLeaderboardHandler(mockService)
+		handler.GetLeaderboard(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("UnknownPeriod", func(t *testing.T) {
+		c, w := createTestContext()
+		c.Params = gin.Params{{Key: "metric", Value: "received"}}
+		c.Request = httptest.NewRequest("GET", "/leaderboards/received?period=year", nil)
+
+		mockService := new(MockLeaderboardService)
+
+		handler := NewLeaderboardHandler(mockService)
+		handler.GetLeaderboard(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "GetLeaderboard", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestLeaderboardHandler_SetLeaderboardVisibility(t *testing.T) {
+	c, w := createTestContext()
+	setUserContext(c, 1)
+	c.Request = httptest.NewRequest("PUT", "/leaderboards/visibility", strings.NewReader(`{"hidden":false}`))
+
+	mockService := new(MockLeaderboardService)
+	mockService.On("SetLeaderboardVisibility", uint(1), false).Return(nil)
+
+	handler := NewLeaderboardHandler(mockService)
+	handler.SetLeaderboardVisibility(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
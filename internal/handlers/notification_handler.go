@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"merch_shop/internal/middleware"
+	"merch_shop/internal/model"
+	"net/http"
+	"strconv"
+)
+
+type notificationService interface {
+	GetNotifications(userId uint) ([]model.Notification, error)
+	MarkNotificationRead(userId uint, notificationId uint) error
+}
+
+type NotificationHandler struct {
+	notificationService notificationService
+}
+
+func NewNotificationHandler(notificationService notificationService) *NotificationHandler {
+	return &NotificationHandler{notificationService}
+}
+
+func (handler *NotificationHandler) Routes(c *gin.RouterGroup) {
+	c.GET("/notifications", handler.GetNotifications)
+	c.POST("/notifications/:id/read", handler.MarkNotificationRead)
+}
+
+func (h NotificationHandler) GetNotifications(c *gin.Context) {
+	claims, _ := middleware.GetUser(c)
+	response, err := h.notificationService.GetNotifications(claims.UserId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	notificationId, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "notification id is not valid"})
+		return
+	}
+	claims, _ := middleware.GetUser(c)
+	if err := h.notificationService.MarkNotificationRead(claims.UserId, uint(notificationId)); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.Status(http.StatusOK)
+}
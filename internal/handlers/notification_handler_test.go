@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merch_shop/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type MockNotificationService struct {
+	mock.Mock
+}
+
+func (m *MockNotificationService) GetNotifications(userId uint) ([]model.Notification, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]model.Notification), args.Error(1)
+}
+
+func (m *MockNotificationService) MarkNotificationRead(userId uint, notificationId uint) error {
+	args := m.Called(userId, notificationId)
+	return args.Error(0)
+}
+
+func TestNotificationHandler_GetNotifications(t *testing.T) {
+	c, w := createTestContext()
+	setUserContext(c, 1)
+	c.Request = httptest.NewRequest("GET", "/notifications", nil)
+
+	mockService := new(MockNotificationService)
+	mockService.On("GetNotifications", uint(1)).Return([]model.Notification{{Id: 7, Message: "Badge earned"}}, nil)
+
+	handler := NewNotificationHandler(mockService)
+	handler.GetNotifications(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []model.Notification
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Badge earned", response[0].Message)
+}
+
+func TestNotificationHandler_MarkNotificationRead(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Params = gin.Params{{Key: "id", Value: "7"}}
+		c.Request = httptest.NewRequest("POST", "/notifications/7/read", nil)
+
+		mockService := new(MockNotificationService)
+		mockService.On("MarkNotificationRead", uint(1), uint(7)).Return(nil)
+
+		handler := NewNotificationHandler(mockService)
+		handler.MarkNotificationRead(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Params = gin.Params{{Key: "id", Value: "8"}}
+		c.Request = httptest.NewRequest("POST", "/notifications/8/read", nil)
+
+		mockService := new(MockNotificationService)
+		mockService.On("MarkNotificationRead", uint(1), uint(8)).Return(errors.New("notification not found"))
+
+		handler := NewNotificationHandler(mockService)
+		handler.MarkNotificationRead(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "notification not found")
+	})
+
+	t.Run("InvalidId", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Params = gin.Params{{Key: "id", Value: "abc"}}
+		c.Request = httptest.NewRequest("POST", "/notifications/abc/read", nil)
+
+		mockService := new(MockNotificationService)
+
+		handler := NewNotificationHandler(mockService)
+		handler.MarkNotificationRead(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "MarkNotificationRead", mock.Anything, mock.Anything)
+	})
+}
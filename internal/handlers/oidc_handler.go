@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"merch_shop/internal/model"
+	"net/http"
+)
+
+type oidcService interface {
+	BeginLogin() (model.OIDCLogin, error)
+	CompleteLogin(state string, code string) (model.AuthResponse, error)
+}
+
+type OIDCHandler struct {
+	oidcService oidcService
+}
+
+func NewOIDCHandler(oidcService oidcService) *OIDCHandler {
+	return &OIDCHandler{oidcService}
+}
+
+func (handler *OIDCHandler) Routes(c *gin.RouterGroup) {
+	c.GET("/auth/oidc/login", handler.BeginLogin)
+	c.GET("/auth/oidc/callback", handler.CompleteLogin)
+}
+
+func (h OIDCHandler) BeginLogin(c *gin.Context) {
+	response, err := h.oidcService.BeginLogin()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// CompleteLogin handles the redirect back from the identity provider.
+func (h OIDCHandler) CompleteLogin(c *gin.Context) {
+	if providerError := c.Query("error"); providerError != "" {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "sign-in was declined: " + providerError})
+		return
+	}
+	var query model.OIDCCallbackQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	response, err := h.oidcService.CompleteLogin(query.State, query.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merch_shop/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type MockOIDCService struct {
+	mock.Mock
+}
+
+func (m *MockOIDCService) BeginLogin() (model.OIDCLogin, error) {
+	args := m.Called()
+	return args.Get(0).(model.OIDCLogin), args.Error(1)
+}
+
+func (m *MockOIDCService) CompleteLogin(state string, code string) (model.AuthResponse, error) {
+	args := m.Called(state, code)
+	return args.Get(0).(model.AuthResponse), args.Error(1)
+}
+
+func TestOIDCHandler_BeginLogin(t *testing.T) {
+	c, w := createTestContext()
+	c.Request = httptest.NewRequest("GET", "/auth/oidc/login", nil)
+
+	mockService := new(MockOIDCService)
+	mockService.On("BeginLogin").Return(model.OIDCLogin{AuthorizationURL: "https://idp.example.com/authorize"}, nil)
+
+	handler := NewOIDCHandler(mockService)
+	handler.BeginLogin(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"authorizationUrl":"https://idp.example.com/authorize"}`, w.Body.String())
+}
+
+func TestOIDCHandler_CompleteLogin(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		c, w := createTestContext()
+		c.Request = httptest.NewRequest("GET", "/auth/oidc/callback?code=abc&state=xyz", nil)
+
+		mockService := new(MockOIDCService)
+		mockService.On("CompleteLogin", "xyz", "abc").Return(model.AuthResponse{Token: "token"}, nil)
+
+		handler := NewOIDCHandler(mockService)
+		handler.CompleteLogin(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"token":"token"}`, w.Body.String())
+	})
+
+	t.Run("ProviderError", func(t *testing.T) {
+		c, w := createTestContext()
+		c.Request = httptest.NewRequest("GET", "/auth/oidc/callback?error=access_denied&state=xyz", nil)
+
+		mockService := new(MockOIDCService)
+		handler := NewOIDCHandler(mockService)
+		handler.CompleteLogin(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"errors":"sign-in was declined: access_denied"}`, w.Body.String())
+		mockService.AssertNotCalled(t, "CompleteLogin", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ServiceError", func(t *testing.T) {
+		c, w := createTestContext()
+		c.Request = httptest.NewRequest("GET", "/auth/oidc/callback?code=abc&state=xyz", nil)
+
+		mockService := new(MockOIDCService)
+		mockService.On("CompleteLogin", "xyz", "abc").Return(model.AuthResponse{}, errors.New("sign-in is invalid or expired"))
+
+		handler := NewOIDCHandler(mockService)
+		handler.CompleteLogin(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"errors":"sign-in is invalid or expired"}`, w.Body.String())
+	})
+}
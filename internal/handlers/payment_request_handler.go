@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"merch_shop/internal/middleware"
+	"merch_shop/internal/model"
+	"net/http"
+	"strconv"
+)
+
+type paymentRequestService interface {
+	RequestCoin(userId uint, fromUser string, amount uint, comment string) (model.PaymentRequest, error)
+	GetPaymentRequests(userId uint) (model.PaymentRequestsResponse, error)
+	AcceptPaymentRequest(userId uint, requestId uint) error
+	DeclinePaymentRequest(userId uint, requestId uint) error
+}
+
+type PaymentRequestHandler struct {
+	paymentRequestService paymentRequestService
+}
+
+func NewPaymentRequestHandler(paymentRequestService paymentRequestService) *PaymentRequestHandler {
+	return &PaymentRequestHandler{paymentRequestService}
+}
+
+func (handler *PaymentRequestHandler) Routes(c *gin.RouterGroup) {
+	c.GET("/paymentRequests", handler.GetPaymentRequests)
+	c.POST("/paymentRequests", handler.RequestCoin)
+	c.POST("/paymentRequests/:id/accept", handler.AcceptPaymentRequest)
+	c.POST("/paymentRequests/:id/decline", handler.DeclinePaymentRequest)
+}
+
+func (h PaymentRequestHandler) RequestCoin(c *gin.Context) {
+	var request model.RequestCoinRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+
+	claims, _ := middleware.GetUser(c)
+	response, err := h.paymentRequestService.RequestCoin(claims.UserId, request.FromUser, request.Amount, request.Comment)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h PaymentRequestHandler) GetPaymentRequests(c *gin.Context) {
+	claims, _ := middleware.GetUser(c)
+	response, err := h.paymentRequestService.GetPaymentRequests(claims.UserId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h PaymentRequestHandler) AcceptPaymentRequest(c *gin.Context) {
+	requestId, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "payment request id is not valid"})
+		return
+	}
+	claims, _ := middleware.GetUser(c)
+	if err := h.paymentRequestService.AcceptPaymentRequest(claims.UserId, uint(requestId)); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func (h PaymentRequestHandler) DeclinePaymentRequest(c *gin.Context) {
+	requestId, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "payment request id is not valid"})
+		return
+	}
+	claims, _ := middleware.GetUser(c)
+	if err := h.paymentRequestService.DeclinePaymentRequest(claims.UserId, uint(requestId)); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merch_shop/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type MockPaymentRequestService struct {
+	mock.Mock
+}
+
+func (m *MockPaymentRequestService) RequestCoin(userId uint, fromUser string, amount uint, comment string) (model.PaymentRequest, error) {
+	args := m.Called(userId, fromUser, amount, comment)
+	return args.Get(0).(model.PaymentRequest), args.Error(1)
+}
+
+func (m *MockPaymentRequestService) GetPaymentRequests(userId uint) (model.PaymentRequestsResponse, error) {
+	args := m.Called(userId)
+	return args.Get(0).(model.PaymentRequestsResponse), args.Error(1)
+}
+
+func (m *MockPaymentRequestService) AcceptPaymentRequest(userId uint, requestId uint) error {
+	args := m.Called(userId, requestId)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRequestService) DeclinePaymentRequest(userId uint, requestId uint) error {
+	args := m.Called(userId, requestId)
+	return args.Error(0)
+}
+
+func TestPaymentRequestHandler_RequestCoin(t *testing.T) {
+	t.Run("ValidRequest", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Request = httptest.NewRequest("POST", "/paymentRequests",
+			strings.NewReader(`{"fromUser":"bob","amount":50,"comment":"pizza"}`))
+
+		mockService := new(MockPaymentRequestService)
+		mockService.On("RequestCoin", uint(1), "bob", uint(50), "pizza").
+			Return(model.PaymentRequest{Id: 7, ToUser: "bob", Amount: 50}, nil)
+
+		handler := NewPaymentRequestHandler(mockService)
+		handler.RequestCoin(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"id":7`)
+	})
+
+	t.Run("InvalidRequest", func(t *testing.T) {
+		c, w := createTestContext()
+		c.Request = httptest.NewRequest("POST", "/paymentRequests",
+			strings.NewReader(`{"amount":50}`))
+
+		handler := NewPaymentRequestHandler(new(MockPaymentRequestService))
+		handler.RequestCoin(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Required fields")
+	})
+}
+
+func TestPaymentRequestHandler_AcceptPaymentRequest(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 2)
+		c.Params = gin.Params{{Key: "id", Value: "7"}}
+
+		mockService := new(MockPaymentRequestService)
+		mockService.On("AcceptPaymentRequest", uint(2), uint(7)).Return(nil)
+
+		handler := NewPaymentRequestHandler(mockService)
+		handler.AcceptPaymentRequest(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("InvalidId", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 2)
+		c.Params = gin.Params{{Key: "id", Value: "abc"}}
+
+		handler := NewPaymentRequestHandler(new(MockPaymentRequestService))
+		handler.AcceptPaymentRequest(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("ServiceError", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 2)
+		c.Params = gin.Params{{Key: "id", Value: "7"}}
+
+		mockService := new(MockPaymentRequestService)
+		mockService.On("AcceptPaymentRequest", uint(2), uint(7)).Return(errors.New("payment request expired"))
+
+		handler := NewPaymentRequestHandler(mockService)
+		handler.AcceptPaymentRequest(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "payment request expired")
+	})
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"merch_shop/internal/middleware"
+	"merch_shop/internal/model"
+	"net/http"
+)
+
+type profileService interface {
+	GetMe(userId uint) (model.Profile, error)
+	GetProfile(name string) (model.Profile, error)
+	UpdateProfile(userId uint, request model.ProfileRequest) (model.Profile, error)
+	SearchUsers(userId uint, query string, limit int) ([]model.Profile, error)
+}
+
+type ProfileHandler struct {
+	profileService profileService
+}
+
+func NewProfileHandler(profileService profileService) *ProfileHandler {
+	return &ProfileHandler{profileService}
+}
+
+func (handler *ProfileHandler) Routes(c *gin.RouterGroup) {
+	c.GET("/me", handler.GetMe)
+	c.PATCH("/me", handler.UpdateProfile)
+	c.GET("/users/:name", handler.GetProfile)
+}
+
+// SearchRoutes should be registered on a rate-limited group.
+func (handler *ProfileHandler) SearchRoutes(c *gin.RouterGroup) {
+	c.GET("/users", handler.SearchUsers)
+}
+
+func (h ProfileHandler) GetMe(c *gin.Context) {
+	claims, _ := middleware.GetUser(c)
+	response, err := h.profileService.GetMe(claims.UserId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h ProfileHandler) UpdateProfile(c *gin.Context) {
+	var request model.ProfileRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	claims, _ := middleware.GetUser(c)
+	response, err := h.profileService.UpdateProfile(claims.UserId, request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h ProfileHandler) GetProfile(c *gin.Context) {
+	response, err := h.profileService.GetProfile(c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h ProfileHandler) SearchUsers(c *gin.Context) {
+	query := model.UserSearchQuery{Limit: 10}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	claims, _ := middleware.GetUser(c)
+	response, err := h.profileService.SearchUsers(claims.UserId, query.Query, query.Limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
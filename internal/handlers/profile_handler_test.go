@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merch_shop/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type MockProfileService struct {
+	mock.Mock
+}
+
+func (m *MockProfileService) GetMe(userId uint) (model.Profile, error) {
+	args := m.Called(userId)
+	return args.Get(0).(model.Profile), args.Error(1)
+}
+
+func (m *MockProfileService) GetProfile(name string) (model.Profile, error) {
+	args := m.Called(name)
+	return args.Get(0).(model.Profile), args.Error(1)
+}
+
+func (m *MockProfileService) UpdateProfile(userId uint, request model.ProfileRequest) (model.Profile, error) {
+	args := m.Called(userId, request)
+	return args.Get(0).(model.Profile), args.Error(1)
+}
+
+func (m *MockProfileService) SearchUsers(userId uint, query string, limit int) ([]model.Profile, error) {
+	args := m.Called(userId, query, limit)
+	return args.Get(0).([]model.Profile), args.Error(1)
+}
+
+func TestProfileHandler_UpdateProfile(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Request = httptest.NewRequest("PATCH", "/me", strings.NewReader(`{"displayName":"John Smith"}`))
+
+		mockService := new(MockProfileService)
+		mockService.On("UpdateProfile", uint(1), mock.MatchedBy(func(request model.ProfileRequest) bool {
+			return *request.DisplayName == "John Smith" && request.Bio == nil
+		})).Return(model.Profile{Name: "jsmith42", DisplayName: "John Smith"}, nil)
+
+		handler := NewProfileHandler(mockService)
+		handler.UpdateProfile(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"name":"jsmith42","displayName":"John Smith"}`, w.Body.String())
+	})
+
+	t.Run("BioTooLong", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Request = httptest.NewRequest("PATCH", "/me", strings.NewReader(`{"bio":"`+strings.Repeat("a", 501)+`"}`))
+
+		mockService := new(MockProfileService)
+
+		handler := NewProfileHandler(mockService)
+		handler.UpdateProfile(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "UpdateProfile", mock.Anything, mock.Anything)
+	})
+}
+
+func TestProfileHandler_SearchUsers(t *testing.T) {
+	t.Run("DefaultLimit", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Request = httptest.NewRequest("GET", "/users?query=jsm", nil)
+
+		mockService := new(MockProfileService)
+		mockService.On("SearchUsers", uint(1), "jsm", 10).Return([]model.Profile{{Name: "jsmith42", DisplayName: "John Smith"}}, nil)
+
+		handler := NewProfileHandler(mockService)
+		handler.SearchUsers(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[{"name":"jsmith42","displayName":"John Smith"}]`, w.Body.String())
+	})
+
+	t.Run("MissingQuery", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Request = httptest.NewRequest("GET", "/users?limit=50", nil)
+
+		mockService := new(MockProfileService)
+
+		handler := NewProfileHandler(mockService)
+		handler.SearchUsers(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "SearchUsers", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
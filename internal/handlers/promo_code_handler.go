@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"merch_shop/internal/model"
+	"net/http"
+)
+
+type promoCodeService interface {
+	CreatePromoCode(request model.PromoCode) (model.PromoCode, error)
+	GetPromoCodes() ([]model.PromoCode, error)
+}
+
+type PromoCodeHandler struct {
+	promoCodeService promoCodeService
+}
+
+func NewPromoCodeHandler(promoCodeService promoCodeService) *PromoCodeHandler {
+	return &PromoCodeHandler{promoCodeService}
+}
+
+func (handler *PromoCodeHandler) AdminRoutes(c *gin.RouterGroup) {
+	c.GET("/promoCodes", handler.GetPromoCodes)
+	c.POST("/promoCodes", handler.CreatePromoCode)
+}
+
+func (h PromoCodeHandler) GetPromoCodes(c *gin.Context) {
+	response, err := h.promoCodeService.GetPromoCodes()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h PromoCodeHandler) CreatePromoCode(c *gin.Context) {
+	var request model.PromoCode
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	response, err := h.promoCodeService.CreatePromoCode(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merch_shop/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type MockPromoCodeService struct {
+	mock.Mock
+}
+
+func (m *MockPromoCodeService) CreatePromoCode(request model.PromoCode) (model.PromoCode, error) {
+	args := m.Called(request)
+	return args.Get(0).(model.PromoCode), args.Error(1)
+}
+
+func (m *MockPromoCodeService) GetPromoCodes() ([]model.PromoCode, error) {
+	args := m.Called()
+	return args.Get(0).([]model.PromoCode), args.Error(1)
+}
+
+func TestPromoCodeHandler_CreatePromoCode(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Request = httptest.NewRequest("POST", "/promoCodes",
+			strings.NewReader(`{"code":"SUMMER","discountType":"percent","discount":20,"category":"apparel"}`))
+
+		request := model.PromoCode{Code: "SUMMER", DiscountType: "percent", Discount: 20, Category: "apparel"}
+		mockService := new(MockPromoCodeService)
+		mockService.On("CreatePromoCode", request).Return(request, nil)
+
+		handler := NewPromoCodeHandler(mockService)
+		handler.CreatePromoCode(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"SUMMER"`)
+	})
+
+	t.Run("InvalidDiscountType", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Request = httptest.NewRequest("POST", "/promoCodes",
+			strings.NewReader(`{"code":"SUMMER","discountType":"free","discount":20}`))
+
+		mockService := new(MockPromoCodeService)
+
+		handler := NewPromoCodeHandler(mockService)
+		handler.CreatePromoCode(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "CreatePromoCode", mock.Anything)
+	})
+}
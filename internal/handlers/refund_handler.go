@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"merch_shop/internal/middleware"
+	"merch_shop/internal/model"
+	"net/http"
+	"strconv"
+)
+
+type refundService interface {
+	GetPurchases(userId uint) ([]model.Purchase, error)
+	GetUserPurchases(userName string) ([]model.Purchase, error)
+	ReturnItem(userId uint, name string, variant string) (model.Refund, error)
+	RefundPurchase(adminId uint, purchaseId uint) (model.Refund, error)
+}
+
+type RefundHandler struct {
+	refundService refundService
+}
+
+func NewRefundHandler(refundService refundService) *RefundHandler {
+	return &RefundHandler{refundService}
+}
+
+func (handler *RefundHandler) Routes(c *gin.RouterGroup) {
+	c.GET("/purchases", handler.GetPurchases)
+	c.POST("/purchases/return", handler.ReturnItem)
+}
+
+func (handler *RefundHandler) AdminRoutes(c *gin.RouterGroup) {
+	c.GET("/users/:name/purchases", handler.GetUserPurchases)
+	c.POST("/purchases/:id/refund", handler.RefundPurchase)
+}
+
+func (h RefundHandler) GetPurchases(c *gin.Context) {
+	claims, _ := middleware.GetUser(c)
+	response, err := h.refundService.GetPurchases(claims.UserId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h RefundHandler) ReturnItem(c *gin.Context) {
+	var request model.ReturnItemRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	claims, _ := middleware.GetUser(c)
+	response, err := h.refundService.ReturnItem(claims.UserId, request.Item, request.Variant)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h RefundHandler) GetUserPurchases(c *gin.Context) {
+	response, err := h.refundService.GetUserPurchases(c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h RefundHandler) RefundPurchase(c *gin.Context) {
+	purchaseId, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "purchase id is not valid"})
+		return
+	}
+	claims, _ := middleware.GetUser(c)
+	response, err := h.refundService.RefundPurchase(claims.UserId, uint(purchaseId))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merch_shop/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type MockRefundService struct {
+	mock.Mock
+}
+
+func (m *MockRefundService) GetPurchases(userId uint) ([]model.Purchase, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]model.Purchase), args.Error(1)
+}
+
+func (m *MockRefundService) GetUserPurchases(userName string) ([]model.Purchase, error) {
+	args := m.Called(userName)
+	return args.Get(0).([]model.Purchase), args.Error(1)
+}
+
+func (m *MockRefundService) ReturnItem(userId uint, name string, variant string) (model.Refund, error) {
+	args := m.Called(userId, name, variant)
+	return args.Get(0).(model.Refund), args.Error(1)
+}
+
+func (m *MockRefundService) RefundPurchase(adminId uint, purchaseId uint) (model.Refund, error) {
+	args := m.Called(adminId, purchaseId)
+	return args.Get(0).(model.Refund), args.Error(1)
+}
+
+func TestRefundHandler_ReturnItem(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Request = httptest.NewRequest("POST", "/purchases/return", strings.NewReader(`{"item":"cup"}`))
+
+		mockService := new(MockRefundService)
+		mockService.On("ReturnItem", uint(1), "cup", "").Return(model.Refund{PurchaseId: 4, Item: "cup", Amount: 20}, nil)
+
+		handler := NewRefundHandler(mockService)
+		handler.ReturnItem(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"amount":20`)
+	})
+
+	t.Run("ServiceError", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Request = httptest.NewRequest("POST", "/purchases/return", strings.NewReader(`{"item":"cup"}`))
+
+		mockService := new(MockRefundService)
+		mockService.On("ReturnItem", uint(1), "cup", "").
+			Return(model.Refund{}, errors.New("no purchase of this item within the return window"))
+
+		handler := NewRefundHandler(mockService)
+		handler.ReturnItem(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "return window")
+	})
+}
+
+func TestRefundHandler_RefundPurchase(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 5)
+		c.Params = gin.Params{{Key: "id", Value: "9"}}
+
+		mockService := new(MockRefundService)
+		mockService.On("RefundPurchase", uint(5), uint(9)).Return(model.Refund{PurchaseId: 9}, nil)
+
+		handler := NewRefundHandler(mockService)
+		handler.RefundPurchase(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("InvalidId", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 5)
+		c.Params = gin.Params{{Key: "id", Value: "-1"}}
+
+		handler := NewRefundHandler(new(MockRefundService))
+		handler.RefundPurchase(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
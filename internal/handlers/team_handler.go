@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"io"
+	"merch_shop/internal/middleware"
+	"merch_shop/internal/model"
+	"net/http"
+	"strings"
+)
+
+type teamService interface {
+	GetTeams() ([]model.Team, error)
+	GetTeam(name string) (model.Team, error)
+	CreateTeam(name string, department string) (model.Team, error)
+	AddMember(name string, userName string, manager bool) error
+	RemoveMember(name string, userName string) error
+	ImportTeams(rows []model.TeamImportRow) ([]model.TeamImportRow, error)
+	GetTeamStats(userId uint, role string, name string, query model.TeamStatsQuery) (model.TeamStats, error)
+}
+
+type TeamHandler struct {
+	teamService teamService
+}
+
+func NewTeamHandler(teamService teamService) *TeamHandler {
+	return &TeamHandler{teamService}
+}
+
+func (handler *TeamHandler) Routes(c *gin.RouterGroup) {
+	c.GET("/teams", handler.GetTeams)
+	c.GET("/teams/:name", handler.GetTeam)
+	c.GET("/teams/:name/stats", handler.GetTeamStats)
+}
+
+func (handler *TeamHandler) AdminRoutes(c *gin.RouterGroup) {
+	c.POST("/teams", handler.CreateTeam)
+	c.POST("/teams/import", handler.ImportTeams)
+	c.POST("/teams/:name/members", handler.AddMember)
+	c.DELETE("/teams/:name/members/:user", handler.RemoveMember)
+}
+
+func (h TeamHandler) GetTeams(c *gin.Context) {
+	response, err := h.teamService.GetTeams()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h TeamHandler) GetTeam(c *gin.Context) {
+	response, err := h.teamService.GetTeam(c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h TeamHandler) CreateTeam(c *gin.Context) {
+	var request model.TeamRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	response, err := h.teamService.CreateTeam(request.Name, request.Department)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h TeamHandler) AddMember(c *gin.Context) {
+	var request model.TeamMemberRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	if err := h.teamService.AddMember(c.Param("name"), request.User, request.Manager); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func (h TeamHandler) RemoveMember(c *gin.Context) {
+	if err := h.teamService.RemoveMember(c.Param("name"), c.Param("user")); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// ImportTeams reads a CSV body with team, department and user columns; a leading header row is skipped.
+func (h TeamHandler) ImportTeams(c *gin.Context) {
+	rows, err := readTeamCSV(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "CSV is not valid"})
+		return
+	}
+	results, err := h.teamService.ImportTeams(rows)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.TeamImportResponse{Errors: err.Error(), Results: results})
+		return
+	}
+	c.JSON(http.StatusOK, model.TeamImportResponse{Results: results})
+}
+
+func (h TeamHandler) GetTeamStats(c *gin.Context) {
+	var query model.TeamStatsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	claims, _ := middleware.GetUser(c)
+	response, err := h.teamService.GetTeamStats(claims.UserId, claims.Role, c.Param("name"), query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func readTeamCSV(body io.Reader) ([]model.TeamImportRow, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+
+	rows := make([]model.TeamImportRow, 0)
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if line == 1 && strings.EqualFold(record[0], "team") {
+			continue
+		}
+		rows = append(rows, model.TeamImportRow{
+			Line:       line,
+			Team:       strings.TrimSpace(record[0]),
+			Department: strings.TrimSpace(record[1]),
+			User:       strings.TrimSpace(record[2]),
+		})
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("no rows")
+	}
+	return rows, nil
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merch_shop/internal/entity"
+	"merch_shop/internal/model"
+	"merch_shop/internal/provider"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type MockTeamService struct {
+	mock.Mock
+}
+
+func (m *MockTeamService) GetTeams() ([]model.Team, error) {
+	args := m.Called()
+	return args.Get(0).([]model.Team), args.Error(1)
+}
+
+func (m *MockTeamService) GetTeam(name string) (model.Team, error) {
+	args := m.Called(name)
+	return args.Get(0).(model.Team), args.Error(1)
+}
+
+func (m *MockTeamService) CreateTeam(name string, department string) (model.Team, error) {
+	args := m.Called(name, department)
+	return args.Get(0).(model.Team), args.Error(1)
+}
+
+func (m *MockTeamService) AddMember(name string, userName string, manager bool) error {
+	args := m.Called(name, userName, manager)
+	return args.Error(0)
+}
+
+func (m *MockTeamService) RemoveMember(name string, userName string) error {
+	args := m.Called(name, userName)
+	return args.Error(0)
+}
+
+func (m *MockTeamService) ImportTeams(rows []model.TeamImportRow) ([]model.TeamImportRow, error) {
+	args := m.Called(rows)
+	return args.Get(0).([]model.TeamImportRow), args.Error(1)
+}
+
+func (m *MockTeamService) GetTeamStats(userId uint, role string, name string, query model.TeamStatsQuery) (model.TeamStats, error) {
+	args := m.Called(userId, role, name, query)
+	return args.Get(0).(model.TeamStats), args.Error(1)
+}
+
+func TestTeamHandler_ImportTeams(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		c, w := createTestContext()
+		c.Request = httptest.NewRequest("POST", "/admin/teams/import",
+			strings.NewReader("team,department,user\nplatform,engineering,alice\nplatform, engineering, bob\n"))
+
+		rows := []model.TeamImportRow{
+			{Line: 2, Team: "platform", Department: "engineering", User: "alice"},
+			{Line: 3, Team: "platform", Department: "engineering", User: "bob"},
+		}
+		mockService := new(MockTeamService)
+		mockService.On("ImportTeams", rows).Return(rows, nil)
+
+		handler := NewTeamHandler(mockService)
+		handler.ImportTeams(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("WrongColumns", func(t *testing.T) {
+		c, w := createTestContext()
+		c.Request = httptest.NewRequest("POST", "/admin/teams/import", strings.NewReader("platform,alice\n"))
+
+		mockService := new(MockTeamService)
+
+		handler := NewTeamHandler(mockService)
+		handler.ImportTeams(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "CSV is not valid")
+		mockService.AssertNotCalled(t, "ImportTeams", mock.Anything)
+	})
+}
+
+func TestTeamHandler_GetTeamStats(t *testing.T) {
+	t.Run("MissingRange", func(t *testing.T) {
+		c, w := createTestContext()
+		c.Params = gin.Params{{Key: "name", Value: "platform"}}
+		c.Request = httptest.NewRequest("GET", "/teams/platform/stats?from=2025-03-01", nil)
+
+		mockService := new(MockTeamService)
+
+		handler := NewTeamHandler(mockService)
+		handler.GetTeamStats(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		c, w := createTestContext()
+		c.Set("user", provider.UserClaims{UserId: 1, Role: entity.RoleUser})
+		c.Params = gin.Params{{Key: "name", Value: "platform"}}
+		c.Request = httptest.NewRequest("GET", "/teams/platform/stats?from=2025-03-01&to=2025-03-31", nil)
+
+		mockService := new(MockTeamService)
+		mockService.On("GetTeamStats", uint(1), entity.RoleUser, "platform", mock.MatchedBy(func(query model.TeamStatsQuery) bool {
+			return query.From.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)) &&
+				query.To.Equal(time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC))
+		})).Return(model.TeamStats{Team: "platform", CoinsReceived: 300}, nil)
+
+		handler := NewTeamHandler(mockService)
+		handler.GetTeamStats(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"coinsReceived":300`)
+	})
+}
@@ -10,7 +10,10 @@ import (
 type transactionService interface {
 	GetInfo(userId uint) (model.InfoResponse, error)
 	SendCoin(userId uint, toUser string, amount uint) error
-	BuyItem(userId uint, name string) error
+	SendCoinBatch(userId uint, transfers []model.SendCoinRequest) ([]model.SendCoinBatchResult, error)
+	BuyItem(userId uint, name string, variant string, promoCode string) error
+	GiftItem(userId uint, name string, variant string, toUser string, promoCode string) error
+	TransferItem(userId uint, name string, variant string, toUser string, quantity uint) error
 }
 
 type TransactionHandler struct {
@@ -24,14 +27,17 @@ func NewTransactionHandler(infoService transactionService) *TransactionHandler {
 func (handler *TransactionHandler) Routes(c *gin.RouterGroup) {
 	c.GET("/info", handler.GetInfo)
 	c.POST("/sendCoin", handler.SendCoin)
+	c.POST("/sendCoin/batch", handler.SendCoinBatch)
 	c.GET("/buy/:item", handler.BuyItem)
+	c.POST("/buy/:item/gift", handler.GiftItem)
+	c.POST("/inventory/transfer", handler.TransferItem)
 }
 
 func (h TransactionHandler) GetInfo(c *gin.Context) {
 	claims, _ := middleware.GetUser(c)
 	response, err := h.transactionService.GetInfo(claims.UserId)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, model.ErrorResponse{err.Error()})
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, response)
@@ -40,29 +46,80 @@ func (h TransactionHandler) GetInfo(c *gin.Context) {
 func (h TransactionHandler) SendCoin(c *gin.Context) {
 	var request model.SendCoinRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, model.ErrorResponse{"Required fields are empty or not valid"})
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
 		return
 	}
 
 	claims, _ := middleware.GetUser(c)
 	err := h.transactionService.SendCoin(claims.UserId, request.ToUser, request.Amount)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, model.ErrorResponse{err.Error()})
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
 		return
 	}
 	c.Status(http.StatusOK)
 }
 
+func (h TransactionHandler) SendCoinBatch(c *gin.Context) {
+	var request model.SendCoinBatchRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+
+	claims, _ := middleware.GetUser(c)
+	results, err := h.transactionService.SendCoinBatch(claims.UserId, request.Transfers)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.SendCoinBatchResponse{Errors: err.Error(), Results: results})
+		return
+	}
+	c.JSON(http.StatusOK, model.SendCoinBatchResponse{Results: results})
+}
+
 func (h TransactionHandler) BuyItem(c *gin.Context) {
 	item := c.Param("item")
 	if item == "" {
-		c.JSON(http.StatusBadRequest, model.ErrorResponse{"item name is not provided"})
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "item name is not provided"})
+		return
+	}
+	claims, _ := middleware.GetUser(c)
+	err := h.transactionService.BuyItem(claims.UserId, item, c.Query("variant"), c.Query("promoCode"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func (h TransactionHandler) GiftItem(c *gin.Context) {
+	item := c.Param("item")
+	if item == "" {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "item name is not provided"})
+		return
+	}
+	var request model.GiftItemRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	claims, _ := middleware.GetUser(c)
+	err := h.transactionService.GiftItem(claims.UserId, item, request.Variant, request.ToUser, request.PromoCode)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func (h TransactionHandler) TransferItem(c *gin.Context) {
+	var request model.TransferItemRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
 		return
 	}
 	claims, _ := middleware.GetUser(c)
-	err := h.transactionService.BuyItem(claims.UserId, item)
+	err := h.transactionService.TransferItem(claims.UserId, request.Item, request.Variant, request.ToUser, request.Quantity)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, model.ErrorResponse{err.Error()})
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
 		return
 	}
 	c.Status(http.StatusOK)
@@ -26,8 +26,23 @@ func (m *MockTransactionService) SendCoin(userId uint, toUser string, amount uin
 	return args.Error(0)
 }
 
-func (m *MockTransactionService) BuyItem(userId uint, name string) error {
-	args := m.Called(userId, name)
+func (m *MockTransactionService) SendCoinBatch(userId uint, transfers []model.SendCoinRequest) ([]model.SendCoinBatchResult, error) {
+	args := m.Called(userId, transfers)
+	return args.Get(0).([]model.SendCoinBatchResult), args.Error(1)
+}
+
+func (m *MockTransactionService) BuyItem(userId uint, name string, variant string, promoCode string) error {
+	args := m.Called(userId, name, variant, promoCode)
+	return args.Error(0)
+}
+
+func (m *MockTransactionService) GiftItem(userId uint, name string, variant string, toUser string, promoCode string) error {
+	args := m.Called(userId, name, variant, toUser, promoCode)
+	return args.Error(0)
+}
+
+func (m *MockTransactionService) TransferItem(userId uint, name string, variant string, toUser string, quantity uint) error {
+	args := m.Called(userId, name, variant, toUser, quantity)
 	return args.Error(0)
 }
 
@@ -109,6 +124,58 @@ func TestTransactionHandler_SendCoin(t *testing.T) {
 	})
 }
 
+func TestTransactionHandler_SendCoinBatch(t *testing.T) {
+	transfers := []model.SendCoinRequest{{ToUser: "bob", Amount: 10}, {ToUser: "ghost", Amount: 10}}
+
+	t.Run("ValidRequest", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Request = httptest.NewRequest("POST", "/sendCoin/batch",
+			strings.NewReader(`{"transfers":[{"toUser":"bob","amount":10},{"toUser":"ghost","amount":10}]}`))
+
+		mockService := new(MockTransactionService)
+		mockService.On("SendCoinBatch", uint(1), transfers).Return([]model.SendCoinBatchResult{
+			{ToUser: "bob", Amount: 10}, {ToUser: "ghost", Amount: 10},
+		}, nil)
+
+		handler := NewTransactionHandler(mockService)
+		handler.SendCoinBatch(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("EmptyTransfers", func(t *testing.T) {
+		c, w := createTestContext()
+		c.Request = httptest.NewRequest("POST", "/sendCoin/batch",
+			strings.NewReader(`{"transfers":[]}`))
+
+		handler := NewTransactionHandler(new(MockTransactionService))
+		handler.SendCoinBatch(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Required fields")
+	})
+
+	t.Run("ValidationFailed", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Request = httptest.NewRequest("POST", "/sendCoin/batch",
+			strings.NewReader(`{"transfers":[{"toUser":"bob","amount":10},{"toUser":"ghost","amount":10}]}`))
+
+		mockService := new(MockTransactionService)
+		mockService.On("SendCoinBatch", uint(1), transfers).Return([]model.SendCoinBatchResult{
+			{ToUser: "bob", Amount: 10}, {ToUser: "ghost", Amount: 10, Error: "user not found"},
+		}, errors.New("batch validation failed"))
+
+		handler := NewTransactionHandler(mockService)
+		handler.SendCoinBatch(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "batch validation failed")
+		assert.Contains(t, w.Body.String(), `"error":"user not found"`)
+	})
+}
+
 func TestTransactionHandler_BuyItem(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		c, w := createTestContext()
@@ -116,7 +183,37 @@ func TestTransactionHandler_BuyItem(t *testing.T) {
 		c.Params = gin.Params{{Key: "item", Value: "sword"}}
 
 		mockService := new(MockTransactionService)
-		mockService.On("BuyItem", uint(1), "sword").Return(nil)
+		mockService.On("BuyItem", uint(1), "sword", "", "").Return(nil)
+
+		handler := NewTransactionHandler(mockService)
+		handler.BuyItem(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Variant", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Params = gin.Params{{Key: "item", Value: "hoody"}}
+		c.Request = httptest.NewRequest("GET", "/buy/hoody?variant=XL", nil)
+
+		mockService := new(MockTransactionService)
+		mockService.On("BuyItem", uint(1), "hoody", "XL", "").Return(nil)
+
+		handler := NewTransactionHandler(mockService)
+		handler.BuyItem(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("PromoCode", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Params = gin.Params{{Key: "item", Value: "cup"}}
+		c.Request = httptest.NewRequest("GET", "/buy/cup?promoCode=SUMMER", nil)
+
+		mockService := new(MockTransactionService)
+		mockService.On("BuyItem", uint(1), "cup", "", "SUMMER").Return(nil)
 
 		handler := NewTransactionHandler(mockService)
 		handler.BuyItem(c)
@@ -141,7 +238,7 @@ func TestTransactionHandler_BuyItem(t *testing.T) {
 		c.Params = gin.Params{{Key: "item", Value: "shield"}}
 
 		mockService := new(MockTransactionService)
-		mockService.On("BuyItem", uint(1), "shield").Return(errors.New("item not found"))
+		mockService.On("BuyItem", uint(1), "shield", "", "").Return(errors.New("item not found"))
 
 		handler := NewTransactionHandler(mockService)
 		handler.BuyItem(c)
@@ -150,3 +247,65 @@ func TestTransactionHandler_BuyItem(t *testing.T) {
 		assert.Contains(t, w.Body.String(), "item not found")
 	})
 }
+
+func TestTransactionHandler_GiftItem(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Params = gin.Params{{Key: "item", Value: "cup"}}
+		c.Request = httptest.NewRequest("POST", "/buy/cup/gift", strings.NewReader(`{"toUser":"bob"}`))
+
+		mockService := new(MockTransactionService)
+		mockService.On("GiftItem", uint(1), "cup", "", "bob", "").Return(nil)
+
+		handler := NewTransactionHandler(mockService)
+		handler.GiftItem(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("MissingRecipient", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Params = gin.Params{{Key: "item", Value: "cup"}}
+		c.Request = httptest.NewRequest("POST", "/buy/cup/gift", strings.NewReader(`{}`))
+
+		handler := NewTransactionHandler(new(MockTransactionService))
+		handler.GiftItem(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestTransactionHandler_TransferItem(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Request = httptest.NewRequest("POST", "/inventory/transfer",
+			strings.NewReader(`{"toUser":"bob","item":"cup","quantity":2}`))
+
+		mockService := new(MockTransactionService)
+		mockService.On("TransferItem", uint(1), "cup", "", "bob", uint(2)).Return(nil)
+
+		handler := NewTransactionHandler(mockService)
+		handler.TransferItem(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("ServiceError", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Request = httptest.NewRequest("POST", "/inventory/transfer",
+			strings.NewReader(`{"toUser":"bob","item":"cup","quantity":2}`))
+
+		mockService := new(MockTransactionService)
+		mockService.On("TransferItem", uint(1), "cup", "", "bob", uint(2)).Return(errors.New("not enough items in inventory"))
+
+		handler := NewTransactionHandler(mockService)
+		handler.TransferItem(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "not enough items in inventory")
+	})
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"merch_shop/internal/middleware"
+	"merch_shop/internal/model"
+	"net/http"
+)
+
+type walletService interface {
+	CreateWallet(userId uint, name string) (model.Wallet, error)
+	GetWallets(userId uint) ([]model.Wallet, error)
+	GetWallet(userId uint, name string) (model.Wallet, error)
+	GetWalletHistory(userId uint, name string) ([]model.WalletTransaction, error)
+	SetMember(userId uint, name string, memberName string, role string) error
+	RemoveMember(userId uint, name string, memberName string) error
+	Deposit(userId uint, name string, amount uint) error
+	SendCoin(userId uint, name string, toUserName string, amount uint) error
+	BuyItem(userId uint, name string, request model.WalletBuyRequest) error
+}
+
+type WalletHandler struct {
+	walletService walletService
+}
+
+func NewWalletHandler(walletService walletService) *WalletHandler {
+	return &WalletHandler{walletService}
+}
+
+func (handler *WalletHandler) Routes(c *gin.RouterGroup) {
+	c.GET("/wallets", handler.GetWallets)
+	c.POST("/wallets", handler.CreateWallet)
+	c.GET("/wallets/:name", handler.GetWallet)
+	c.GET("/wallets/:name/history", handler.GetWalletHistory)
+	c.PUT("/wallets/:name/members", handler.SetMember)
+	c.DELETE("/wallets/:name/members/:user", handler.RemoveMember)
+	c.POST("/wallets/:name/deposit", handler.Deposit)
+	c.POST("/wallets/:name/sendCoin", handler.SendCoin)
+	c.POST("/wallets/:name/buy", handler.BuyItem)
+}
+
+func (h WalletHandler) GetWallets(c *gin.Context) {
+	claims, _ := middleware.GetUser(c)
+	response, err := h.walletService.GetWallets(claims.UserId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h WalletHandler) CreateWallet(c *gin.Context) {
+	var request model.WalletRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	claims, _ := middleware.GetUser(c)
+	response, err := h.walletService.CreateWallet(claims.UserId, request.Name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h WalletHandler) GetWallet(c *gin.Context) {
+	claims, _ := middleware.GetUser(c)
+	response, err := h.walletService.GetWallet(claims.UserId, c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h WalletHandler) GetWalletHistory(c *gin.Context) {
+	claims, _ := middleware.GetUser(c)
+	response, err := h.walletService.GetWalletHistory(claims.UserId, c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h WalletHandler) SetMember(c *gin.Context) {
+	var request model.WalletMemberRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	claims, _ := middleware.GetUser(c)
+	if err := h.walletService.SetMember(claims.UserId, c.Param("name"), request.User, request.Role); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func (h WalletHandler) RemoveMember(c *gin.Context) {
+	claims, _ := middleware.GetUser(c)
+	if err := h.walletService.RemoveMember(claims.UserId, c.Param("name"), c.Param("user")); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func (h WalletHandler) Deposit(c *gin.Context) {
+	var request model.WalletDepositRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	claims, _ := middleware.GetUser(c)
+	if err := h.walletService.Deposit(claims.UserId, c.Param("name"), request.Amount); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func (h WalletHandler) SendCoin(c *gin.Context) {
+	var request model.SendCoinRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	claims, _ := middleware.GetUser(c)
+	if err := h.walletService.SendCoin(claims.UserId, c.Param("name"), request.ToUser, request.Amount); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func (h WalletHandler) BuyItem(c *gin.Context) {
+	var request model.WalletBuyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	claims, _ := middleware.GetUser(c)
+	if err := h.walletService.BuyItem(claims.UserId, c.Param("name"), request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merch_shop/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type MockWalletService struct {
+	mock.Mock
+}
+
+func (m *MockWalletService) CreateWallet(userId uint, name string) (model.Wallet, error) {
+	args := m.Called(userId, name)
+	return args.Get(0).(model.Wallet), args.Error(1)
+}
+
+func (m *MockWalletService) GetWallets(userId uint) ([]model.Wallet, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]model.Wallet), args.Error(1)
+}
+
+func (m *MockWalletService) GetWallet(userId uint, name string) (model.Wallet, error) {
+	args := m.Called(userId, name)
+	return args.Get(0).(model.Wallet), args.Error(1)
+}
+
+func (m *MockWalletService) GetWalletHistory(userId uint, name string) ([]model.WalletTransaction, error) {
+	args := m.Called(userId, name)
+	return args.Get(0).([]model.WalletTransaction), args.Error(1)
+}
+
+func (m *MockWalletService) SetMember(userId uint, name string, memberName string, role string) error {
+	args := m.Called(userId, name, memberName, role)
+	return args.Error(0)
+}
+
+func (m *MockWalletService) RemoveMember(userId uint, name string, memberName string) error {
+	args := m.Called(userId, name, memberName)
+	return args.Error(0)
+}
+
+func (m *MockWalletService) Deposit(userId uint, name string, amount uint) error {
+	args := m.Called(userId, name, amount)
+	return args.Error(0)
+}
+
+func (m *MockWalletService) SendCoin(userId uint, name string, toUserName string, amount uint) error {
+	args := m.Called(userId, name, toUserName, amount)
+	return args.Error(0)
+}
+
+func (m *MockWalletService) BuyItem(userId uint, name string, request model.WalletBuyRequest) error {
+	args := m.Called(userId, name, request)
+	return args.Error(0)
+}
+
+func TestWalletHandler_SetMember(t *testing.T) {
+	t.Run("UnknownRole", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Params = gin.Params{{Key: "name", Value: "platform"}}
+		c.Request = httptest.NewRequest("PUT", "/wallets/platform/members", strings.NewReader(`{"user":"bob","role":"admin"}`))
+
+		mockService := new(MockWalletService)
+
+		handler := NewWalletHandler(mockService)
+		handler.SetMember(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "SetMember", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Params = gin.Params{{Key: "name", Value: "platform"}}
+		c.Request = httptest.NewRequest("PUT", "/wallets/platform/members", strings.NewReader(`{"user":"bob","role":"spender"}`))
+
+		mockService := new(MockWalletService)
+		mockService.On("SetMember", uint(1), "platform", "bob", "spender").Return(nil)
+
+		handler := NewWalletHandler(mockService)
+		handler.SetMember(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestWalletHandler_BuyItem(t *testing.T) {
+	c, w := createTestContext()
+	setUserContext(c, 2)
+	c.Params = gin.Params{{Key: "name", Value: "platform"}}
+	c.Request = httptest.NewRequest("POST", "/wallets/platform/buy", strings.NewReader(`{"item":"cup","forUser":"carol"}`))
+
+	mockService := new(MockWalletService)
+	mockService.On("BuyItem", uint(2), "platform", model.WalletBuyRequest{Item: "cup", ForUser: "carol"}).Return(nil)
+
+	handler := NewWalletHandler(mockService)
+	handler.BuyItem(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
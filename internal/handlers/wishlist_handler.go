@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"merch_shop/internal/middleware"
+	"merch_shop/internal/model"
+	"net/http"
+)
+
+type wishlistService interface {
+	GetWishlist(userId uint) ([]model.WishlistItem, error)
+	AddToWishlist(userId uint, name string, variant string) error
+	RemoveFromWishlist(userId uint, name string, variant string) error
+}
+
+type WishlistHandler struct {
+	wishlistService wishlistService
+}
+
+func NewWishlistHandler(wishlistService wishlistService) *WishlistHandler {
+	return &WishlistHandler{wishlistService}
+}
+
+func (handler *WishlistHandler) Routes(c *gin.RouterGroup) {
+	c.GET("/wishlist", handler.GetWishlist)
+	c.POST("/wishlist", handler.AddToWishlist)
+	c.DELETE("/wishlist/:item", handler.RemoveFromWishlist)
+}
+
+func (h WishlistHandler) GetWishlist(c *gin.Context) {
+	claims, _ := middleware.GetUser(c)
+	response, err := h.wishlistService.GetWishlist(claims.UserId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h WishlistHandler) AddToWishlist(c *gin.Context) {
+	var request model.WishlistRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: "Required fields are empty or not valid"})
+		return
+	}
+	claims, _ := middleware.GetUser(c)
+	if err := h.wishlistService.AddToWishlist(claims.UserId, request.Item, request.Variant); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func (h WishlistHandler) RemoveFromWishlist(c *gin.Context) {
+	claims, _ := middleware.GetUser(c)
+	if err := h.wishlistService.RemoveFromWishlist(claims.UserId, c.Param("item"), c.Query("variant")); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Errors: err.Error()})
+		return
+	}
+	c.Status(http.StatusOK)
+}
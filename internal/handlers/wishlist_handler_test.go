@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merch_shop/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type MockWishlistService struct {
+	mock.Mock
+}
+
+func (m *MockWishlistService) GetWishlist(userId uint) ([]model.WishlistItem, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]model.WishlistItem), args.Error(1)
+}
+
+func (m *MockWishlistService) AddToWishlist(userId uint, name string, variant string) error {
+	args := m.Called(userId, name, variant)
+	return args.Error(0)
+}
+
+func (m *MockWishlistService) RemoveFromWishlist(userId uint, name string, variant string) error {
+	args := m.Called(userId, name, variant)
+	return args.Error(0)
+}
+
+func TestWishlistHandler_AddToWishlist(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Request = httptest.NewRequest("POST", "/wishlist", strings.NewReader(`{"item":"hoody","variant":"XL"}`))
+
+		mockService := new(MockWishlistService)
+		mockService.On("AddToWishlist", uint(1), "hoody", "XL").Return(nil)
+
+		handler := NewWishlistHandler(mockService)
+		handler.AddToWishlist(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("MissingItem", func(t *testing.T) {
+		c, w := createTestContext()
+		setUserContext(c, 1)
+		c.Request = httptest.NewRequest("POST", "/wishlist", strings.NewReader(`{}`))
+
+		mockService := new(MockWishlistService)
+
+		handler := NewWishlistHandler(mockService)
+		handler.AddToWishlist(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "AddToWishlist", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestWishlistHandler_RemoveFromWishlist(t *testing.T) {
+	c, w := createTestContext()
+	setUserContext(c, 1)
+	c.Params = gin.Params{{Key: "item", Value: "hoody"}}
+	c.Request = httptest.NewRequest("DELETE", "/wishlist/hoody?variant=XL", nil)
+
+	mockService := new(MockWishlistService)
+	mockService.On("RemoveFromWishlist", uint(1), "hoody", "XL").Return(nil)
+
+	handler := NewWishlistHandler(mockService)
+	handler.RemoveFromWishlist(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
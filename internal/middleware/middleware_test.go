@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -58,7 +59,7 @@ func TestJWTAuthMiddleware(t *testing.T) {
 		c, _ := gin.CreateTestContext(w)
 		c.Request = httptest.NewRequest("GET", "/", nil)
 
-		token, _ := auth.GenerateToken(123)
+		token, _ := auth.GenerateToken(123, "user", 0)
 		c.Request.Header.Set("Authorization", "Bearer "+token)
 
 		middleware(c)
@@ -90,3 +91,124 @@ func TestGetUser(t *testing.T) {
 		assert.Equal(t, expectedClaims, claims)
 	})
 }
+
+func TestRequireRole(t *testing.T) {
+	middleware := RequireRole("admin")
+
+	t.Run("Forbidden", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("user", provider.UserClaims{UserId: 1, Role: "user"})
+
+		middleware(c)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.True(t, c.IsAborted())
+	})
+
+	t.Run("Allowed", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("user", provider.UserClaims{UserId: 1, Role: "admin"})
+
+		middleware(c)
+
+		assert.False(t, c.IsAborted())
+	})
+}
+
+func TestRateLimit(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	middleware := rateLimit(2, time.Minute, func() time.Time { return now })
+
+	request := func(userId uint) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/", nil)
+		c.Set("user", provider.UserClaims{UserId: userId})
+		middleware(c)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, request(1).Code)
+	assert.Equal(t, http.StatusOK, request(1).Code)
+	limited := request(1)
+	assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+	assert.Equal(t, "60", limited.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusOK, request(2).Code)
+
+	now = now.Add(time.Minute)
+	assert.Equal(t, http.StatusOK, request(1).Code)
+}
+
+type stubSessions map[uint]uint
+
+func (s stubSessions) ValidateSession(userId uint, sessionVersion uint) error {
+	if s[userId] != sessionVersion {
+		return errors.New("session expired")
+	}
+	return nil
+}
+
+func TestSessionMiddleware(t *testing.T) {
+	middleware := SessionMiddleware(stubSessions{1: 2})
+
+	t.Run("Expired", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("user", provider.UserClaims{UserId: 1, SessionVersion: 1})
+
+		middleware(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.True(t, c.IsAborted())
+	})
+
+	t.Run("Current", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("user", provider.UserClaims{UserId: 1, SessionVersion: 2})
+
+		middleware(c)
+
+		assert.False(t, c.IsAborted())
+	})
+}
+
+type stubAPIKeys map[string]provider.UserClaims
+
+func (s stubAPIKeys) AuthenticateAPIKey(key string) (provider.UserClaims, error) {
+	claims, found := s[key]
+	if !found {
+		return provider.UserClaims{}, errors.New("api key is invalid")
+	}
+	return claims, nil
+}
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	keys := stubAPIKeys{"msk_valid": {UserId: 7, APIKeyID: 1, Scopes: []string{"catalog:read"}}}
+	router := gin.New()
+	router.Use(
+		APIKeyMiddleware(keys, JWTAuthMiddleware(provider.NewJWTAuth([]byte("test_secret"), time.Hour))),
+		RequireAPIKeyScope(map[string]string{"GET /items": "catalog:read", "POST /sendCoin": "coins:grant"}),
+	)
+	router.GET("/items", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/sendCoin", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/info", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	request := func(method string, path string, key string) int {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(method, path, nil)
+		if key != "" {
+			r.Header.Set("X-API-Key", key)
+		}
+		router.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request("GET", "/items", "msk_valid"))
+	assert.Equal(t, http.StatusForbidden, request("POST", "/sendCoin", "msk_valid"))
+	assert.Equal(t, http.StatusForbidden, request("GET", "/info", "msk_valid"), "unlisted routes are closed to API keys")
+	assert.Equal(t, http.StatusUnauthorized, request("GET", "/items", "msk_revoked"))
+	assert.Equal(t, http.StatusUnauthorized, request("GET", "/items", ""), "requests without a key need a token")
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"math"
+	"merch_shop/internal/model"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// RateLimit lets every user make at most limit requests per window and rejects the rest with 429.
+// Requests without user claims are counted per client IP. Counters are kept in memory.
+// A limit of 0 turns the check off.
+func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	if limit <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return rateLimit(limit, window, time.Now)
+}
+
+func rateLimit(limit int, window time.Duration, now func() time.Time) gin.HandlerFunc {
+	var mu sync.Mutex
+	windows := make(map[string]*rateWindow)
+	lastSweep := now()
+
+	return func(c *gin.Context) {
+		key := "ip:" + c.ClientIP()
+		if claims, found := GetUser(c); found {
+			key = fmt.Sprintf("user:%d", claims.UserId)
+		}
+		at := now()
+
+		mu.Lock()
+		if at.Sub(lastSweep) >= window {
+			for k, v := range windows {
+				if at.Sub(v.start) >= window {
+					delete(windows, k)
+				}
+			}
+			lastSweep = at
+		}
+		w, found := windows[key]
+		if !found || at.Sub(w.start) >= window {
+			w = &rateWindow{start: at}
+			windows[key] = w
+		}
+		w.count++
+		allowed := w.count <= limit
+		retryAfter := w.start.Add(window).Sub(at)
+		mu.Unlock()
+
+		if !allowed {
+			c.Header("Retry-After", fmt.Sprint(int(math.Ceil(retryAfter.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, model.ErrorResponse{Errors: "Too many requests"})
+			return
+		}
+		c.Next()
+	}
+}
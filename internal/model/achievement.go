@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+type Badge struct {
+	Badge       string    `json:"badge"`
+	Description string    `json:"description,omitempty"`
+	Bonus       uint      `json:"bonus,omitempty"`
+	AwardedAt   time.Time `json:"awardedAt"`
+}
+
+type Achievement struct {
+	Badge       string     `json:"badge"`
+	Description string     `json:"description,omitempty"`
+	Metric      string     `json:"metric"`
+	Threshold   uint       `json:"threshold"`
+	Bonus       uint       `json:"bonus,omitempty"`
+	Progress    uint       `json:"progress"`
+	AwardedAt   *time.Time `json:"awardedAt,omitempty"`
+}
+
+type AchievementEvaluation struct {
+	Awarded int `json:"awarded"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+type APIKey struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+// NewAPIKey carries the key itself, which is only returned when the key is created.
+type NewAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
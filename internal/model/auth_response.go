@@ -1,5 +1,9 @@
 package model
 
 type AuthResponse struct {
-	Token string `json:"token"`
+	Token string `json:"token,omitempty"`
+	// TwoFactorRequired is set instead of Token when the user has to exchange ChallengeToken and an
+	// authenticator or recovery code for a token.
+	TwoFactorRequired bool   `json:"twoFactorRequired,omitempty"`
+	ChallengeToken    string `json:"challengeToken,omitempty"`
 }
@@ -0,0 +1,5 @@
+package model
+
+type Category struct {
+	Name string `json:"name" binding:"required"`
+}
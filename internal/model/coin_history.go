@@ -3,4 +3,5 @@ package model
 type CoinHistory struct {
 	Received []CoinHistoryReceived `json:"received"`
 	Sent     []CoinHistorySent     `json:"sent"`
+	Bonuses  []CoinHistoryBonus    `json:"bonuses"`
 }
@@ -0,0 +1,12 @@
+package model
+
+import (
+	"time"
+)
+
+// CoinHistoryBonus is the bonus coins credited with an achievement badge.
+type CoinHistoryBonus struct {
+	Badge     string    `json:"badge"`
+	Amount    uint      `json:"amount"`
+	AwardedAt time.Time `json:"awardedAt"`
+}
@@ -1,6 +1,8 @@
 package model
 
 type CoinHistoryReceived struct {
-	FromUser string `json:"fromUser"`
-	Amount   uint   `json:"amount"`
+	FromUser        string `json:"fromUser"`
+	FromDisplayName string `json:"fromDisplayName"`
+	Amount          uint   `json:"amount"`
+	Wallet          string `json:"wallet,omitempty"`
 }
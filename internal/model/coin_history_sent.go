@@ -1,6 +1,8 @@
 package model
 
 type CoinHistorySent struct {
-	ToUser string `json:"toUser"`
-	Amount uint   `json:"amount"`
+	ToUser        string `json:"toUser"`
+	ToDisplayName string `json:"toDisplayName"`
+	Amount        uint   `json:"amount"`
+	Wallet        string `json:"wallet,omitempty"`
 }
@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+type FulfillmentOrder struct {
+	PurchaseId uint      `json:"purchaseId"`
+	Owner      string    `json:"owner"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
@@ -0,0 +1,8 @@
+package model
+
+type FulfillmentQueue struct {
+	Item    string             `json:"item"`
+	Variant string             `json:"variant,omitempty"`
+	Count   int                `json:"count"`
+	Orders  []FulfillmentOrder `json:"orders"`
+}
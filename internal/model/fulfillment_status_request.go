@@ -0,0 +1,5 @@
+package model
+
+type FulfillmentStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=packed ready delivered"`
+}
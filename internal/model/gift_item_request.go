@@ -0,0 +1,7 @@
+package model
+
+type GiftItemRequest struct {
+	ToUser    string `json:"toUser" binding:"required"`
+	Variant   string `json:"variant"`
+	PromoCode string `json:"promoCode"`
+}
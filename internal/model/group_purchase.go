@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+)
+
+type GroupPurchase struct {
+	Id        uint      `json:"id"`
+	Item      string    `json:"item"`
+	Variant   string    `json:"variant,omitempty"`
+	Organizer string    `json:"organizer"`
+	Recipient string    `json:"recipient"`
+	Price     uint      `json:"price"`
+	Collected uint      `json:"collected"`
+	Status    string    `json:"status"`
+	Deadline  time.Time `json:"deadline"`
+	Pledges   []Pledge  `json:"pledges"`
+}
@@ -0,0 +1,12 @@
+package model
+
+import (
+	"time"
+)
+
+type GroupPurchaseRequest struct {
+	Item     string    `json:"item" binding:"required"`
+	Variant  string    `json:"variant"`
+	ToUser   string    `json:"toUser" binding:"required"`
+	Deadline time.Time `json:"deadline" binding:"required"`
+}
@@ -6,4 +6,12 @@ type InfoResponse struct {
 	Inventory []Inventory `json:"inventory"`
 
 	CoinHistory CoinHistory `json:"coinHistory"`
+
+	ItemHistory ItemHistory `json:"itemHistory"`
+
+	Refunds []Refund `json:"refunds"`
+
+	Wishlist []WishlistItem `json:"wishlist"`
+
+	Badges []Badge `json:"badges"`
 }
@@ -2,5 +2,6 @@ package model
 
 type Inventory struct {
 	Name     string `json:"name"`
+	Variant  string `json:"variant,omitempty"`
 	Quantity uint   `json:"quantity"`
 }
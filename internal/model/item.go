@@ -0,0 +1,10 @@
+package model
+
+type Item struct {
+	Name          string   `json:"name"`
+	Price         uint     `json:"price"`
+	Category      string   `json:"category,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+	Stock         *uint    `json:"stock,omitempty"`
+	PurchaseLimit uint     `json:"purchaseLimit,omitempty"`
+}
@@ -0,0 +1,5 @@
+package model
+
+type ItemCategoryRequest struct {
+	Category string `json:"category"`
+}
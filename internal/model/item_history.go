@@ -0,0 +1,6 @@
+package model
+
+type ItemHistory struct {
+	Received []ItemHistoryReceived `json:"received"`
+	Sent     []ItemHistorySent     `json:"sent"`
+}
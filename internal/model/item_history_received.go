@@ -0,0 +1,10 @@
+package model
+
+type ItemHistoryReceived struct {
+	FromUser        string `json:"fromUser"`
+	FromDisplayName string `json:"fromDisplayName"`
+	Item            string `json:"item"`
+	Variant         string `json:"variant,omitempty"`
+	Quantity        uint   `json:"quantity"`
+	Gift            bool   `json:"gift"`
+}
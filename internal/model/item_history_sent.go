@@ -0,0 +1,10 @@
+package model
+
+type ItemHistorySent struct {
+	ToUser        string `json:"toUser"`
+	ToDisplayName string `json:"toDisplayName"`
+	Item          string `json:"item"`
+	Variant       string `json:"variant,omitempty"`
+	Quantity      uint   `json:"quantity"`
+	Gift          bool   `json:"gift"`
+}
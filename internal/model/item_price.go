@@ -0,0 +1,8 @@
+package model
+
+import "time"
+
+type ItemPrice struct {
+	Price         uint      `json:"price"`
+	EffectiveFrom time.Time `json:"effectiveFrom"`
+}
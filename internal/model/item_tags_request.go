@@ -0,0 +1,5 @@
+package model
+
+type ItemTagsRequest struct {
+	Tags []string `json:"tags" binding:"required"`
+}
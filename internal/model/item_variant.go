@@ -0,0 +1,7 @@
+package model
+
+type ItemVariant struct {
+	Name       string `json:"name" binding:"required"`
+	PriceDelta int    `json:"priceDelta"`
+	Stock      *uint  `json:"stock,omitempty"`
+}
@@ -0,0 +1,13 @@
+package model
+
+import (
+	"time"
+)
+
+type Leaderboard struct {
+	Metric  string             `json:"metric"`
+	Period  string             `json:"period"`
+	From    time.Time          `json:"from"`
+	To      time.Time          `json:"to"`
+	Entries []LeaderboardEntry `json:"entries"`
+}
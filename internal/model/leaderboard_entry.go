@@ -0,0 +1,7 @@
+package model
+
+type LeaderboardEntry struct {
+	Rank  int    `json:"rank"`
+	User  string `json:"user"`
+	Value uint   `json:"value"`
+}
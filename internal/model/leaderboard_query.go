@@ -0,0 +1,6 @@
+package model
+
+type LeaderboardQuery struct {
+	Period string `form:"period" binding:"omitempty,oneof=week month quarter"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
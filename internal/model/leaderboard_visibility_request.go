@@ -0,0 +1,5 @@
+package model
+
+type LeaderboardVisibilityRequest struct {
+	Hidden *bool `json:"hidden" binding:"required"`
+}
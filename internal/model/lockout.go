@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+type Lockout struct {
+	Kind        string    `json:"kind"`
+	Value       string    `json:"value"`
+	Failures    uint      `json:"failures"`
+	LockedUntil time.Time `json:"lockedUntil"`
+}
@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+type Notification struct {
+	Id        uint       `json:"id"`
+	Message   string     `json:"message"`
+	CreatedAt time.Time  `json:"createdAt"`
+	ReadAt    *time.Time `json:"readAt,omitempty"`
+}
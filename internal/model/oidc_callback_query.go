@@ -0,0 +1,6 @@
+package model
+
+type OIDCCallbackQuery struct {
+	Code  string `form:"code" binding:"required"`
+	State string `form:"state" binding:"required"`
+}
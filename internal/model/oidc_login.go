@@ -0,0 +1,6 @@
+package model
+
+type OIDCLogin struct {
+	// AuthorizationURL is where the user signs in at the identity provider.
+	AuthorizationURL string `json:"authorizationUrl"`
+}
@@ -0,0 +1,6 @@
+package model
+
+type PasswordChangeRequest struct {
+	OldPassword string `json:"oldPassword" binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required"`
+}
@@ -0,0 +1,8 @@
+package model
+
+import "time"
+
+type PasswordResetToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
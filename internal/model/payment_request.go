@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+type PaymentRequest struct {
+	Id        uint      `json:"id"`
+	FromUser  string    `json:"fromUser,omitempty"`
+	ToUser    string    `json:"toUser,omitempty"`
+	Amount    uint      `json:"amount"`
+	Comment   string    `json:"comment"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
@@ -0,0 +1,6 @@
+package model
+
+type PaymentRequestsResponse struct {
+	Incoming []PaymentRequest `json:"incoming"`
+	Outgoing []PaymentRequest `json:"outgoing"`
+}
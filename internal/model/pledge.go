@@ -0,0 +1,6 @@
+package model
+
+type Pledge struct {
+	User   string `json:"user"`
+	Amount uint   `json:"amount"`
+}
@@ -0,0 +1,5 @@
+package model
+
+type PledgeRequest struct {
+	Amount uint `json:"amount" binding:"required"`
+}
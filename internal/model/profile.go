@@ -0,0 +1,9 @@
+package model
+
+type Profile struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	AvatarURL   string `json:"avatarUrl,omitempty"`
+	Department  string `json:"department,omitempty"`
+	Bio         string `json:"bio,omitempty"`
+}
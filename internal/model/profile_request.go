@@ -0,0 +1,9 @@
+package model
+
+// ProfileRequest changes only the fields that are present; an empty string clears a field.
+type ProfileRequest struct {
+	DisplayName *string `json:"displayName" binding:"omitempty,max=64"`
+	AvatarURL   *string `json:"avatarUrl" binding:"omitempty,max=512"`
+	Department  *string `json:"department" binding:"omitempty,max=64"`
+	Bio         *string `json:"bio" binding:"omitempty,max=500"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+type PromoCode struct {
+	Code           string     `json:"code" binding:"required"`
+	DiscountType   string     `json:"discountType" binding:"required,oneof=percent fixed"`
+	Discount       uint       `json:"discount" binding:"required"`
+	Item           string     `json:"item,omitempty"`
+	Category       string     `json:"category,omitempty"`
+	MaxRedemptions uint       `json:"maxRedemptions"`
+	MaxPerUser     uint       `json:"maxPerUser"`
+	Redemptions    uint       `json:"redemptions"`
+	StartsAt       *time.Time `json:"startsAt,omitempty"`
+	EndsAt         *time.Time `json:"endsAt,omitempty"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+)
+
+type Purchase struct {
+	Id          uint       `json:"id"`
+	Item        string     `json:"item"`
+	Variant     string     `json:"variant,omitempty"`
+	Price       uint       `json:"price"`
+	Discount    uint       `json:"discount,omitempty"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	PackedAt    *time.Time `json:"packedAt,omitempty"`
+	ReadyAt     *time.Time `json:"readyAt,omitempty"`
+	DeliveredAt *time.Time `json:"deliveredAt,omitempty"`
+	CancelledAt *time.Time `json:"cancelledAt,omitempty"`
+	RefundedAt  *time.Time `json:"refundedAt,omitempty"`
+}
@@ -0,0 +1,5 @@
+package model
+
+type RecoveryCodes struct {
+	Codes []string `json:"codes"`
+}
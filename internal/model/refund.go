@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+type Refund struct {
+	PurchaseId uint      `json:"purchaseId"`
+	Item       string    `json:"item"`
+	Variant    string    `json:"variant,omitempty"`
+	Amount     uint      `json:"amount"`
+	RefundedAt time.Time `json:"refundedAt"`
+}
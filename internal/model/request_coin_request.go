@@ -0,0 +1,7 @@
+package model
+
+type RequestCoinRequest struct {
+	FromUser string `json:"fromUser" binding:"required"`
+	Amount   uint   `json:"amount" binding:"required"`
+	Comment  string `json:"comment"`
+}
@@ -0,0 +1,7 @@
+package model
+
+type RestockRequest struct {
+	Variant       string `json:"variant"`
+	Quantity      uint   `json:"quantity"`
+	PurchaseLimit *uint  `json:"purchaseLimit"`
+}
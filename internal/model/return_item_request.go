@@ -0,0 +1,6 @@
+package model
+
+type ReturnItemRequest struct {
+	Item    string `json:"item" binding:"required"`
+	Variant string `json:"variant"`
+}
@@ -0,0 +1,8 @@
+package model
+
+import "time"
+
+type SchedulePriceRequest struct {
+	Price         uint       `json:"price" binding:"required"`
+	EffectiveFrom *time.Time `json:"effectiveFrom"`
+}
@@ -0,0 +1,5 @@
+package model
+
+type SendCoinBatchRequest struct {
+	Transfers []SendCoinRequest `json:"transfers" binding:"required,min=1,max=100,dive"`
+}
@@ -0,0 +1,6 @@
+package model
+
+type SendCoinBatchResponse struct {
+	Errors  string                `json:"errors,omitempty"`
+	Results []SendCoinBatchResult `json:"results"`
+}
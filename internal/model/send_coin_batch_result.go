@@ -0,0 +1,7 @@
+package model
+
+type SendCoinBatchResult struct {
+	ToUser string `json:"toUser"`
+	Amount uint   `json:"amount"`
+	Error  string `json:"error,omitempty"`
+}
@@ -0,0 +1,7 @@
+package model
+
+type ServiceAccount struct {
+	Name    string   `json:"name"`
+	Balance uint     `json:"balance"`
+	APIKeys []APIKey `json:"apiKeys"`
+}
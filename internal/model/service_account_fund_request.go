@@ -0,0 +1,5 @@
+package model
+
+type ServiceAccountFundRequest struct {
+	Amount uint `json:"amount" binding:"required"`
+}
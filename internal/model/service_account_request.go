@@ -0,0 +1,6 @@
+package model
+
+type ServiceAccountRequest struct {
+	Name    string `json:"name" binding:"required"`
+	Balance uint   `json:"balance"`
+}
@@ -0,0 +1,8 @@
+package model
+
+type Team struct {
+	Name       string   `json:"name"`
+	Department string   `json:"department,omitempty"`
+	Members    []string `json:"members"`
+	Managers   []string `json:"managers"`
+}
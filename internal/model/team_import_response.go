@@ -0,0 +1,6 @@
+package model
+
+type TeamImportResponse struct {
+	Errors  string          `json:"errors,omitempty"`
+	Results []TeamImportRow `json:"results"`
+}
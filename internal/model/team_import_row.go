@@ -0,0 +1,10 @@
+package model
+
+// TeamImportRow is one line of a team CSV import.
+type TeamImportRow struct {
+	Line       int    `json:"line"`
+	Team       string `json:"team"`
+	Department string `json:"department,omitempty"`
+	User       string `json:"user"`
+	Error      string `json:"error,omitempty"`
+}
@@ -0,0 +1,6 @@
+package model
+
+type TeamMemberRequest struct {
+	User    string `json:"user" binding:"required"`
+	Manager bool   `json:"manager"`
+}
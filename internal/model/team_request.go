@@ -0,0 +1,6 @@
+package model
+
+type TeamRequest struct {
+	Name       string `json:"name" binding:"required"`
+	Department string `json:"department"`
+}
@@ -0,0 +1,15 @@
+package model
+
+import (
+	"time"
+)
+
+type TeamStats struct {
+	Team          string    `json:"team"`
+	From          time.Time `json:"from"`
+	To            time.Time `json:"to"`
+	Members       int       `json:"members"`
+	CoinsReceived uint      `json:"coinsReceived"`
+	CoinsGiven    uint      `json:"coinsGiven"`
+	ItemsBought   int64     `json:"itemsBought"`
+}
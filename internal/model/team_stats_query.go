@@ -0,0 +1,10 @@
+package model
+
+import (
+	"time"
+)
+
+type TeamStatsQuery struct {
+	From time.Time `form:"from" binding:"required" time_format:"2006-01-02" time_utc:"1"`
+	To   time.Time `form:"to" binding:"required" time_format:"2006-01-02" time_utc:"1"`
+}
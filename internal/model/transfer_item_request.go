@@ -0,0 +1,8 @@
+package model
+
+type TransferItemRequest struct {
+	ToUser   string `json:"toUser" binding:"required"`
+	Item     string `json:"item" binding:"required"`
+	Variant  string `json:"variant"`
+	Quantity uint   `json:"quantity" binding:"required"`
+}
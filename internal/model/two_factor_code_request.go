@@ -0,0 +1,5 @@
+package model
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
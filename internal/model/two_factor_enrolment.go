@@ -0,0 +1,7 @@
+package model
+
+type TwoFactorEnrolment struct {
+	Secret string `json:"secret"`
+	// ProvisioningURI is the otpauth:// URI to show as a QR code.
+	ProvisioningURI string `json:"provisioningUri"`
+}
@@ -0,0 +1,6 @@
+package model
+
+type UserSearchQuery struct {
+	Query string `form:"query" binding:"required,max=64"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=20"`
+}
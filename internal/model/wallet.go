@@ -0,0 +1,8 @@
+package model
+
+type Wallet struct {
+	Name    string         `json:"name"`
+	Balance uint           `json:"balance"`
+	Role    string         `json:"role"`
+	Members []WalletMember `json:"members"`
+}
@@ -0,0 +1,8 @@
+package model
+
+type WalletBuyRequest struct {
+	Item      string `json:"item" binding:"required"`
+	Variant   string `json:"variant"`
+	ForUser   string `json:"forUser" binding:"required"`
+	PromoCode string `json:"promoCode"`
+}
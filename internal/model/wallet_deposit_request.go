@@ -0,0 +1,5 @@
+package model
+
+type WalletDepositRequest struct {
+	Amount uint `json:"amount" binding:"required"`
+}
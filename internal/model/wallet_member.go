@@ -0,0 +1,6 @@
+package model
+
+type WalletMember struct {
+	User string `json:"user"`
+	Role string `json:"role"`
+}
@@ -0,0 +1,6 @@
+package model
+
+type WalletMemberRequest struct {
+	User string `json:"user" binding:"required"`
+	Role string `json:"role" binding:"required,oneof=owner spender viewer"`
+}
@@ -0,0 +1,5 @@
+package model
+
+type WalletRequest struct {
+	Name string `json:"name" binding:"required"`
+}
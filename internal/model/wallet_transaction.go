@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+)
+
+type WalletTransaction struct {
+	Type       string    `json:"type"`
+	User       string    `json:"user"`
+	ToUser     string    `json:"toUser,omitempty"`
+	Amount     uint      `json:"amount"`
+	Item       string    `json:"item,omitempty"`
+	Variant    string    `json:"variant,omitempty"`
+	PurchaseId *uint     `json:"purchaseId,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
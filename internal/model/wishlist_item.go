@@ -0,0 +1,8 @@
+package model
+
+type WishlistItem struct {
+	Item        string `json:"item"`
+	Variant     string `json:"variant,omitempty"`
+	Price       uint   `json:"price"`
+	CoinsNeeded uint   `json:"coinsNeeded"`
+}
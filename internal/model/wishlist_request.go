@@ -0,0 +1,6 @@
+package model
+
+type WishlistRequest struct {
+	Item    string `json:"item" binding:"required"`
+	Variant string `json:"variant"`
+}
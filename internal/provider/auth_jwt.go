@@ -7,10 +7,21 @@ import (
 )
 
 type UserClaims struct {
-	UserId uint `json:"user_id"`
+	UserId uint   `json:"user_id"`
+	Role   string `json:"role,omitempty"`
+	// SessionVersion must match the user's current session version for the token to be accepted.
+	SessionVersion uint `json:"session_version,omitempty"`
+	// Purpose is set on tokens that only grant a single step, such as ChallengePurpose.
+	Purpose string `json:"purpose,omitempty"`
+	// APIKeyID and Scopes are set on requests authenticated with an API key instead of a token.
+	APIKeyID uint     `json:"-"`
+	Scopes   []string `json:"-"`
 	jwt.RegisteredClaims
 }
 
+// ChallengePurpose marks tokens that only allow completing a two-factor sign-in.
+const ChallengePurpose = "2fa"
+
 type JWTAuth struct {
 	signingKey []byte
 	expiration time.Duration
@@ -20,23 +31,35 @@ func NewJWTAuth(signingKey []byte, expiration time.Duration) *JWTAuth {
 	return &JWTAuth{signingKey: signingKey, expiration: expiration}
 }
 
+// VerifyToken accepts session tokens only, not tokens issued for a Purpose.
 func (auth JWTAuth) VerifyToken(tokenString string) (UserClaims, error) {
+	return auth.verify(tokenString, "")
+}
+
+// VerifyChallengeToken accepts tokens issued by GenerateChallengeToken only.
+func (auth JWTAuth) VerifyChallengeToken(tokenString string) (UserClaims, error) {
+	return auth.verify(tokenString, ChallengePurpose)
+}
+
+func (auth JWTAuth) verify(tokenString string, purpose string) (UserClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &UserClaims{}, func(token *jwt.Token) (interface{}, error) {
 		return auth.signingKey, nil
 	})
 	if err != nil {
 		return UserClaims{}, err
 	}
-	if claims, ok := token.Claims.(*UserClaims); ok && token.Valid {
+	if claims, ok := token.Claims.(*UserClaims); ok && token.Valid && claims.Purpose == purpose {
 		return *claims, nil
 	}
 
 	return UserClaims{}, errors.New("invalid token")
 }
 
-func (auth JWTAuth) GenerateToken(userId uint) (string, error) {
+func (auth JWTAuth) GenerateToken(userId uint, role string, sessionVersion uint) (string, error) {
 	claims := UserClaims{
-		UserId: userId,
+		UserId:         userId,
+		Role:           role,
+		SessionVersion: sessionVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(auth.expiration)),
 		},
@@ -45,3 +68,19 @@ func (auth JWTAuth) GenerateToken(userId uint) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString(auth.signingKey)
 }
+
+// GenerateChallengeToken issues a short-lived token that proves the password of the user was
+// checked and can only be exchanged for a session token with a second factor.
+func (auth JWTAuth) GenerateChallengeToken(userId uint, sessionVersion uint, ttl time.Duration) (string, error) {
+	claims := UserClaims{
+		UserId:         userId,
+		SessionVersion: sessionVersion,
+		Purpose:        ChallengePurpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(auth.signingKey)
+}
@@ -13,13 +13,14 @@ func TestJWTAuth(t *testing.T) {
 
 	t.Run("GenerateAndVerifyValidToken", func(t *testing.T) {
 		userId := uint(123)
-		token, err := auth.GenerateToken(userId)
+		token, err := auth.GenerateToken(userId, "admin", 0)
 		assert.NoError(t, err)
 		assert.NotEmpty(t, token)
 
 		claims, err := auth.VerifyToken(token)
 		assert.NoError(t, err)
 		assert.Equal(t, userId, claims.UserId)
+		assert.Equal(t, "admin", claims.Role)
 		assert.WithinDuration(t, time.Now().Add(time.Hour*24), claims.ExpiresAt.Time, time.Minute)
 	})
 
@@ -40,4 +41,21 @@ func TestJWTAuth(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "signature")
 	})
+
+	t.Run("ChallengeTokenIsNotASessionToken", func(t *testing.T) {
+		token, err := auth.GenerateChallengeToken(123, 2, time.Minute)
+		assert.NoError(t, err)
+
+		_, err = auth.VerifyToken(token)
+		assert.Error(t, err)
+
+		claims, err := auth.VerifyChallengeToken(token)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(123), claims.UserId)
+		assert.Equal(t, uint(2), claims.SessionVersion)
+
+		sessionToken, _ := auth.GenerateToken(123, "user", 0)
+		_, err = auth.VerifyChallengeToken(sessionToken)
+		assert.Error(t, err)
+	})
 }
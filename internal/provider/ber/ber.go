@@ -0,0 +1,200 @@
+// Package ber encodes and decodes the subset of ASN.1 Basic Encoding Rules that LDAP messages use:
+// definite lengths and tag numbers below 31.
+package ber
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	ClassUniversal   = 0
+	ClassApplication = 1
+	ClassContext     = 2
+)
+
+const (
+	TagBoolean     = 1
+	TagInteger     = 2
+	TagOctetString = 4
+	TagNull        = 5
+	TagEnumerated  = 10
+	TagSequence    = 16
+	TagSet         = 17
+)
+
+// maxLength bounds the length of a single element, so that a peer cannot make us allocate without limit.
+const maxLength = 1 << 20
+
+// Packet is a BER element. Primitive elements carry Value; constructed elements carry Children.
+type Packet struct {
+	Class       int
+	Constructed bool
+	Tag         int
+	Value       []byte
+	Children    []*Packet
+}
+
+// Sequence returns a constructed element with the given class and tag.
+func Sequence(class int, tag int, children ...*Packet) *Packet {
+	return &Packet{Class: class, Constructed: true, Tag: tag, Children: children}
+}
+
+// String returns a primitive element holding s.
+func String(class int, tag int, s string) *Packet {
+	return &Packet{Class: class, Tag: tag, Value: []byte(s)}
+}
+
+// Int returns a primitive element holding the two's complement encoding of v.
+func Int(class int, tag int, v int64) *Packet {
+	var value []byte
+	for {
+		value = append([]byte{byte(v)}, value...)
+		v >>= 8
+		if (v == 0 && value[0]&0x80 == 0) || (v == -1 && value[0]&0x80 != 0) {
+			break
+		}
+	}
+	return &Packet{Class: class, Tag: tag, Value: value}
+}
+
+func Bool(v bool) *Packet {
+	if v {
+		return &Packet{Class: ClassUniversal, Tag: TagBoolean, Value: []byte{0xff}}
+	}
+	return &Packet{Class: ClassUniversal, Tag: TagBoolean, Value: []byte{0}}
+}
+
+// Is reports whether p has the given class and tag.
+func (p *Packet) Is(class int, tag int) bool {
+	return p != nil && p.Class == class && p.Tag == tag
+}
+
+// String returns the value of a primitive element, or "" for nil.
+func (p *Packet) String() string {
+	if p == nil {
+		return ""
+	}
+	return string(p.Value)
+}
+
+// Int returns the value of an integer or enumerated element, or 0 for nil.
+func (p *Packet) Int() int64 {
+	if p == nil {
+		return 0
+	}
+	var v int64
+	for i, b := range p.Value {
+		if i == 0 && b&0x80 != 0 {
+			v = -1
+		}
+		v = v<<8 | int64(b)
+	}
+	return v
+}
+
+// Child returns the i-th child, or nil when there is none.
+func (p *Packet) Child(i int) *Packet {
+	if p == nil || i >= len(p.Children) {
+		return nil
+	}
+	return p.Children[i]
+}
+
+// Bytes returns the encoding of p.
+func (p *Packet) Bytes() []byte {
+	content := p.Value
+	if p.Constructed {
+		content = nil
+		for _, child := range p.Children {
+			content = append(content, child.Bytes()...)
+		}
+	}
+	identifier := byte(p.Class<<6 | p.Tag)
+	if p.Constructed {
+		identifier |= 0x20
+	}
+	return append(append([]byte{identifier}, encodeLength(len(content))...), content...)
+}
+
+func encodeLength(length int) []byte {
+	if length < 0x80 {
+		return []byte{byte(length)}
+	}
+	var encoded []byte
+	for ; length > 0; length >>= 8 {
+		encoded = append([]byte{byte(length)}, encoded...)
+	}
+	return append([]byte{0x80 | byte(len(encoded))}, encoded...)
+}
+
+// Read reads one element from r.
+func Read(r *bufio.Reader) (*Packet, error) {
+	identifier, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if identifier&0x1f == 0x1f {
+		return nil, errors.New("ber: high tag numbers are not supported")
+	}
+	length, err := readLength(r)
+	if err != nil {
+		return nil, err
+	}
+	content := make([]byte, length)
+	if _, err := io.ReadFull(r, content); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	p := &Packet{Class: int(identifier >> 6), Constructed: identifier&0x20 != 0, Tag: int(identifier & 0x1f)}
+	if !p.Constructed {
+		p.Value = content
+		return p, nil
+	}
+	children := bufio.NewReader(bytes.NewReader(content))
+	for {
+		if _, err := children.Peek(1); err == io.EOF {
+			return p, nil
+		}
+		child, err := Read(children)
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		p.Children = append(p.Children, child)
+	}
+}
+
+func readLength(r *bufio.Reader) (int, error) {
+	first, err := r.ReadByte()
+	if err != nil {
+		return 0, unexpectedEOF(err)
+	}
+	if first < 0x80 {
+		return int(first), nil
+	}
+	count := int(first & 0x7f)
+	if count == 0 || count > 3 {
+		return 0, fmt.Errorf("ber: unsupported length encoding")
+	}
+	length := 0
+	for i := 0; i < count; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, unexpectedEOF(err)
+		}
+		length = length<<8 | int(b)
+	}
+	if length > maxLength {
+		return 0, fmt.Errorf("ber: element of %d bytes is too long", length)
+	}
+	return length, nil
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,54 @@
+package ber
+
+import (
+	"bufio"
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestInt(t *testing.T) {
+	for v, encoded := range map[int64][]byte{
+		0:    {0x02, 0x01, 0x00},
+		127:  {0x02, 0x01, 0x7f},
+		128:  {0x02, 0x02, 0x00, 0x80},
+		256:  {0x02, 0x02, 0x01, 0x00},
+		-1:   {0x02, 0x01, 0xff},
+		-129: {0x02, 0x02, 0xff, 0x7f},
+	} {
+		p := Int(ClassUniversal, TagInteger, v)
+		assert.Equal(t, encoded, p.Bytes(), v)
+		assert.Equal(t, v, p.Int())
+	}
+}
+
+func TestRead(t *testing.T) {
+	long := strings.Repeat("x", 300)
+	packet := Sequence(ClassUniversal, TagSequence,
+		Int(ClassUniversal, TagInteger, 7),
+		Sequence(ClassApplication, 0, String(ClassUniversal, TagOctetString, long), String(ClassContext, 0, "")),
+		Bool(true),
+	)
+
+	read, err := Read(bufio.NewReader(bytes.NewReader(packet.Bytes())))
+	assert.NoError(t, err)
+	assert.True(t, read.Is(ClassUniversal, TagSequence))
+	assert.Equal(t, int64(7), read.Child(0).Int())
+	assert.True(t, read.Child(1).Is(ClassApplication, 0))
+	assert.Equal(t, long, read.Child(1).Child(0).String())
+	assert.True(t, read.Child(1).Child(1).Is(ClassContext, 0))
+	assert.Nil(t, read.Child(3))
+}
+
+func TestRead_Malformed(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"Truncated":        {0x30, 0x05, 0x02, 0x01},
+		"IndefiniteLength": {0x30, 0x80, 0x00, 0x00},
+		"TooLong":          {0x04, 0x84, 0x7f, 0xff, 0xff, 0xff},
+		"TruncatedChild":   {0x30, 0x02, 0x04, 0x05},
+	} {
+		_, err := Read(bufio.NewReader(bytes.NewReader(data)))
+		assert.Error(t, err, name)
+	}
+}
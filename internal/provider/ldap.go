@@ -0,0 +1,280 @@
+package provider
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"merch_shop/internal/provider/ber"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// LDAP protocol operations (RFC 4511) and result codes used by the client.
+const (
+	ldapBindRequest      = 0
+	ldapBindResponse     = 1
+	ldapUnbindRequest    = 2
+	ldapSearchRequest    = 3
+	ldapSearchResultItem = 4
+	ldapSearchResultDone = 5
+	ldapSearchResultRef  = 19
+
+	ldapFilterEquality = 3
+
+	ldapSuccess            = 0
+	ldapSizeLimitExceeded  = 4
+	ldapInvalidCredentials = 49
+)
+
+// LDAPUser is the directory entry of a user who signed in.
+type LDAPUser struct {
+	DN          string
+	DisplayName string
+	Email       string
+	// Username is the value of the user attribute as the directory spells it.
+	Username string
+	// Groups are the DNs of the groups the user is a member of, from the memberOf attribute.
+	Groups []string
+}
+
+// LDAPError is a result other than success returned by the directory.
+type LDAPError struct {
+	ResultCode int64
+	Message    string
+}
+
+func (e *LDAPError) Error() string {
+	return fmt.Sprintf("ldap result code %d: %s", e.ResultCode, e.Message)
+}
+
+// LDAPClient checks passwords with an LDAP bind. The user's entry is found with a search, bound as the
+// search account when one is set, and the password is then checked by binding as that entry.
+type LDAPClient struct {
+	url           string
+	bindDN        string
+	bindPassword  string
+	baseDN        string
+	userAttribute string
+	timeout       time.Duration
+}
+
+func NewLDAPClient(url string, bindDN string, bindPassword string, baseDN string, userAttribute string, timeout time.Duration) *LDAPClient {
+	return &LDAPClient{
+		url:           url,
+		bindDN:        bindDN,
+		bindPassword:  bindPassword,
+		baseDN:        baseDN,
+		userAttribute: userAttribute,
+		timeout:       timeout,
+	}
+}
+
+// Authenticate returns the entry of the user, or nil when the username is unknown or the password is wrong.
+func (c *LDAPClient) Authenticate(username string, password string) (*LDAPUser, error) {
+	// A simple bind with an empty password is an anonymous bind, which most directories accept.
+	if username == "" || password == "" {
+		return nil, nil
+	}
+	conn, err := c.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.close()
+
+	if c.bindDN != "" {
+		if err := conn.bind(c.bindDN, c.bindPassword); err != nil {
+			return nil, fmt.Errorf("search account bind failed: %w", err)
+		}
+	}
+	users, err := conn.searchUser(c.baseDN, c.userAttribute, username)
+	if err != nil {
+		return nil, fmt.Errorf("user search failed: %w", err)
+	}
+	if len(users) != 1 {
+		return nil, nil
+	}
+	var ldapErr *LDAPError
+	err = conn.bind(users[0].DN, password)
+	if errors.As(err, &ldapErr) && ldapErr.ResultCode == ldapInvalidCredentials {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("user bind failed: %w", err)
+	}
+	return &users[0], nil
+}
+
+type ldapConn struct {
+	conn      net.Conn
+	reader    *bufio.Reader
+	messageID int64
+}
+
+func (c *LDAPClient) dial() (*ldapConn, error) {
+	parsed, err := url.Parse(c.url)
+	if err != nil {
+		return nil, fmt.Errorf("ldap url is invalid: %w", err)
+	}
+	dialer := &net.Dialer{Timeout: c.timeout}
+	var conn net.Conn
+	switch parsed.Scheme {
+	case "ldap":
+		conn, err = dialer.Dial("tcp", hostPort(parsed, "389"))
+	case "ldaps":
+		conn, err = tls.DialWithDialer(dialer, "tcp", hostPort(parsed, "636"), &tls.Config{ServerName: parsed.Hostname()})
+	default:
+		return nil, fmt.Errorf("ldap url has unsupported scheme %q", parsed.Scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if c.timeout > 0 {
+		conn.SetDeadline(time.Now().Add(c.timeout))
+	}
+	return &ldapConn{conn: conn, reader: bufio.NewReader(conn)}, nil
+}
+
+func hostPort(parsed *url.URL, defaultPort string) string {
+	if parsed.Port() != "" {
+		return parsed.Host
+	}
+	return net.JoinHostPort(parsed.Hostname(), defaultPort)
+}
+
+func (l *ldapConn) bind(dn string, password string) error {
+	request := ber.Sequence(ber.ClassApplication, ldapBindRequest,
+		ber.Int(ber.ClassUniversal, ber.TagInteger, 3),
+		ber.String(ber.ClassUniversal, ber.TagOctetString, dn),
+		ber.String(ber.ClassContext, 0, password),
+	)
+	if err := l.send(request); err != nil {
+		return err
+	}
+	response, err := l.receive()
+	if err != nil {
+		return err
+	}
+	if !response.Is(ber.ClassApplication, ldapBindResponse) {
+		return errors.New("unexpected response to bind")
+	}
+	return ldapResult(response)
+}
+
+func (l *ldapConn) searchUser(baseDN string, attribute string, value string) ([]LDAPUser, error) {
+	request := ber.Sequence(ber.ClassApplication, ldapSearchRequest,
+		ber.String(ber.ClassUniversal, ber.TagOctetString, baseDN),
+		ber.Int(ber.ClassUniversal, ber.TagEnumerated, 2), // whole subtree
+		ber.Int(ber.ClassUniversal, ber.TagEnumerated, 0), // never dereference aliases
+		ber.Int(ber.ClassUniversal, ber.TagInteger, 2),    // two entries tell that the username is ambiguous
+		ber.Int(ber.ClassUniversal, ber.TagInteger, 0),
+		ber.Bool(false),
+		ber.Sequence(ber.ClassContext, ldapFilterEquality,
+			ber.String(ber.ClassUniversal, ber.TagOctetString, attribute),
+			ber.String(ber.ClassUniversal, ber.TagOctetString, value),
+		),
+		ber.Sequence(ber.ClassUniversal, ber.TagSequence,
+			ber.String(ber.ClassUniversal, ber.TagOctetString, attribute),
+			ber.String(ber.ClassUniversal, ber.TagOctetString, "displayName"),
+			ber.String(ber.ClassUniversal, ber.TagOctetString, "mail"),
+			ber.String(ber.ClassUniversal, ber.TagOctetString, "memberOf"),
+		),
+	)
+	if err := l.send(request); err != nil {
+		return nil, err
+	}
+	var users []LDAPUser
+	for {
+		response, err := l.receive()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case response.Is(ber.ClassApplication, ldapSearchResultItem):
+			users = append(users, toLDAPUser(response, attribute))
+		case response.Is(ber.ClassApplication, ldapSearchResultRef):
+			// Referrals to other directories are not followed.
+		case response.Is(ber.ClassApplication, ldapSearchResultDone):
+			var ldapErr *LDAPError
+			// Exceeding the size limit means the username matched more than one entry.
+			if err := ldapResult(response); err != nil && !(errors.As(err, &ldapErr) && ldapErr.ResultCode == ldapSizeLimitExceeded) {
+				return nil, err
+			}
+			return users, nil
+		default:
+			return nil, errors.New("unexpected response to search")
+		}
+	}
+}
+
+func (l *ldapConn) close() {
+	l.send(&ber.Packet{Class: ber.ClassApplication, Tag: ldapUnbindRequest})
+	l.conn.Close()
+}
+
+func (l *ldapConn) send(operation *ber.Packet) error {
+	l.messageID++
+	message := ber.Sequence(ber.ClassUniversal, ber.TagSequence,
+		ber.Int(ber.ClassUniversal, ber.TagInteger, l.messageID),
+		operation,
+	)
+	_, err := l.conn.Write(message.Bytes())
+	return err
+}
+
+// receive returns the protocol operation of the next response to the last request.
+func (l *ldapConn) receive() (*ber.Packet, error) {
+	message, err := ber.Read(l.reader)
+	if err != nil {
+		return nil, err
+	}
+	if !message.Is(ber.ClassUniversal, ber.TagSequence) || len(message.Children) < 2 {
+		return nil, errors.New("malformed ldap message")
+	}
+	if message.Children[0].Int() != l.messageID {
+		return nil, errors.New("ldap response to another request")
+	}
+	return message.Children[1], nil
+}
+
+func ldapResult(response *ber.Packet) error {
+	if len(response.Children) < 3 {
+		return errors.New("malformed ldap result")
+	}
+	if code := response.Children[0].Int(); code != ldapSuccess {
+		return &LDAPError{ResultCode: code, Message: response.Children[2].String()}
+	}
+	return nil
+}
+
+func toLDAPUser(entry *ber.Packet, userAttribute string) LDAPUser {
+	user := LDAPUser{DN: entry.Child(0).String()}
+	if entry.Child(1) == nil {
+		return user
+	}
+	for _, attribute := range entry.Child(1).Children {
+		if attribute.Child(1) == nil {
+			continue
+		}
+		var values []string
+		for _, v := range attribute.Child(1).Children {
+			values = append(values, v.String())
+		}
+		if len(values) == 0 {
+			continue
+		}
+		switch name := strings.ToLower(attribute.Child(0).String()); {
+		case name == strings.ToLower(userAttribute):
+			user.Username = values[0]
+		case name == "displayname":
+			user.DisplayName = values[0]
+		case name == "mail":
+			user.Email = values[0]
+		case name == "memberof":
+			user.Groups = values
+		}
+	}
+	return user
+}
@@ -0,0 +1,95 @@
+package provider_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"merch_shop/internal/provider"
+	"merch_shop/internal/provider/ldaptest"
+	"testing"
+	"time"
+)
+
+const (
+	searchDN       = "cn=merch-shop,ou=services,dc=example,dc=com"
+	searchPassword = "search-secret"
+	adminsGroup    = "cn=shop-admins,ou=groups,dc=example,dc=com"
+)
+
+func newTestDirectory() *ldaptest.Server {
+	return ldaptest.NewServer(
+		ldaptest.Entry{DN: searchDN, Password: searchPassword},
+		ldaptest.Entry{
+			DN:       "uid=alice,ou=people,dc=example,dc=com",
+			Password: "alice-password",
+			Attributes: map[string][]string{
+				"uid":         {"alice"},
+				"displayName": {"Alice Smith"},
+				"mail":        {"alice@example.com"},
+				"memberOf":    {adminsGroup, "cn=staff,ou=groups,dc=example,dc=com"},
+			},
+		},
+		ldaptest.Entry{DN: "uid=bob,ou=people,dc=example,dc=com", Password: "bob-password", Attributes: map[string][]string{"uid": {"bob"}}},
+		ldaptest.Entry{DN: "uid=bob,ou=contractors,dc=example,dc=com", Password: "bob-password", Attributes: map[string][]string{"uid": {"bob"}}},
+	)
+}
+
+func TestLDAPClient_Authenticate(t *testing.T) {
+	directory := newTestDirectory()
+	defer directory.Close()
+	client := provider.NewLDAPClient(directory.URL, searchDN, searchPassword, "dc=example,dc=com", "uid", time.Second)
+
+	t.Run("Success", func(t *testing.T) {
+		user, err := client.Authenticate("alice", "alice-password")
+		assert.NoError(t, err)
+		assert.Equal(t, &provider.LDAPUser{
+			DN:          "uid=alice,ou=people,dc=example,dc=com",
+			DisplayName: "Alice Smith",
+			Email:       "alice@example.com",
+			Username:    "alice",
+			Groups:      []string{adminsGroup, "cn=staff,ou=groups,dc=example,dc=com"},
+		}, user)
+	})
+
+	t.Run("UsernameAsTheDirectorySpellsIt", func(t *testing.T) {
+		user, err := client.Authenticate("ALICE", "alice-password")
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", user.Username)
+	})
+
+	t.Run("WrongPassword", func(t *testing.T) {
+		user, err := client.Authenticate("alice", "wrong")
+		assert.NoError(t, err)
+		assert.Nil(t, user)
+	})
+
+	t.Run("UnknownUser", func(t *testing.T) {
+		user, err := client.Authenticate("carol", "alice-password")
+		assert.NoError(t, err)
+		assert.Nil(t, user)
+	})
+
+	t.Run("AmbiguousUser", func(t *testing.T) {
+		user, err := client.Authenticate("bob", "bob-password")
+		assert.NoError(t, err)
+		assert.Nil(t, user)
+	})
+
+	t.Run("EmptyPasswordIsNotSentToTheDirectory", func(t *testing.T) {
+		binds := len(directory.Binds())
+		user, err := client.Authenticate("alice", "")
+		assert.NoError(t, err)
+		assert.Nil(t, user)
+		assert.Len(t, directory.Binds(), binds)
+	})
+
+	t.Run("WrongSearchAccountPassword", func(t *testing.T) {
+		misconfigured := provider.NewLDAPClient(directory.URL, searchDN, "wrong", "dc=example,dc=com", "uid", time.Second)
+		_, err := misconfigured.Authenticate("alice", "alice-password")
+		assert.Error(t, err)
+	})
+
+	t.Run("DirectoryUnavailable", func(t *testing.T) {
+		unavailable := provider.NewLDAPClient("ldap://127.0.0.1:1", searchDN, searchPassword, "dc=example,dc=com", "uid", time.Second)
+		_, err := unavailable.Authenticate("alice", "alice-password")
+		assert.Error(t, err)
+	})
+}
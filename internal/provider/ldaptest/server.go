@@ -0,0 +1,270 @@
+// Package ldaptest runs an in-process LDAP directory for tests of the LDAP bind sign-in. It implements
+// simple binds and searches with equality, presence, and, or and not filters.
+package ldaptest
+
+import (
+	"bufio"
+	"merch_shop/internal/provider/ber"
+	"net"
+	"strings"
+	"sync"
+)
+
+const (
+	bindRequest      = 0
+	bindResponse     = 1
+	unbindRequest    = 2
+	searchRequest    = 3
+	searchResultItem = 4
+	searchResultDone = 5
+
+	success                 = 0
+	protocolError           = 2
+	sizeLimitExceeded       = 4
+	invalidCredentials      = 49
+	insufficientAccessRight = 50
+	unwillingToPerform      = 53
+)
+
+// Entry is a directory entry. Entries with a password can bind.
+type Entry struct {
+	DN         string
+	Password   string
+	Attributes map[string][]string
+}
+
+// Server is an LDAP directory listening on a local port. Searches require an authenticated bind.
+type Server struct {
+	URL string
+
+	listener net.Listener
+	mu       sync.Mutex
+	entries  []Entry
+	binds    []string
+	conns    map[net.Conn]bool
+	closed   bool
+	wg       sync.WaitGroup
+}
+
+func NewServer(entries ...Entry) *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	s := &Server{URL: "ldap://" + listener.Addr().String(), listener: listener, entries: entries, conns: map[net.Conn]bool{}}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// Close stops listening, drops open connections and waits for their handlers to finish.
+func (s *Server) Close() {
+	s.listener.Close()
+	s.mu.Lock()
+	s.closed = true
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// Binds returns the DNs of all binds attempted so far, successful or not.
+func (s *Server) Binds() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.binds...)
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[conn] = true
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	bound := ""
+	for {
+		message, err := ber.Read(reader)
+		if err != nil {
+			return
+		}
+		messageID := message.Child(0).Int()
+		request := message.Child(1)
+		reply := func(operation *ber.Packet) {
+			conn.Write(ber.Sequence(ber.ClassUniversal, ber.TagSequence,
+				ber.Int(ber.ClassUniversal, ber.TagInteger, messageID), operation).Bytes())
+		}
+		switch {
+		case request.Is(ber.ClassApplication, bindRequest):
+			dn, code := s.bind(request)
+			bound = dn
+			reply(result(bindResponse, code))
+		case request.Is(ber.ClassApplication, searchRequest):
+			if bound == "" {
+				reply(result(searchResultDone, insufficientAccessRight))
+				continue
+			}
+			entries, code := s.search(request)
+			for _, entry := range entries {
+				reply(entry)
+			}
+			reply(result(searchResultDone, code))
+		case request.Is(ber.ClassApplication, unbindRequest):
+			return
+		default:
+			reply(result(searchResultDone, protocolError))
+			return
+		}
+	}
+}
+
+// bind returns the DN bound to, which is empty for anonymous or failed binds.
+func (s *Server) bind(request *ber.Packet) (string, int64) {
+	dn := request.Child(1).String()
+	password := request.Child(2)
+	if !password.Is(ber.ClassContext, 0) {
+		return "", unwillingToPerform
+	}
+	if dn == "" && password.String() == "" {
+		return "", success
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.binds = append(s.binds, dn)
+	if password.String() == "" {
+		return "", unwillingToPerform
+	}
+	for _, entry := range s.entries {
+		if strings.EqualFold(entry.DN, dn) && entry.Password != "" && entry.Password == password.String() {
+			return entry.DN, success
+		}
+	}
+	return "", invalidCredentials
+}
+
+func (s *Server) search(request *ber.Packet) ([]*ber.Packet, int64) {
+	baseDN := strings.ToLower(request.Child(0).String())
+	sizeLimit := int(request.Child(3).Int())
+	filter := request.Child(6)
+	var requested []string
+	for _, v := range request.Child(7).Children {
+		requested = append(requested, v.String())
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var found []*ber.Packet
+	for _, entry := range s.entries {
+		dn := strings.ToLower(entry.DN)
+		if dn != baseDN && !strings.HasSuffix(dn, ","+baseDN) {
+			continue
+		}
+		if !matches(entry, filter) {
+			continue
+		}
+		if sizeLimit > 0 && len(found) == sizeLimit {
+			return found, sizeLimitExceeded
+		}
+		found = append(found, searchEntry(entry, requested))
+	}
+	return found, success
+}
+
+func matches(entry Entry, filter *ber.Packet) bool {
+	if filter == nil || filter.Class != ber.ClassContext {
+		return false
+	}
+	switch filter.Tag {
+	case 0:
+		for _, v := range filter.Children {
+			if !matches(entry, v) {
+				return false
+			}
+		}
+		return true
+	case 1:
+		for _, v := range filter.Children {
+			if matches(entry, v) {
+				return true
+			}
+		}
+		return false
+	case 2:
+		return !matches(entry, filter.Child(0))
+	case 3:
+		for _, v := range attribute(entry, filter.Child(0).String()) {
+			if strings.EqualFold(v, filter.Child(1).String()) {
+				return true
+			}
+		}
+		return false
+	case 7:
+		return len(attribute(entry, filter.String())) > 0
+	}
+	return false
+}
+
+func attribute(entry Entry, name string) []string {
+	for key, values := range entry.Attributes {
+		if strings.EqualFold(key, name) {
+			return values
+		}
+	}
+	return nil
+}
+
+func searchEntry(entry Entry, requested []string) *ber.Packet {
+	attributes := ber.Sequence(ber.ClassUniversal, ber.TagSequence)
+	for name, values := range entry.Attributes {
+		if len(requested) > 0 && !containsFold(requested, name) {
+			continue
+		}
+		set := ber.Sequence(ber.ClassUniversal, ber.TagSet)
+		for _, v := range values {
+			set.Children = append(set.Children, ber.String(ber.ClassUniversal, ber.TagOctetString, v))
+		}
+		attributes.Children = append(attributes.Children, ber.Sequence(ber.ClassUniversal, ber.TagSequence,
+			ber.String(ber.ClassUniversal, ber.TagOctetString, name), set))
+	}
+	return ber.Sequence(ber.ClassApplication, searchResultItem,
+		ber.String(ber.ClassUniversal, ber.TagOctetString, entry.DN), attributes)
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func result(operation int, code int64) *ber.Packet {
+	return ber.Sequence(ber.ClassApplication, operation,
+		ber.Int(ber.ClassUniversal, ber.TagEnumerated, code),
+		ber.String(ber.ClassUniversal, ber.TagOctetString, ""),
+		ber.String(ber.ClassUniversal, ber.TagOctetString, ""),
+	)
+}
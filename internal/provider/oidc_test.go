@@ -0,0 +1,69 @@
+package provider_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"merch_shop/internal/provider"
+	"merch_shop/internal/provider/oidctest"
+	"testing"
+)
+
+const redirectURL = "https://shop.example.com/api/auth/oidc/callback"
+
+func TestOIDCProvider_AuthorizationCodeFlow(t *testing.T) {
+	idp := oidctest.NewServer("merch-shop", "client-secret")
+	defer idp.Close()
+	oidc := provider.NewOIDCProvider(idp.URL, "merch-shop", "client-secret", redirectURL, nil)
+	identity := oidctest.Identity{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true, Name: "Alice", PreferredUsername: "alice"}
+
+	t.Run("Success", func(t *testing.T) {
+		authorizationURL, err := oidc.AuthCodeURL("state", "nonce", "verifier")
+		assert.NoError(t, err)
+		code, state, err := idp.Authorize(authorizationURL, identity)
+		assert.NoError(t, err)
+		assert.Equal(t, "state", state)
+
+		result, err := oidc.Exchange(code, "verifier", "nonce")
+		assert.NoError(t, err)
+		assert.Equal(t, provider.OIDCIdentity{
+			Subject:           "sub-1",
+			Email:             "alice@example.com",
+			EmailVerified:     true,
+			Name:              "Alice",
+			PreferredUsername: "alice",
+		}, result)
+
+		_, err = oidc.Exchange(code, "verifier", "nonce")
+		assert.Error(t, err, "codes can only be redeemed once")
+	})
+
+	t.Run("WrongCodeVerifier", func(t *testing.T) {
+		authorizationURL, err := oidc.AuthCodeURL("state", "nonce", "verifier")
+		assert.NoError(t, err)
+		code, _, err := idp.Authorize(authorizationURL, identity)
+		assert.NoError(t, err)
+
+		_, err = oidc.Exchange(code, "another-verifier", "nonce")
+		assert.Error(t, err)
+	})
+
+	t.Run("WrongNonce", func(t *testing.T) {
+		authorizationURL, err := oidc.AuthCodeURL("state", "nonce", "verifier")
+		assert.NoError(t, err)
+		code, _, err := idp.Authorize(authorizationURL, identity)
+		assert.NoError(t, err)
+
+		_, err = oidc.Exchange(code, "verifier", "replayed-nonce")
+		assert.EqualError(t, err, "id token nonce does not match")
+	})
+
+	t.Run("WrongClientSecret", func(t *testing.T) {
+		other := provider.NewOIDCProvider(idp.URL, "merch-shop", "wrong-secret", redirectURL, nil)
+		authorizationURL, err := other.AuthCodeURL("state", "nonce", "verifier")
+		assert.NoError(t, err)
+		code, _, err := idp.Authorize(authorizationURL, identity)
+		assert.NoError(t, err)
+
+		_, err = other.Exchange(code, "verifier", "nonce")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rfcSecret is the SHA1 key of the RFC 6238 test vectors, "12345678901234567890", base32 encoded.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	// RFC 6238 appendix B lists 8 digit codes; these are their last 6 digits.
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for unix, expected := range vectors {
+		code, err := TOTPCode(rfcSecret, TOTPStep(time.Unix(unix, 0)))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, code, "time %d", unix)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	code, _ := TOTPCode(rfcSecret, TOTPStep(now))
+
+	t.Run("CurrentPeriod", func(t *testing.T) {
+		step, ok := ValidateTOTP(rfcSecret, code, now)
+		assert.True(t, ok)
+		assert.Equal(t, TOTPStep(now), step)
+	})
+
+	t.Run("ClockSkew", func(t *testing.T) {
+		_, ok := ValidateTOTP(rfcSecret, code, now.Add(30*time.Second))
+		assert.True(t, ok)
+		_, ok = ValidateTOTP(rfcSecret, code, now.Add(90*time.Second))
+		assert.False(t, ok)
+	})
+
+	t.Run("WrongCode", func(t *testing.T) {
+		_, ok := ValidateTOTP(rfcSecret, "000000", now)
+		assert.False(t, ok)
+	})
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	assert.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	uri := TOTPProvisioningURI("Merch Shop", "alice", secret)
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Merch%20Shop:alice?"))
+	assert.Contains(t, uri, "secret="+secret)
+	assert.Contains(t, uri, "issuer=Merch+Shop")
+}
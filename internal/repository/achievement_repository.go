@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"merch_shop/internal/entity"
+)
+
+const (
+	AchievementCoinsSent         = "coins_sent"
+	AchievementCoinsReceived     = "coins_received"
+	AchievementColleaguesThanked = "colleagues_thanked"
+	AchievementPurchases         = "purchases"
+)
+
+// AchievementMinThanks is the least number of net coins a user must send a colleague to have thanked them.
+const AchievementMinThanks = 10
+
+type GormAchievementRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAchievementRepository(db *gorm.DB) *GormAchievementRepository {
+	return &GormAchievementRepository{
+		db: db,
+	}
+}
+
+func (repo *GormAchievementRepository) GetUserBadges(userId uint) ([]entity.UserBadge, error) {
+	var badges []entity.UserBadge
+	err := repo.db.Where("user_id = ?", userId).Order("created_at").Find(&badges).Error
+	if err != nil {
+		return nil, err
+	}
+	return badges, nil
+}
+
+// AwardBadge reports false when the user already has the badge.
+func (repo *GormAchievementRepository) AwardBadge(badge *entity.UserBadge) (bool, error) {
+	result := repo.db.Clauses(clause.OnConflict{DoNothing: true}).Create(badge)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetUserMetric computes the user's all-time value of an achievement metric. Coin metrics are read
+// from the daily transfer totals and netted per colleague, so coins sent back and forth between two
+// users count for neither; a colleague counts as thanked once the net coins sent to them reach
+// AchievementMinThanks. Purchases that were refunded or paid from a team wallet are not counted.
+func (repo *GormAchievementRepository) GetUserMetric(userId uint, metric string) (uint, error) {
+	var value uint
+	var query *gorm.DB
+	switch metric {
+	case AchievementCoinsSent:
+		query = repo.db.Table("(?) AS pairs", repo.netTransfers(userId, false)).Select("COALESCE(SUM(net), 0)").Where("net > 0")
+	case AchievementCoinsReceived:
+		query = repo.db.Table("(?) AS pairs", repo.netTransfers(userId, true)).Select("COALESCE(SUM(net), 0)").Where("net > 0")
+	case AchievementColleaguesThanked:
+		query = repo.db.Table("(?) AS pairs", repo.netTransfers(userId, false)).Select("COUNT(*)").Where("net >= ?", AchievementMinThanks)
+	case AchievementPurchases:
+		query = repo.db.Model(&entity.Purchase{}).Select("COUNT(*)").Where("buyer_id = ? AND wallet_id IS NULL AND refunded_at IS NULL", userId)
+	default:
+		return 0, fmt.Errorf("unknown achievement metric %q", metric)
+	}
+	if err := query.Scan(&value).Error; err != nil {
+		return 0, err
+	}
+	return value, nil
+}
+
+// netTransfers sums, per colleague, the coins the user received from them minus the coins the user
+// sent them, or the other way round when received is false.
+func (repo *GormAchievementRepository) netTransfers(userId uint, received bool) *gorm.DB {
+	userColumn, peerColumn := "from_id", "to_id"
+	if received {
+		userColumn, peerColumn = peerColumn, userColumn
+	}
+	counted := repo.db.Model(&entity.DailyTransfer{}).Select(peerColumn+" AS peer, amount").Where(userColumn+" = ?", userId)
+	returned := repo.db.Model(&entity.DailyTransfer{}).Select(userColumn+" AS peer, -amount AS amount").Where(peerColumn+" = ?", userId)
+	return repo.db.Table("(? UNION ALL ?) AS flows", counted, returned).Select("peer, SUM(amount) AS net").Group("peer")
+}
+
+func (repo *GormAchievementRepository) GetUserIds() ([]uint, error) {
+	var userIds []uint
+	err := repo.db.Model(&entity.User{}).Order("id").Pluck("id", &userIds).Error
+	if err != nil {
+		return nil, err
+	}
+	return userIds, nil
+}
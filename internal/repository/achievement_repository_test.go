@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"testing"
+	"time"
+)
+
+func setupAchievementDB() *gorm.DB {
+	db, _ := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	db.AutoMigrate(&entity.User{}, &entity.Item{}, &entity.Transaction{}, &entity.DailyTransfer{},
+		&entity.Purchase{}, &entity.UserBadge{})
+	return db
+}
+
+func TestGormAchievementRepository_GetUserMetric(t *testing.T) {
+	db := setupAchievementDB()
+	repo := NewGormAchievementRepository(db)
+	transactionRepo := NewGormTransactionRepository(db)
+
+	alice := &entity.User{Name: "alice"}
+	bob := &entity.User{Name: "bob"}
+	carol := &entity.User{Name: "carol"}
+	dave := &entity.User{Name: "dave"}
+	for _, user := range []*entity.User{alice, bob, carol, dave} {
+		db.Create(user)
+	}
+	for _, transaction := range []entity.Transaction{
+		{FromId: alice.ID, ToId: bob.ID, Amount: 100},
+		{FromId: alice.ID, ToId: bob.ID, Amount: 50},
+		{FromId: alice.ID, ToId: carol.ID, Amount: 20},
+		{FromId: carol.ID, ToId: alice.ID, Amount: 5},
+		{FromId: alice.ID, ToId: dave.ID, Amount: 1},
+	} {
+		assert.NoError(t, transactionRepo.CreateTransaction(&transaction))
+	}
+	refundedAt := time.Now()
+	db.Create(&entity.Purchase{BuyerId: alice.ID, OwnerId: alice.ID})
+	db.Create(&entity.Purchase{BuyerId: alice.ID, OwnerId: bob.ID})
+	db.Create(&entity.Purchase{BuyerId: alice.ID, OwnerId: alice.ID, RefundedAt: &refundedAt})
+
+	for metric, expected := range map[string]uint{
+		AchievementCoinsSent:         166,
+		AchievementCoinsReceived:     0,
+		AchievementColleaguesThanked: 2,
+		AchievementPurchases:         2,
+	} {
+		value, err := repo.GetUserMetric(alice.ID, metric)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, metric)
+	}
+
+	received, err := repo.GetUserMetric(bob.ID, AchievementCoinsReceived)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(150), received)
+
+	// Coins sent straight back do not count for either user.
+	assert.NoError(t, transactionRepo.CreateTransaction(&entity.Transaction{FromId: bob.ID, ToId: alice.ID, Amount: 150}))
+	received, err = repo.GetUserMetric(bob.ID, AchievementCoinsReceived)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(0), received)
+	thanked, err := repo.GetUserMetric(alice.ID, AchievementColleaguesThanked)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), thanked)
+
+	_, err = repo.GetUserMetric(alice.ID, "balance")
+	assert.Error(t, err)
+}
+
+func TestGormAchievementRepository_AwardBadge(t *testing.T) {
+	db := setupAchievementDB()
+	repo := NewGormAchievementRepository(db)
+
+	created, err := repo.AwardBadge(&entity.UserBadge{UserID: 1, Badge: "generous", Bonus: 10})
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	created, err = repo.AwardBadge(&entity.UserBadge{UserID: 1, Badge: "generous", Bonus: 10})
+	assert.NoError(t, err)
+	assert.False(t, created)
+
+	badges, err := repo.GetUserBadges(1)
+	assert.NoError(t, err)
+	assert.Len(t, badges, 1)
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"time"
+)
+
+type GormAPIKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAPIKeyRepository(db *gorm.DB) *GormAPIKeyRepository {
+	return &GormAPIKeyRepository{
+		db: db,
+	}
+}
+
+func (repo *GormAPIKeyRepository) CreateAPIKey(key *entity.APIKey) error {
+	return repo.db.Create(key).Error
+}
+
+func (repo *GormAPIKeyRepository) UpdateAPIKey(key *entity.APIKey) error {
+	return repo.db.Save(key).Error
+}
+
+func (repo *GormAPIKeyRepository) FindAPIKeyById(id uint) (*entity.APIKey, error) {
+	return repo.findAPIKey("id = ?", id)
+}
+
+func (repo *GormAPIKeyRepository) FindAPIKeyByHash(keyHash string) (*entity.APIKey, error) {
+	return repo.findAPIKey("key_hash = ?", keyHash)
+}
+
+func (repo *GormAPIKeyRepository) GetAPIKeys(userId uint) ([]entity.APIKey, error) {
+	var keys []entity.APIKey
+	err := repo.db.Where("user_id = ?", userId).Order("id").Find(&keys).Error
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// TouchAPIKey records that the key was used at now, unless that was already recorded after before.
+func (repo *GormAPIKeyRepository) TouchAPIKey(id uint, now time.Time, before time.Time) error {
+	return repo.db.Model(&entity.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, before).
+		Update("last_used_at", now).Error
+}
+
+func (repo *GormAPIKeyRepository) findAPIKey(query string, args ...interface{}) (*entity.APIKey, error) {
+	key := new(entity.APIKey)
+	err := repo.db.Where(query, args...).First(key).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return key, nil
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"testing"
+	"time"
+)
+
+func TestGormAPIKeyRepository_TouchAPIKey(t *testing.T) {
+	db, _ := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	db.AutoMigrate(&entity.APIKey{})
+	repo := NewGormAPIKeyRepository(db)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	key := &entity.APIKey{UserID: 1, Name: "bot", KeyHash: "hash", Scopes: entity.ScopeCoinsGrant}
+	assert.NoError(t, repo.CreateAPIKey(key))
+
+	assert.NoError(t, repo.TouchAPIKey(key.ID, now, now.Add(-time.Minute)))
+	assert.NoError(t, repo.TouchAPIKey(key.ID, now.Add(30*time.Second), now.Add(-30*time.Second)))
+	found, err := repo.FindAPIKeyByHash("hash")
+	assert.NoError(t, err)
+	assert.True(t, now.Equal(*found.LastUsedAt))
+
+	later := now.Add(2 * time.Minute)
+	assert.NoError(t, repo.TouchAPIKey(key.ID, later, later.Add(-time.Minute)))
+	found, _ = repo.FindAPIKeyById(key.ID)
+	assert.True(t, later.Equal(*found.LastUsedAt))
+
+	missing, err := repo.FindAPIKeyByHash("other")
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"time"
+)
+
+type GormGroupPurchaseRepository struct {
+	db *gorm.DB
+}
+
+func NewGormGroupPurchaseRepository(db *gorm.DB) *GormGroupPurchaseRepository {
+	return &GormGroupPurchaseRepository{
+		db: db,
+	}
+}
+
+func (repo *GormGroupPurchaseRepository) CreateGroupPurchase(groupPurchase *entity.GroupPurchase) error {
+	return repo.db.Omit("Organizer", "Recipient", "Item", "Variant", "Pledges").Create(groupPurchase).Error
+}
+
+func (repo *GormGroupPurchaseRepository) UpdateGroupPurchase(groupPurchase *entity.GroupPurchase) error {
+	return repo.db.Omit("Organizer", "Recipient", "Item", "Variant", "Pledges").Save(groupPurchase).Error
+}
+
+func (repo *GormGroupPurchaseRepository) FindGroupPurchaseById(groupPurchaseId uint) (*entity.GroupPurchase, error) {
+	groupPurchase := new(entity.GroupPurchase)
+	err := repo.withDetails().First(groupPurchase, groupPurchaseId).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return groupPurchase, nil
+}
+
+func (repo *GormGroupPurchaseRepository) GetOpenGroupPurchases() ([]entity.GroupPurchase, error) {
+	var groupPurchases []entity.GroupPurchase
+	err := repo.withDetails().
+		Where("status = ?", entity.GroupPurchaseOpen).
+		Order("deadline").
+		Find(&groupPurchases).Error
+	if err != nil {
+		return nil, err
+	}
+	return groupPurchases, nil
+}
+
+// GetOverdueGroupPurchases returns open group purchases whose deadline has passed.
+func (repo *GormGroupPurchaseRepository) GetOverdueGroupPurchases(now time.Time) ([]entity.GroupPurchase, error) {
+	var groupPurchases []entity.GroupPurchase
+	err := repo.withDetails().
+		Where("status = ? AND deadline <= ?", entity.GroupPurchaseOpen, now).
+		Find(&groupPurchases).Error
+	if err != nil {
+		return nil, err
+	}
+	return groupPurchases, nil
+}
+
+func (repo *GormGroupPurchaseRepository) CreatePledge(pledge *entity.Pledge) error {
+	return repo.db.Omit("User").Create(pledge).Error
+}
+
+func (repo *GormGroupPurchaseRepository) withDetails() *gorm.DB {
+	return repo.db.Joins("Organizer").Joins("Recipient").Joins("Item").Joins("Variant").Preload("Pledges.User")
+}
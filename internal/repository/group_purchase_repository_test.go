@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"testing"
+	"time"
+)
+
+func setupGroupPurchaseDB() *gorm.DB {
+	db, _ := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	db.AutoMigrate(&entity.User{}, &entity.Item{}, &entity.ItemVariant{}, &entity.GroupPurchase{}, &entity.Pledge{})
+	return db
+}
+
+func TestGormGroupPurchaseRepository(t *testing.T) {
+	db := setupGroupPurchaseDB()
+	repo := NewGormGroupPurchaseRepository(db)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	alice := &entity.User{Name: "alice"}
+	bob := &entity.User{Name: "bob"}
+	item := &entity.Item{Name: "pink-hoody", Price: 500}
+	db.Create(alice)
+	db.Create(bob)
+	db.Create(item)
+
+	overdue := &entity.GroupPurchase{OrganizerId: alice.ID, RecipientId: bob.ID, ItemID: item.ID, Price: 500, Deadline: now.Add(-time.Hour)}
+	running := &entity.GroupPurchase{OrganizerId: alice.ID, RecipientId: bob.ID, ItemID: item.ID, Price: 500, Deadline: now.Add(time.Hour)}
+	assert.NoError(t, repo.CreateGroupPurchase(overdue))
+	assert.NoError(t, repo.CreateGroupPurchase(running))
+	assert.NoError(t, repo.CreatePledge(&entity.Pledge{GroupPurchaseID: running.ID, UserID: alice.ID, Amount: 100}))
+
+	found, err := repo.FindGroupPurchaseById(running.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", found.Organizer.Name)
+	assert.Equal(t, "bob", found.Recipient.Name)
+	assert.Equal(t, "pink-hoody", found.Item.Name)
+	assert.Len(t, found.Pledges, 1)
+	assert.Equal(t, "alice", found.Pledges[0].User.Name)
+
+	groupPurchases, err := repo.GetOverdueGroupPurchases(now)
+	assert.NoError(t, err)
+	assert.Len(t, groupPurchases, 1)
+	assert.Equal(t, overdue.ID, groupPurchases[0].ID)
+
+	overdue.Status = entity.GroupPurchaseExpired
+	assert.NoError(t, repo.UpdateGroupPurchase(overdue))
+
+	groupPurchases, err = repo.GetOpenGroupPurchases()
+	assert.NoError(t, err)
+	assert.Len(t, groupPurchases, 1)
+	assert.Equal(t, running.ID, groupPurchases[0].ID)
+}
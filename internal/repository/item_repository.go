@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"time"
+)
+
+type GormItemRepository struct {
+	db *gorm.DB
+}
+
+func NewGormItemRepository(db *gorm.DB) *GormItemRepository {
+	return &GormItemRepository{
+		db: db,
+	}
+}
+
+// SetItemCategory changes only the item's category, leaving its stock to concurrent purchases.
+func (repo *GormItemRepository) SetItemCategory(itemId uint, category string) error {
+	return repo.db.Model(&entity.Item{}).Where("id = ?", itemId).Update("category", category).Error
+}
+
+func (repo *GormItemRepository) SetPurchaseLimit(itemId uint, purchaseLimit uint) error {
+	return repo.db.Model(&entity.Item{}).Where("id = ?", itemId).Update("purchase_limit", purchaseLimit).Error
+}
+
+// DecrementStock takes one unit from a tracked stock and reports false when it is sold out.
+func (repo *GormItemRepository) DecrementStock(itemId uint) (bool, error) {
+	result := repo.db.Model(&entity.Item{}).
+		Where("id = ? AND stock > 0", itemId).
+		Update("stock", gorm.Expr("stock - 1"))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// AddStock puts units back into a tracked stock; items without stock tracking are left untouched.
+func (repo *GormItemRepository) AddStock(itemId uint, quantity uint) error {
+	return repo.db.Model(&entity.Item{}).
+		Where("id = ? AND stock IS NOT NULL", itemId).
+		Update("stock", gorm.Expr("stock + ?", quantity)).Error
+}
+
+// RestockItem adds units to the stock, starting stock tracking when the item had none.
+func (repo *GormItemRepository) RestockItem(itemId uint, quantity uint) error {
+	return repo.db.Model(&entity.Item{}).
+		Where("id = ?", itemId).
+		Update("stock", gorm.Expr("COALESCE(stock, 0) + ?", quantity)).Error
+}
+
+func (repo *GormItemRepository) CreateVariant(variant *entity.ItemVariant) error {
+	return repo.db.Create(variant).Error
+}
+
+func (repo *GormItemRepository) FindVariant(itemId uint, name string) (*entity.ItemVariant, error) {
+	variant := new(entity.ItemVariant)
+	err := repo.db.Where("item_id = ? AND name = ?", itemId, name).First(variant).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return variant, nil
+}
+
+func (repo *GormItemRepository) CountVariants(itemId uint) (int64, error) {
+	var count int64
+	err := repo.db.Model(&entity.ItemVariant{}).Where("item_id = ?", itemId).Count(&count).Error
+	return count, err
+}
+
+func (repo *GormItemRepository) DecrementVariantStock(variantId uint) (bool, error) {
+	result := repo.db.Model(&entity.ItemVariant{}).
+		Where("id = ? AND stock > 0", variantId).
+		Update("stock", gorm.Expr("stock - 1"))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (repo *GormItemRepository) AddVariantStock(variantId uint, quantity uint) error {
+	return repo.db.Model(&entity.ItemVariant{}).
+		Where("id = ? AND stock IS NOT NULL", variantId).
+		Update("stock", gorm.Expr("stock + ?", quantity)).Error
+}
+
+func (repo *GormItemRepository) RestockVariant(variantId uint, quantity uint) error {
+	return repo.db.Model(&entity.ItemVariant{}).
+		Where("id = ?", variantId).
+		Update("stock", gorm.Expr("COALESCE(stock, 0) + ?", quantity)).Error
+}
+
+// GetItems lists items of the category that carry all of the tags; empty filters match every item.
+func (repo *GormItemRepository) GetItems(category string, tags []string) ([]entity.Item, error) {
+	var items []entity.Item
+	query := repo.db.Preload("Tags")
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+	if len(tags) > 0 {
+		query = query.Where("id IN (?)", repo.db.Model(&entity.ItemTag{}).
+			Select("item_id").
+			Where("tag IN ?", tags).
+			Group("item_id").
+			Having("COUNT(DISTINCT tag) = ?", len(tags)))
+	}
+	err := query.Order("name").Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (repo *GormItemRepository) ReplaceItemTags(itemId uint, tags []string) error {
+	if err := repo.db.Unscoped().Where("item_id = ?", itemId).Delete(&entity.ItemTag{}).Error; err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	itemTags := make([]entity.ItemTag, 0, len(tags))
+	for _, tag := range tags {
+		itemTags = append(itemTags, entity.ItemTag{ItemID: itemId, Tag: tag})
+	}
+	return repo.db.Create(&itemTags).Error
+}
+
+func (repo *GormItemRepository) CreateCategory(category *entity.Category) error {
+	return repo.db.Create(category).Error
+}
+
+func (repo *GormItemRepository) FindCategoryByName(name string) (*entity.Category, error) {
+	category := new(entity.Category)
+	err := repo.db.Where("name = ?", name).First(category).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return category, nil
+}
+
+func (repo *GormItemRepository) GetCategories() ([]entity.Category, error) {
+	var categories []entity.Category
+	err := repo.db.Order("name").Find(&categories).Error
+	if err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (repo *GormItemRepository) CreateItemPrice(price *entity.ItemPrice) error {
+	return repo.db.Omit("Item").Create(price).Error
+}
+
+// FindEffectivePrice returns the history entry in effect at the given moment, or nil when the item has no history yet.
+func (repo *GormItemRepository) FindEffectivePrice(itemId uint, at time.Time) (*entity.ItemPrice, error) {
+	price := new(entity.ItemPrice)
+	err := repo.db.Where("item_id = ? AND effective_from <= ?", itemId, at).
+		Order("effective_from DESC").
+		First(price).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return price, nil
+}
+
+func (repo *GormItemRepository) GetItemPrices(itemId uint) ([]entity.ItemPrice, error) {
+	var prices []entity.ItemPrice
+	err := repo.db.Where("item_id = ?", itemId).Order("effective_from").Find(&prices).Error
+	if err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"testing"
+	"time"
+)
+
+func setupItemDB() *gorm.DB {
+	db, _ := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	db.AutoMigrate(&entity.Item{}, &entity.ItemVariant{}, &entity.ItemPrice{}, &entity.ItemTag{}, &entity.Category{})
+	return db
+}
+
+func TestGormItemRepository_Stock(t *testing.T) {
+	db := setupItemDB()
+	repo := NewGormItemRepository(db)
+
+	stock := uint(1)
+	limited := &entity.Item{Name: "pink-hoody", Price: 500, Stock: &stock}
+	unlimited := &entity.Item{Name: "pen", Price: 10}
+	db.Create(limited)
+	db.Create(unlimited)
+
+	t.Run("DecrementUntilSoldOut", func(t *testing.T) {
+		inStock, err := repo.DecrementStock(limited.ID)
+		assert.NoError(t, err)
+		assert.True(t, inStock)
+
+		inStock, err = repo.DecrementStock(limited.ID)
+		assert.NoError(t, err)
+		assert.False(t, inStock)
+	})
+
+	t.Run("AddStock", func(t *testing.T) {
+		assert.NoError(t, repo.AddStock(limited.ID, 3))
+		assert.NoError(t, repo.AddStock(unlimited.ID, 3))
+
+		var limitedItem, unlimitedItem entity.Item
+		db.First(&limitedItem, limited.ID)
+		assert.Equal(t, uint(3), *limitedItem.Stock)
+		db.First(&unlimitedItem, unlimited.ID)
+		assert.Nil(t, unlimitedItem.Stock)
+	})
+
+	t.Run("RestockItem", func(t *testing.T) {
+		notebook := &entity.Item{Name: "notebook", Price: 10}
+		db.Create(notebook)
+		assert.NoError(t, repo.RestockItem(notebook.ID, 5))
+		assert.NoError(t, repo.RestockItem(notebook.ID, 2))
+		assert.NoError(t, repo.SetPurchaseLimit(notebook.ID, 1))
+
+		var restocked entity.Item
+		db.First(&restocked, notebook.ID)
+		assert.Equal(t, uint(7), *restocked.Stock)
+		assert.Equal(t, uint(1), restocked.PurchaseLimit)
+	})
+
+	t.Run("SetItemCategory", func(t *testing.T) {
+		assert.NoError(t, repo.SetItemCategory(limited.ID, "apparel"))
+
+		var limitedItem entity.Item
+		db.First(&limitedItem, limited.ID)
+		assert.Equal(t, "apparel", limitedItem.Category)
+		assert.Equal(t, uint(3), *limitedItem.Stock)
+	})
+}
+
+func TestGormItemRepository_Variants(t *testing.T) {
+	db := setupItemDB()
+	repo := NewGormItemRepository(db)
+
+	item := &entity.Item{Name: "hoody", Price: 300}
+	db.Create(item)
+	stock := uint(1)
+	assert.NoError(t, repo.CreateVariant(&entity.ItemVariant{ItemID: item.ID, Name: "XL", PriceDelta: 20, Stock: &stock}))
+
+	count, err := repo.CountVariants(item.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	variant, err := repo.FindVariant(item.ID, "XL")
+	assert.NoError(t, err)
+	assert.Equal(t, 20, variant.PriceDelta)
+
+	missing, err := repo.FindVariant(item.ID, "XS")
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+
+	inStock, err := repo.DecrementVariantStock(variant.ID)
+	assert.NoError(t, err)
+	assert.True(t, inStock)
+	inStock, err = repo.DecrementVariantStock(variant.ID)
+	assert.NoError(t, err)
+	assert.False(t, inStock)
+}
+
+func TestGormItemRepository_FindEffectivePrice(t *testing.T) {
+	db := setupItemDB()
+	repo := NewGormItemRepository(db)
+	now := time.Now()
+
+	item := &entity.Item{Name: "cup", Price: 20}
+	db.Create(item)
+
+	found, err := repo.FindEffectivePrice(item.ID, now)
+	assert.NoError(t, err)
+	assert.Nil(t, found)
+
+	assert.NoError(t, repo.CreateItemPrice(&entity.ItemPrice{ItemID: item.ID, Price: 20, EffectiveFrom: now.Add(-time.Hour * 48)}))
+	assert.NoError(t, repo.CreateItemPrice(&entity.ItemPrice{ItemID: item.ID, Price: 25, EffectiveFrom: now.Add(-time.Hour)}))
+	assert.NoError(t, repo.CreateItemPrice(&entity.ItemPrice{ItemID: item.ID, Price: 30, EffectiveFrom: now.Add(time.Hour)}))
+
+	found, err = repo.FindEffectivePrice(item.ID, now)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(25), found.Price)
+
+	found, err = repo.FindEffectivePrice(item.ID, now.Add(time.Hour*2))
+	assert.NoError(t, err)
+	assert.Equal(t, uint(30), found.Price)
+
+	prices, err := repo.GetItemPrices(item.ID)
+	assert.NoError(t, err)
+	assert.Len(t, prices, 3)
+	assert.Equal(t, uint(20), prices[0].Price)
+}
+
+func TestGormItemRepository_GetItems(t *testing.T) {
+	db := setupItemDB()
+	repo := NewGormItemRepository(db)
+
+	hoody := &entity.Item{Name: "hoody", Category: "apparel"}
+	socks := &entity.Item{Name: "socks", Category: "apparel"}
+	pen := &entity.Item{Name: "pen", Category: "stationery"}
+	db.Create(hoody)
+	db.Create(socks)
+	db.Create(pen)
+	assert.NoError(t, repo.ReplaceItemTags(hoody.ID, []string{"new", "sale"}))
+	assert.NoError(t, repo.ReplaceItemTags(socks.ID, []string{"sale"}))
+	assert.NoError(t, repo.ReplaceItemTags(pen.ID, []string{"new"}))
+
+	items, err := repo.GetItems("apparel", nil)
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+
+	items, err = repo.GetItems("", []string{"new", "sale"})
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, "hoody", items[0].Name)
+	assert.Len(t, items[0].Tags, 2)
+
+	assert.NoError(t, repo.ReplaceItemTags(hoody.ID, []string{"new"}))
+	items, err = repo.GetItems("apparel", []string{"new"})
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+
+	items, err = repo.GetItems("", []string{"sale"})
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, "socks", items[0].Name)
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"fmt"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"time"
+)
+
+const (
+	LeaderboardReceived   = "received"
+	LeaderboardSent       = "sent"
+	LeaderboardColleagues = "colleagues"
+)
+
+type LeaderboardEntry struct {
+	UserId uint
+	Name   string
+	Value  uint
+}
+
+type GormLeaderboardRepository struct {
+	db *gorm.DB
+}
+
+func NewGormLeaderboardRepository(db *gorm.DB) *GormLeaderboardRepository {
+	return &GormLeaderboardRepository{
+		db: db,
+	}
+}
+
+// GetLeaderboard ranks users who did not opt out by the metric over the days in [from, to),
+// reading the daily transfer totals rather than individual transactions.
+func (repo *GormLeaderboardRepository) GetLeaderboard(metric string, from time.Time, to time.Time, limit int) ([]LeaderboardEntry, error) {
+	var userColumn, value string
+	switch metric {
+	case LeaderboardReceived:
+		userColumn, value = "to_id", "SUM(daily_transfers.amount)"
+	case LeaderboardSent:
+		userColumn, value = "from_id", "SUM(daily_transfers.amount)"
+	case LeaderboardColleagues:
+		userColumn, value = "from_id", "COUNT(DISTINCT daily_transfers.to_id)"
+	default:
+		return nil, fmt.Errorf("unknown leaderboard metric %q", metric)
+	}
+
+	var entries []LeaderboardEntry
+	err := repo.db.Model(&entity.DailyTransfer{}).
+		Select("users.id AS user_id, users.name AS name, "+value+" AS value").
+		Joins("JOIN users ON users.id = daily_transfers."+userColumn).
+		Where("daily_transfers.day >= ? AND daily_transfers.day < ?", from, to).
+		Where("users.leaderboard_opt_out = ? AND users.deleted_at IS NULL", false).
+		Group("users.id, users.name").
+		Order("value DESC, users.name").
+		Limit(limit).
+		Scan(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"testing"
+	"time"
+)
+
+func setupLeaderboardDB() *gorm.DB {
+	db, _ := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	db.AutoMigrate(&entity.User{}, &entity.Transaction{}, &entity.DailyTransfer{})
+	return db
+}
+
+func TestGormLeaderboardRepository_GetLeaderboard(t *testing.T) {
+	db := setupLeaderboardDB()
+	repo := NewGormLeaderboardRepository(db)
+	transactionRepo := NewGormTransactionRepository(db)
+	from := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	alice := &entity.User{Name: "alice"}
+	bob := &entity.User{Name: "bob"}
+	carol := &entity.User{Name: "carol"}
+	dave := &entity.User{Name: "dave", LeaderboardOptOut: true}
+	for _, user := range []*entity.User{alice, bob, carol, dave} {
+		db.Create(user)
+	}
+
+	transfer := func(from, to *entity.User, amount uint, at time.Time) {
+		err := transactionRepo.CreateTransaction(&entity.Transaction{Model: gorm.Model{CreatedAt: at}, FromId: from.ID, ToId: to.ID, Amount: amount})
+		assert.NoError(t, err)
+	}
+	inRange := from.Add(time.Hour * 24 * 3)
+	transfer(alice, bob, 100, inRange)
+	transfer(alice, bob, 50, inRange.Add(time.Hour))
+	transfer(alice, carol, 20, inRange.Add(time.Hour*24))
+	transfer(carol, bob, 10, inRange)
+	transfer(bob, dave, 500, inRange)
+	transfer(dave, carol, 70, inRange)
+	transfer(carol, alice, 1000, to)
+
+	received, err := repo.GetLeaderboard(LeaderboardReceived, from, to, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []LeaderboardEntry{
+		{UserId: bob.ID, Name: "bob", Value: 160},
+		{UserId: carol.ID, Name: "carol", Value: 90},
+	}, received)
+
+	sent, err := repo.GetLeaderboard(LeaderboardSent, from, to, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []LeaderboardEntry{{UserId: bob.ID, Name: "bob", Value: 500}}, sent)
+
+	colleagues, err := repo.GetLeaderboard(LeaderboardColleagues, from, to, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, LeaderboardEntry{UserId: alice.ID, Name: "alice", Value: 2}, colleagues[0])
+	assert.Len(t, colleagues, 3)
+}
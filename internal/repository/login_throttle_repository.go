@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"merch_shop/internal/entity"
+	"time"
+)
+
+type GormLoginThrottleRepository struct {
+	db *gorm.DB
+}
+
+func NewGormLoginThrottleRepository(db *gorm.DB) *GormLoginThrottleRepository {
+	return &GormLoginThrottleRepository{
+		db: db,
+	}
+}
+
+func (repo *GormLoginThrottleRepository) FindThrottle(kind string, value string) (*entity.LoginThrottle, error) {
+	throttle := new(entity.LoginThrottle)
+	err := repo.db.Where("kind = ? AND value = ?", kind, value).First(throttle).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return throttle, nil
+}
+
+// RecordFailure atomically counts a failed sign-in at now. Failures older than staleBefore are
+// forgotten, so the count starts again from one.
+func (repo *GormLoginThrottleRepository) RecordFailure(kind string, value string, now time.Time, staleBefore time.Time) (*entity.LoginThrottle, error) {
+	err := repo.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "kind"}, {Name: "value"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"failures":        gorm.Expr("CASE WHEN login_throttles.last_failure_at < ? THEN 1 ELSE login_throttles.failures + 1 END", staleBefore),
+			"last_failure_at": now,
+		}),
+	}).Create(&entity.LoginThrottle{Kind: kind, Value: value, Failures: 1, LastFailureAt: now}).Error
+	if err != nil {
+		return nil, err
+	}
+	return repo.FindThrottle(kind, value)
+}
+
+// ReleaseFailure takes back one failure counted by RecordFailure.
+func (repo *GormLoginThrottleRepository) ReleaseFailure(kind string, value string) error {
+	return repo.db.Model(&entity.LoginThrottle{}).
+		Where("kind = ? AND value = ? AND failures > 0", kind, value).
+		Update("failures", gorm.Expr("failures - 1")).Error
+}
+
+func (repo *GormLoginThrottleRepository) DeleteThrottle(kind string, value string) (bool, error) {
+	result := repo.db.Where("kind = ? AND value = ?", kind, value).Delete(&entity.LoginThrottle{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetThrottles lists throttles with at least minFailures failures, the latest after since.
+func (repo *GormLoginThrottleRepository) GetThrottles(minFailures uint, since time.Time) ([]entity.LoginThrottle, error) {
+	var throttles []entity.LoginThrottle
+	err := repo.db.
+		Where("failures >= ? AND last_failure_at >= ?", minFailures, since).
+		Order("last_failure_at DESC").
+		Find(&throttles).Error
+	if err != nil {
+		return nil, err
+	}
+	return throttles, nil
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"testing"
+	"time"
+)
+
+func setupLoginThrottleDB() *gorm.DB {
+	db, _ := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	db.AutoMigrate(&entity.LoginThrottle{})
+	return db
+}
+
+func TestGormLoginThrottleRepository_RecordFailure(t *testing.T) {
+	db := setupLoginThrottleDB()
+	repo := NewGormLoginThrottleRepository(db)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		throttle, err := repo.RecordFailure(entity.ThrottleUser, "alice", now.Add(time.Duration(i)*time.Second), now.Add(-time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, uint(i+1), throttle.Failures)
+	}
+	_, err := repo.RecordFailure(entity.ThrottleIP, "10.0.0.1", now, now.Add(-time.Hour))
+	assert.NoError(t, err)
+
+	later := now.Add(2 * time.Hour)
+	throttle, err := repo.RecordFailure(entity.ThrottleUser, "alice", later, later.Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), throttle.Failures)
+	assert.True(t, later.Equal(throttle.LastFailureAt))
+
+	throttles, err := repo.GetThrottles(1, now)
+	assert.NoError(t, err)
+	assert.Len(t, throttles, 2)
+	assert.Equal(t, "alice", throttles[0].Value)
+
+	deleted, err := repo.DeleteThrottle(entity.ThrottleUser, "alice")
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+	found, err := repo.FindThrottle(entity.ThrottleUser, "alice")
+	assert.NoError(t, err)
+	assert.Nil(t, found)
+}
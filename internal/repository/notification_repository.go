@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"time"
+)
+
+type GormNotificationRepository struct {
+	db *gorm.DB
+}
+
+func NewGormNotificationRepository(db *gorm.DB) *GormNotificationRepository {
+	return &GormNotificationRepository{
+		db: db,
+	}
+}
+
+func (repo *GormNotificationRepository) CreateNotification(notification *entity.Notification) error {
+	return repo.db.Omit("User").Create(notification).Error
+}
+
+func (repo *GormNotificationRepository) GetNotifications(userId uint) ([]entity.Notification, error) {
+	var notifications []entity.Notification
+	err := repo.db.Where("user_id = ?", userId).Order("created_at DESC").Find(&notifications).Error
+	if err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// MarkNotificationRead reports false when the user has no such unread notification.
+func (repo *GormNotificationRepository) MarkNotificationRead(userId uint, notificationId uint, at time.Time) (bool, error) {
+	result := repo.db.Model(&entity.Notification{}).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", notificationId, userId).
+		Update("read_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"testing"
+	"time"
+)
+
+func setupNotificationDB() *gorm.DB {
+	db, _ := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	db.AutoMigrate(&entity.User{}, &entity.Notification{})
+	return db
+}
+
+func TestGormNotificationRepository(t *testing.T) {
+	db := setupNotificationDB()
+	repo := NewGormNotificationRepository(db)
+	now := time.Now()
+
+	alice := &entity.User{Name: "alice"}
+	bob := &entity.User{Name: "bob"}
+	db.Create(alice)
+	db.Create(bob)
+
+	older := &entity.Notification{UserID: alice.ID, Message: "first"}
+	older.CreatedAt = now.Add(-time.Hour)
+	newer := &entity.Notification{UserID: alice.ID, Message: "second"}
+	others := &entity.Notification{UserID: bob.ID, Message: "for bob"}
+	assert.NoError(t, repo.CreateNotification(older))
+	assert.NoError(t, repo.CreateNotification(newer))
+	assert.NoError(t, repo.CreateNotification(others))
+
+	notifications, err := repo.GetNotifications(alice.ID)
+	assert.NoError(t, err)
+	assert.Len(t, notifications, 2)
+	assert.Equal(t, "second", notifications[0].Message)
+	assert.Equal(t, "first", notifications[1].Message)
+
+	t.Run("OtherUsersNotification", func(t *testing.T) {
+		marked, err := repo.MarkNotificationRead(alice.ID, others.ID, now)
+		assert.NoError(t, err)
+		assert.False(t, marked)
+
+		notifications, err := repo.GetNotifications(bob.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, notifications[0].ReadAt)
+	})
+
+	t.Run("MarkRead", func(t *testing.T) {
+		marked, err := repo.MarkNotificationRead(alice.ID, newer.ID, now)
+		assert.NoError(t, err)
+		assert.True(t, marked)
+
+		marked, err = repo.MarkNotificationRead(alice.ID, newer.ID, now)
+		assert.NoError(t, err)
+		assert.False(t, marked)
+
+		notifications, err := repo.GetNotifications(alice.ID)
+		assert.NoError(t, err)
+		assert.NotNil(t, notifications[0].ReadAt)
+		assert.Nil(t, notifications[1].ReadAt)
+	})
+}
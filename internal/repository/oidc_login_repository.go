@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"merch_shop/internal/entity"
+	"time"
+)
+
+type GormOIDCLoginRepository struct {
+	db *gorm.DB
+}
+
+func NewGormOIDCLoginRepository(db *gorm.DB) *GormOIDCLoginRepository {
+	return &GormOIDCLoginRepository{
+		db: db,
+	}
+}
+
+func (repo *GormOIDCLoginRepository) CreateOIDCLogin(login *entity.OIDCLogin) error {
+	return repo.db.Create(login).Error
+}
+
+// TakeOIDCLogin deletes and returns the sign-in with the given state hash, so that each state can
+// only be used once.
+func (repo *GormOIDCLoginRepository) TakeOIDCLogin(stateHash string) (*entity.OIDCLogin, error) {
+	var logins []entity.OIDCLogin
+	err := repo.db.Clauses(clause.Returning{}).Where("state_hash = ?", stateHash).Delete(&logins).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(logins) == 0 {
+		return nil, nil
+	}
+	return &logins[0], nil
+}
+
+func (repo *GormOIDCLoginRepository) DeleteExpiredOIDCLogins(before time.Time) error {
+	return repo.db.Where("expires_at < ?", before).Delete(&entity.OIDCLogin{}).Error
+}
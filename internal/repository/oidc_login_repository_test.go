@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"testing"
+	"time"
+)
+
+func TestGormOIDCLoginRepository_TakeOIDCLogin(t *testing.T) {
+	db, _ := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	db.AutoMigrate(&entity.OIDCLogin{})
+	repo := NewGormOIDCLoginRepository(db)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, repo.CreateOIDCLogin(&entity.OIDCLogin{StateHash: "a", Nonce: "n", CodeVerifier: "v", ExpiresAt: now}))
+	assert.NoError(t, repo.CreateOIDCLogin(&entity.OIDCLogin{StateHash: "b", ExpiresAt: now.Add(-time.Minute)}))
+
+	login, err := repo.TakeOIDCLogin("a")
+	assert.NoError(t, err)
+	assert.Equal(t, "n", login.Nonce)
+	assert.Equal(t, "v", login.CodeVerifier)
+
+	login, err = repo.TakeOIDCLogin("a")
+	assert.NoError(t, err)
+	assert.Nil(t, login)
+
+	assert.NoError(t, repo.DeleteExpiredOIDCLogins(now))
+	login, _ = repo.TakeOIDCLogin("b")
+	assert.Nil(t, login)
+}
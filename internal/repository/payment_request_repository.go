@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"time"
+)
+
+type GormPaymentRequestRepository struct {
+	db *gorm.DB
+}
+
+func NewGormPaymentRequestRepository(db *gorm.DB) *GormPaymentRequestRepository {
+	return &GormPaymentRequestRepository{
+		db: db,
+	}
+}
+
+func (repo *GormPaymentRequestRepository) CreatePaymentRequest(request *entity.PaymentRequest) error {
+	return repo.db.Create(request).Error
+}
+
+func (repo *GormPaymentRequestRepository) UpdatePaymentRequest(request *entity.PaymentRequest) error {
+	return repo.db.Omit("Requester", "Payer").Save(request).Error
+}
+
+func (repo *GormPaymentRequestRepository) FindPaymentRequestById(requestId uint) (*entity.PaymentRequest, error) {
+	request := new(entity.PaymentRequest)
+	err := repo.db.First(request, requestId).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return request, nil
+}
+
+func (repo *GormPaymentRequestRepository) GetIncomingPaymentRequests(userId uint, now time.Time) ([]entity.PaymentRequest, error) {
+	var requests []entity.PaymentRequest
+	err := repo.db.Joins("Requester").
+		Where("payer_id = ? AND status = ? AND expires_at > ?", userId, entity.PaymentRequestPending, now).
+		Order("payment_requests.created_at").
+		Find(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+func (repo *GormPaymentRequestRepository) GetOutgoingPaymentRequests(userId uint, now time.Time) ([]entity.PaymentRequest, error) {
+	var requests []entity.PaymentRequest
+	err := repo.db.Joins("Payer").
+		Where("requester_id = ? AND status = ? AND expires_at > ?", userId, entity.PaymentRequestPending, now).
+		Order("payment_requests.created_at").
+		Find(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"testing"
+	"time"
+)
+
+func setupPaymentRequestDB() *gorm.DB {
+	db, _ := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	db.AutoMigrate(&entity.User{}, &entity.Transaction{}, &entity.PaymentRequest{}, &entity.DailyTransfer{})
+	return db
+}
+
+func TestGormPaymentRequestRepository_GetPaymentRequests(t *testing.T) {
+	db := setupPaymentRequestDB()
+	repo := NewGormPaymentRequestRepository(db)
+	now := time.Now()
+
+	requester := &entity.User{Name: "alice"}
+	payer := &entity.User{Name: "bob"}
+	db.Create(requester)
+	db.Create(payer)
+
+	open := &entity.PaymentRequest{RequesterId: requester.ID, PayerId: payer.ID, Amount: 50,
+		Status: entity.PaymentRequestPending, ExpiresAt: now.Add(time.Hour)}
+	expired := &entity.PaymentRequest{RequesterId: requester.ID, PayerId: payer.ID, Amount: 60,
+		Status: entity.PaymentRequestPending, ExpiresAt: now.Add(-time.Hour)}
+	declined := &entity.PaymentRequest{RequesterId: requester.ID, PayerId: payer.ID, Amount: 70,
+		Status: entity.PaymentRequestDeclined, ExpiresAt: now.Add(time.Hour)}
+	assert.NoError(t, repo.CreatePaymentRequest(open))
+	assert.NoError(t, repo.CreatePaymentRequest(expired))
+	assert.NoError(t, repo.CreatePaymentRequest(declined))
+
+	t.Run("Incoming", func(t *testing.T) {
+		requests, err := repo.GetIncomingPaymentRequests(payer.ID, now)
+		assert.NoError(t, err)
+		assert.Len(t, requests, 1)
+		assert.Equal(t, open.ID, requests[0].ID)
+		assert.Equal(t, "alice", requests[0].Requester.Name)
+	})
+
+	t.Run("Outgoing", func(t *testing.T) {
+		requests, err := repo.GetOutgoingPaymentRequests(requester.ID, now)
+		assert.NoError(t, err)
+		assert.Len(t, requests, 1)
+		assert.Equal(t, "bob", requests[0].Payer.Name)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		open.Status = entity.PaymentRequestAccepted
+		assert.NoError(t, repo.UpdatePaymentRequest(open))
+
+		found, err := repo.FindPaymentRequestById(open.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.PaymentRequestAccepted, found.Status)
+	})
+}
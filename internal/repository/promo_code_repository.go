@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+)
+
+type GormPromoCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewGormPromoCodeRepository(db *gorm.DB) *GormPromoCodeRepository {
+	return &GormPromoCodeRepository{
+		db: db,
+	}
+}
+
+func (repo *GormPromoCodeRepository) CreatePromoCode(promoCode *entity.PromoCode) error {
+	return repo.db.Omit("Item").Create(promoCode).Error
+}
+
+func (repo *GormPromoCodeRepository) FindPromoCodeByCode(code string) (*entity.PromoCode, error) {
+	promoCode := new(entity.PromoCode)
+	err := repo.db.Where("code = ?", code).First(promoCode).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return promoCode, nil
+}
+
+func (repo *GormPromoCodeRepository) GetPromoCodes() ([]entity.PromoCode, error) {
+	var promoCodes []entity.PromoCode
+	err := repo.db.Preload("Item").Order("created_at DESC").Find(&promoCodes).Error
+	if err != nil {
+		return nil, err
+	}
+	return promoCodes, nil
+}
+
+// RedeemPromoCode counts one redemption and reports false when the code has run out.
+func (repo *GormPromoCodeRepository) RedeemPromoCode(promoCodeId uint) (bool, error) {
+	result := repo.db.Model(&entity.PromoCode{}).
+		Where("id = ? AND (max_redemptions = 0 OR redemptions < max_redemptions)", promoCodeId).
+		Update("redemptions", gorm.Expr("redemptions + 1"))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ReleasePromoCode gives back one redemption of a refunded purchase.
+func (repo *GormPromoCodeRepository) ReleasePromoCode(promoCodeId uint) error {
+	return repo.db.Model(&entity.PromoCode{}).
+		Where("id = ? AND redemptions > 0", promoCodeId).
+		Update("redemptions", gorm.Expr("redemptions - 1")).Error
+}
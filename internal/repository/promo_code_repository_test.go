@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"testing"
+)
+
+func setupPromoCodeDB() *gorm.DB {
+	db, _ := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	db.AutoMigrate(&entity.Item{}, &entity.PromoCode{})
+	return db
+}
+
+func TestGormPromoCodeRepository_RedeemPromoCode(t *testing.T) {
+	db := setupPromoCodeDB()
+	repo := NewGormPromoCodeRepository(db)
+
+	limited := &entity.PromoCode{Code: "ONCE", DiscountType: entity.DiscountFixed, Discount: 10, MaxRedemptions: 1}
+	unlimited := &entity.PromoCode{Code: "ALWAYS", DiscountType: entity.DiscountFixed, Discount: 10}
+	assert.NoError(t, repo.CreatePromoCode(limited))
+	assert.NoError(t, repo.CreatePromoCode(unlimited))
+
+	redeemed, err := repo.RedeemPromoCode(limited.ID)
+	assert.NoError(t, err)
+	assert.True(t, redeemed)
+
+	redeemed, err = repo.RedeemPromoCode(limited.ID)
+	assert.NoError(t, err)
+	assert.False(t, redeemed)
+
+	assert.NoError(t, repo.ReleasePromoCode(limited.ID))
+	redeemed, err = repo.RedeemPromoCode(limited.ID)
+	assert.NoError(t, err)
+	assert.True(t, redeemed)
+
+	for i := 0; i < 3; i++ {
+		redeemed, err = repo.RedeemPromoCode(unlimited.ID)
+		assert.NoError(t, err)
+		assert.True(t, redeemed)
+	}
+
+	found, err := repo.FindPromoCodeByCode("ALWAYS")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), found.Redemptions)
+
+	found, err = repo.FindPromoCodeByCode("NONE")
+	assert.NoError(t, err)
+	assert.Nil(t, found)
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"time"
+)
+
+type GormPurchaseRepository struct {
+	db *gorm.DB
+}
+
+func NewGormPurchaseRepository(db *gorm.DB) *GormPurchaseRepository {
+	return &GormPurchaseRepository{
+		db: db,
+	}
+}
+
+func (repo *GormPurchaseRepository) CreatePurchase(purchase *entity.Purchase) error {
+	return repo.db.Create(purchase).Error
+}
+
+func (repo *GormPurchaseRepository) UpdatePurchase(purchase *entity.Purchase) error {
+	return repo.db.Omit("Buyer", "Owner", "Item", "Variant", "PromoCode").Save(purchase).Error
+}
+
+func (repo *GormPurchaseRepository) FindPurchaseById(purchaseId uint) (*entity.Purchase, error) {
+	purchase := new(entity.Purchase)
+	err := repo.db.Joins("Item").Joins("Variant").First(purchase, purchaseId).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return purchase, nil
+}
+
+// FindReturnablePurchase returns the latest unrefunded purchase of the item owned by the user made after since.
+func (repo *GormPurchaseRepository) FindReturnablePurchase(ownerId uint, itemId uint, variantId uint, since time.Time) (*entity.Purchase, error) {
+	purchase := new(entity.Purchase)
+	err := repo.db.Joins("Item").Joins("Variant").
+		Where("owner_id = ? AND purchases.item_id = ? AND variant_id = ? AND refunded_at IS NULL AND purchases.created_at >= ?",
+			ownerId, itemId, variantId, since).
+		Order("purchases.created_at DESC").
+		First(purchase).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return purchase, nil
+}
+
+func (repo *GormPurchaseRepository) GetUserPurchases(ownerId uint) ([]entity.Purchase, error) {
+	var purchases []entity.Purchase
+	err := repo.db.Joins("Item").Joins("Variant").Where("owner_id = ?", ownerId).Order("purchases.created_at DESC").Find(&purchases).Error
+	if err != nil {
+		return nil, err
+	}
+	return purchases, nil
+}
+
+// GetRefundedPurchases returns the refunded purchases the user paid for personally. Refunds of items
+// paid from a team wallet went back to the wallet and are logged there.
+func (repo *GormPurchaseRepository) GetRefundedPurchases(buyerId uint) ([]entity.Purchase, error) {
+	var purchases []entity.Purchase
+	err := repo.db.Joins("Item").Joins("Variant").
+		Where("buyer_id = ? AND purchases.wallet_id IS NULL AND refunded_at IS NOT NULL", buyerId).Find(&purchases).Error
+	if err != nil {
+		return nil, err
+	}
+	return purchases, nil
+}
+
+// CountActivePurchases counts the units of an item bought by the user that were not refunded.
+// Units bought by a group purchase count for the recipient rather than the organizer.
+func (repo *GormPurchaseRepository) CountActivePurchases(buyerId uint, itemId uint) (int64, error) {
+	var count int64
+	err := repo.db.Model(&entity.Purchase{}).
+		Where("(buyer_id = ? AND group_purchase_id IS NULL OR owner_id = ? AND group_purchase_id IS NOT NULL) AND item_id = ? AND refunded_at IS NULL", buyerId, buyerId, itemId).
+		Count(&count).Error
+	return count, err
+}
+
+// GetOpenOrders returns purchases that still have to be handed over, oldest first.
+func (repo *GormPurchaseRepository) GetOpenOrders() ([]entity.Purchase, error) {
+	var purchases []entity.Purchase
+	err := repo.db.Joins("Item").Joins("Variant").Joins("Owner").
+		Where("fulfillment_status IN ? AND refunded_at IS NULL",
+			[]string{entity.FulfillmentPlaced, entity.FulfillmentPacked, entity.FulfillmentReady}).
+		Order("purchases.created_at").
+		Find(&purchases).Error
+	if err != nil {
+		return nil, err
+	}
+	return purchases, nil
+}
+
+// CountPromoRedemptions counts the buyer's purchases with the promo code that were not refunded.
+func (repo *GormPurchaseRepository) CountPromoRedemptions(buyerId uint, promoCodeId uint) (int64, error) {
+	var count int64
+	err := repo.db.Model(&entity.Purchase{}).
+		Where("buyer_id = ? AND promo_code_id = ? AND refunded_at IS NULL", buyerId, promoCodeId).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"testing"
+	"time"
+)
+
+func setupPurchaseDB() *gorm.DB {
+	db, _ := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	db.AutoMigrate(&entity.User{}, &entity.Item{}, &entity.ItemVariant{}, &entity.Purchase{})
+	return db
+}
+
+func TestGormPurchaseRepository_FindReturnablePurchase(t *testing.T) {
+	db := setupPurchaseDB()
+	repo := NewGormPurchaseRepository(db)
+	now := time.Now()
+
+	user := &entity.User{Name: "alice"}
+	item := &entity.Item{Name: "cup", Price: 20}
+	db.Create(user)
+	db.Create(item)
+
+	old := &entity.Purchase{BuyerId: user.ID, OwnerId: user.ID, ItemID: item.ID, Price: 10}
+	old.CreatedAt = now.Add(-time.Hour * 48)
+	assert.NoError(t, repo.CreatePurchase(old))
+
+	t.Run("OutsideWindow", func(t *testing.T) {
+		found, err := repo.FindReturnablePurchase(user.ID, item.ID, 0, now.Add(-time.Hour*24))
+		assert.NoError(t, err)
+		assert.Nil(t, found)
+	})
+
+	t.Run("WithinWindow", func(t *testing.T) {
+		recent := &entity.Purchase{BuyerId: user.ID, OwnerId: user.ID, ItemID: item.ID, Price: 20}
+		assert.NoError(t, repo.CreatePurchase(recent))
+
+		found, err := repo.FindReturnablePurchase(user.ID, item.ID, 0, now.Add(-time.Hour*24))
+		assert.NoError(t, err)
+		assert.Equal(t, recent.ID, found.ID)
+		assert.Equal(t, "cup", found.Item.Name)
+
+		found.RefundedAt = &now
+		assert.NoError(t, repo.UpdatePurchase(found))
+
+		found, err = repo.FindReturnablePurchase(user.ID, item.ID, 0, now.Add(-time.Hour*24))
+		assert.NoError(t, err)
+		assert.Nil(t, found)
+
+		walletId := uint(1)
+		fromWallet := &entity.Purchase{BuyerId: user.ID, OwnerId: user.ID, ItemID: item.ID, Price: 20, WalletID: &walletId, RefundedAt: &now}
+		assert.NoError(t, repo.CreatePurchase(fromWallet))
+
+		refunded, err := repo.GetRefundedPurchases(user.ID)
+		assert.NoError(t, err)
+		assert.Len(t, refunded, 1)
+		assert.Equal(t, recent.ID, refunded[0].ID)
+	})
+}
+
+func TestGormPurchaseRepository_GetOpenOrders(t *testing.T) {
+	db := setupPurchaseDB()
+	repo := NewGormPurchaseRepository(db)
+	now := time.Now()
+
+	user := &entity.User{Name: "alice"}
+	item := &entity.Item{Name: "cup", Price: 20}
+	db.Create(user)
+	db.Create(item)
+
+	placed := &entity.Purchase{BuyerId: user.ID, OwnerId: user.ID, ItemID: item.ID, FulfillmentStatus: entity.FulfillmentPlaced}
+	delivered := &entity.Purchase{BuyerId: user.ID, OwnerId: user.ID, ItemID: item.ID, FulfillmentStatus: entity.FulfillmentDelivered}
+	refunded := &entity.Purchase{BuyerId: user.ID, OwnerId: user.ID, ItemID: item.ID, FulfillmentStatus: entity.FulfillmentPacked, RefundedAt: &now}
+	assert.NoError(t, repo.CreatePurchase(placed))
+	assert.NoError(t, repo.CreatePurchase(delivered))
+	assert.NoError(t, repo.CreatePurchase(refunded))
+
+	orders, err := repo.GetOpenOrders()
+	assert.NoError(t, err)
+	assert.Len(t, orders, 1)
+	assert.Equal(t, placed.ID, orders[0].ID)
+	assert.Equal(t, "alice", orders[0].Owner.Name)
+	assert.Equal(t, "cup", orders[0].Item.Name)
+}
+
+func TestGormPurchaseRepository_CountPromoRedemptions(t *testing.T) {
+	db := setupPurchaseDB()
+	repo := NewGormPurchaseRepository(db)
+	now := time.Now()
+	promoCodeId := uint(4)
+
+	user := &entity.User{Name: "alice"}
+	item := &entity.Item{Name: "cup", Price: 20}
+	db.Create(user)
+	db.Create(item)
+
+	redeemed := &entity.Purchase{BuyerId: user.ID, OwnerId: user.ID, ItemID: item.ID, Price: 10, PromoCodeID: &promoCodeId}
+	refunded := &entity.Purchase{BuyerId: user.ID, OwnerId: user.ID, ItemID: item.ID, Price: 10, PromoCodeID: &promoCodeId, RefundedAt: &now}
+	assert.NoError(t, repo.CreatePurchase(redeemed))
+	assert.NoError(t, repo.CreatePurchase(refunded))
+	assert.NoError(t, repo.CreatePurchase(&entity.Purchase{BuyerId: user.ID, OwnerId: user.ID, ItemID: item.ID, Price: 20}))
+
+	count, err := repo.CountPromoRedemptions(user.ID, promoCodeId)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestGormPurchaseRepository_CountActivePurchases(t *testing.T) {
+	db := setupPurchaseDB()
+	repo := NewGormPurchaseRepository(db)
+	now := time.Now()
+	groupPurchaseId := uint(5)
+
+	alice := &entity.User{Name: "alice"}
+	bob := &entity.User{Name: "bob"}
+	item := &entity.Item{Name: "cup", Price: 20}
+	db.Create(alice)
+	db.Create(bob)
+	db.Create(item)
+
+	assert.NoError(t, repo.CreatePurchase(&entity.Purchase{BuyerId: alice.ID, OwnerId: alice.ID, ItemID: item.ID}))
+	assert.NoError(t, repo.CreatePurchase(&entity.Purchase{BuyerId: alice.ID, OwnerId: alice.ID, ItemID: item.ID, RefundedAt: &now}))
+	// A gift counts for the buyer, a group purchase for the recipient.
+	assert.NoError(t, repo.CreatePurchase(&entity.Purchase{BuyerId: bob.ID, OwnerId: alice.ID, ItemID: item.ID}))
+	assert.NoError(t, repo.CreatePurchase(&entity.Purchase{BuyerId: bob.ID, OwnerId: alice.ID, ItemID: item.ID, GroupPurchaseID: &groupPurchaseId}))
+
+	count, err := repo.CountActivePurchases(alice.ID, item.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	count, err = repo.CountActivePurchases(bob.ID, item.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"time"
+)
+
+type GormRecoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRecoveryCodeRepository(db *gorm.DB) *GormRecoveryCodeRepository {
+	return &GormRecoveryCodeRepository{
+		db: db,
+	}
+}
+
+func (repo *GormRecoveryCodeRepository) CreateRecoveryCodes(codes []entity.RecoveryCode) error {
+	return repo.db.Create(&codes).Error
+}
+
+func (repo *GormRecoveryCodeRepository) DeleteRecoveryCodes(userId uint) error {
+	return repo.db.Where("user_id = ?", userId).Delete(&entity.RecoveryCode{}).Error
+}
+
+// UseRecoveryCode marks an unused recovery code of the user as used at now. It reports false when
+// the user has no such unused code.
+func (repo *GormRecoveryCodeRepository) UseRecoveryCode(userId uint, codeHash string, now time.Time) (bool, error) {
+	result := repo.db.Model(&entity.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userId, codeHash).
+		Update("used_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (repo *GormRecoveryCodeRepository) CountUnusedRecoveryCodes(userId uint) (int64, error) {
+	var count int64
+	err := repo.db.Model(&entity.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userId).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"testing"
+	"time"
+)
+
+func TestGormRecoveryCodeRepository(t *testing.T) {
+	db, _ := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	db.AutoMigrate(&entity.RecoveryCode{})
+	repo := NewGormRecoveryCodeRepository(db)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, repo.CreateRecoveryCodes([]entity.RecoveryCode{
+		{UserID: 1, CodeHash: "a"}, {UserID: 1, CodeHash: "b"}, {UserID: 2, CodeHash: "a"},
+	}))
+
+	used, err := repo.UseRecoveryCode(1, "a", now)
+	assert.NoError(t, err)
+	assert.True(t, used)
+	used, err = repo.UseRecoveryCode(1, "a", now)
+	assert.NoError(t, err)
+	assert.False(t, used)
+
+	count, err := repo.CountUnusedRecoveryCodes(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	count, _ = repo.CountUnusedRecoveryCodes(2)
+	assert.Equal(t, int64(1), count)
+
+	assert.NoError(t, repo.DeleteRecoveryCodes(1))
+	count, _ = repo.CountUnusedRecoveryCodes(1)
+	assert.Equal(t, int64(0), count)
+}
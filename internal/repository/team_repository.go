@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"merch_shop/internal/entity"
+	"time"
+)
+
+// TeamStats sums up the activity of a team's members over a period.
+type TeamStats struct {
+	CoinsReceived uint
+	CoinsGiven    uint
+	ItemsBought   int64
+}
+
+type GormTeamRepository struct {
+	db *gorm.DB
+}
+
+func NewGormTeamRepository(db *gorm.DB) *GormTeamRepository {
+	return &GormTeamRepository{
+		db: db,
+	}
+}
+
+func (repo *GormTeamRepository) CreateTeam(team *entity.Team) error {
+	return repo.db.Omit("Members").Create(team).Error
+}
+
+func (repo *GormTeamRepository) UpdateTeam(team *entity.Team) error {
+	return repo.db.Omit("Members").Save(team).Error
+}
+
+func (repo *GormTeamRepository) FindTeamByName(name string) (*entity.Team, error) {
+	team := new(entity.Team)
+	err := repo.db.Preload("Members.User").Where("name = ?", name).First(team).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return team, nil
+}
+
+func (repo *GormTeamRepository) GetTeams() ([]entity.Team, error) {
+	var teams []entity.Team
+	err := repo.db.Preload("Members.User").Order("department").Order("name").Find(&teams).Error
+	if err != nil {
+		return nil, err
+	}
+	return teams, nil
+}
+
+// AddTeamMember adds the user to the team; adding an existing member does nothing.
+func (repo *GormTeamRepository) AddTeamMember(member *entity.TeamMember) error {
+	return repo.db.Omit("User").Clauses(clause.OnConflict{DoNothing: true}).Create(member).Error
+}
+
+// SetTeamManager makes the member a manager of the team or takes the role away.
+func (repo *GormTeamRepository) SetTeamManager(teamId uint, userId uint, manager bool) error {
+	return repo.db.Model(&entity.TeamMember{}).
+		Where("team_id = ? AND user_id = ?", teamId, userId).
+		Update("manager", manager).Error
+}
+
+func (repo *GormTeamRepository) RemoveTeamMember(teamId uint, userId uint) (bool, error) {
+	result := repo.db.Unscoped().
+		Where("team_id = ? AND user_id = ?", teamId, userId).
+		Delete(&entity.TeamMember{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetTeamStats sums coins received and given and counts items bought and not refunded
+// by the team's current members in [from, to). Payouts from team wallets are not counted.
+func (repo *GormTeamRepository) GetTeamStats(teamId uint, from time.Time, to time.Time) (TeamStats, error) {
+	var stats TeamStats
+	members := repo.db.Model(&entity.TeamMember{}).Select("user_id").Where("team_id = ?", teamId)
+
+	err := repo.db.Model(&entity.Transaction{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("to_id IN (?) AND wallet_id IS NULL AND created_at >= ? AND created_at < ?", members, from, to).
+		Scan(&stats.CoinsReceived).Error
+	if err != nil {
+		return TeamStats{}, err
+	}
+	err = repo.db.Model(&entity.Transaction{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("from_id IN (?) AND wallet_id IS NULL AND created_at >= ? AND created_at < ?", members, from, to).
+		Scan(&stats.CoinsGiven).Error
+	if err != nil {
+		return TeamStats{}, err
+	}
+	err = repo.db.Model(&entity.Purchase{}).
+		Where("buyer_id IN (?) AND refunded_at IS NULL AND created_at >= ? AND created_at < ?", members, from, to).
+		Count(&stats.ItemsBought).Error
+	if err != nil {
+		return TeamStats{}, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"testing"
+	"time"
+)
+
+func setupTeamDB() *gorm.DB {
+	db, _ := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	db.AutoMigrate(&entity.User{}, &entity.Item{}, &entity.ItemVariant{}, &entity.Transaction{}, &entity.Purchase{},
+		&entity.Team{}, &entity.TeamMember{})
+	return db
+}
+
+func TestGormTeamRepository_GetTeamStats(t *testing.T) {
+	db := setupTeamDB()
+	repo := NewGormTeamRepository(db)
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	alice := &entity.User{Name: "alice"}
+	bob := &entity.User{Name: "bob"}
+	carol := &entity.User{Name: "carol"}
+	item := &entity.Item{Name: "cup", Price: 20}
+	db.Create(alice)
+	db.Create(bob)
+	db.Create(carol)
+	db.Create(item)
+
+	team := &entity.Team{Name: "platform"}
+	assert.NoError(t, repo.CreateTeam(team))
+	assert.NoError(t, repo.AddTeamMember(&entity.TeamMember{TeamID: team.ID, UserID: alice.ID}))
+	assert.NoError(t, repo.AddTeamMember(&entity.TeamMember{TeamID: team.ID, UserID: bob.ID}))
+	assert.NoError(t, repo.AddTeamMember(&entity.TeamMember{TeamID: team.ID, UserID: bob.ID}))
+
+	inRange := from.Add(time.Hour * 24 * 10)
+	db.Create(&entity.Transaction{Model: gorm.Model{CreatedAt: inRange}, FromId: carol.ID, ToId: alice.ID, Amount: 100})
+	db.Create(&entity.Transaction{Model: gorm.Model{CreatedAt: inRange}, FromId: alice.ID, ToId: bob.ID, Amount: 30})
+	db.Create(&entity.Transaction{Model: gorm.Model{CreatedAt: to}, FromId: carol.ID, ToId: bob.ID, Amount: 500})
+	walletId := uint(1)
+	db.Create(&entity.Transaction{Model: gorm.Model{CreatedAt: inRange}, FromId: alice.ID, ToId: bob.ID, Amount: 70, WalletID: &walletId})
+	refundedAt := inRange
+	db.Create(&entity.Purchase{Model: gorm.Model{CreatedAt: inRange}, BuyerId: bob.ID, OwnerId: bob.ID, ItemID: item.ID})
+	db.Create(&entity.Purchase{Model: gorm.Model{CreatedAt: inRange}, BuyerId: bob.ID, OwnerId: bob.ID, ItemID: item.ID, RefundedAt: &refundedAt})
+	db.Create(&entity.Purchase{Model: gorm.Model{CreatedAt: inRange}, BuyerId: carol.ID, OwnerId: carol.ID, ItemID: item.ID})
+
+	assert.NoError(t, repo.SetTeamManager(team.ID, alice.ID, true))
+
+	found, err := repo.FindTeamByName("platform")
+	assert.NoError(t, err)
+	assert.Len(t, found.Members, 2)
+	assert.True(t, found.Members[0].Manager)
+	assert.False(t, found.Members[1].Manager)
+
+	stats, err := repo.GetTeamStats(team.ID, from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(130), stats.CoinsReceived)
+	assert.Equal(t, uint(30), stats.CoinsGiven)
+	assert.Equal(t, int64(1), stats.ItemsBought)
+}
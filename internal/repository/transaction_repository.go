@@ -5,6 +5,7 @@ import (
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 	"merch_shop/internal/entity"
+	"time"
 )
 
 type GormTransactionRepository struct {
@@ -19,7 +20,7 @@ func NewGormTransactionRepository(db *gorm.DB) *GormTransactionRepository {
 
 func (repo *GormTransactionRepository) GetUserInventory(userId uint) ([]entity.InventoryItem, error) {
 	var inventoryItems []entity.InventoryItem
-	err := repo.db.Joins("Item").Where("user_id = ?", userId).Find(&inventoryItems).Error
+	err := repo.db.Joins("Item").Joins("Variant").Where("user_id = ? AND quantity > 0", userId).Find(&inventoryItems).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return []entity.InventoryItem{}, nil
@@ -30,7 +31,7 @@ func (repo *GormTransactionRepository) GetUserInventory(userId uint) ([]entity.I
 }
 func (repo *GormTransactionRepository) GetIncomeTransactions(userId uint) ([]entity.Transaction, error) {
 	var transactions []entity.Transaction
-	err := repo.db.Joins("FromUser").Where("to_id = ?", userId).Find(&transactions).Error
+	err := repo.db.Joins("FromUser").Joins("Wallet").Where("to_id = ?", userId).Find(&transactions).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return []entity.Transaction{}, nil
@@ -43,7 +44,7 @@ func (repo *GormTransactionRepository) GetIncomeTransactions(userId uint) ([]ent
 
 func (repo *GormTransactionRepository) GetOutcomeTransactions(userId uint) ([]entity.Transaction, error) {
 	var transactions []entity.Transaction
-	err := repo.db.Joins("ToUser").Where("from_id = ?", userId).Find(&transactions).Error
+	err := repo.db.Joins("ToUser").Joins("Wallet").Where("from_id = ?", userId).Find(&transactions).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return []entity.Transaction{}, nil
@@ -53,8 +54,34 @@ func (repo *GormTransactionRepository) GetOutcomeTransactions(userId uint) ([]en
 	return transactions, nil
 }
 
+// CreateTransaction stores the transaction and adds it to the daily transfer totals. Payouts from
+// a team wallet are not the sender's own coins and are left out of the totals.
 func (repo *GormTransactionRepository) CreateTransaction(transaction *entity.Transaction) error {
-	return repo.db.Create(transaction).Error
+	if err := repo.db.Create(transaction).Error; err != nil {
+		return err
+	}
+	if transaction.WalletID != nil {
+		return nil
+	}
+	return repo.RecordDailyTransfer(transaction)
+}
+
+// RecordDailyTransfer adds the transaction to the totals of its sender, recipient and day.
+func (repo *GormTransactionRepository) RecordDailyTransfer(transaction *entity.Transaction) error {
+	createdAt := transaction.CreatedAt.UTC()
+	return repo.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "day"}, {Name: "from_id"}, {Name: "to_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"amount": gorm.Expr("daily_transfers.amount + ?", transaction.Amount),
+			"count":  gorm.Expr("daily_transfers.count + 1"),
+		}),
+	}).Create(&entity.DailyTransfer{
+		Day:    time.Date(createdAt.Year(), createdAt.Month(), createdAt.Day(), 0, 0, 0, 0, time.UTC),
+		FromId: transaction.FromId,
+		ToId:   transaction.ToId,
+		Amount: transaction.Amount,
+		Count:  1,
+	}).Error
 }
 
 func (repo *GormTransactionRepository) GetItemByName(name string) (*entity.Item, error) {
@@ -71,16 +98,54 @@ func (repo *GormTransactionRepository) GetItemByName(name string) (*entity.Item,
 }
 
 func (repo *GormTransactionRepository) AddItem(userId uint, itemId uint) error {
+	return repo.AddItems(userId, itemId, 0, 1)
+}
+
+func (repo *GormTransactionRepository) AddItems(userId uint, itemId uint, variantId uint, quantity uint) error {
 	return repo.db.Clauses(
 		clause.OnConflict{
-			Columns: []clause.Column{{Name: "user_id"}, {Name: "item_id"}}, // Составной ключ для поиска дубликатов
+			Columns: []clause.Column{{Name: "user_id"}, {Name: "item_id"}, {Name: "variant_id"}}, // Составной ключ для поиска дубликатов
 			DoUpdates: clause.Assignments(map[string]interface{}{
-				"quantity": gorm.Expr("inventory_items.quantity + 1"), // Увеличиваем quantity на 1
+				"quantity": gorm.Expr("inventory_items.quantity + ?", quantity), // Увеличиваем quantity
 			}),
 		},
 	).Create(&entity.InventoryItem{
-		UserID:   userId,
-		ItemID:   itemId,
-		Quantity: 1,
+		UserID:    userId,
+		ItemID:    itemId,
+		VariantID: variantId,
+		Quantity:  quantity,
 	}).Error
 }
+
+// RemoveItems decrements the owned quantity and reports false when the user owns fewer than quantity units.
+func (repo *GormTransactionRepository) RemoveItems(userId uint, itemId uint, variantId uint, quantity uint) (bool, error) {
+	result := repo.db.Model(&entity.InventoryItem{}).
+		Where("user_id = ? AND item_id = ? AND variant_id = ? AND quantity >= ?", userId, itemId, variantId, quantity).
+		Update("quantity", gorm.Expr("quantity - ?", quantity))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (repo *GormTransactionRepository) CreateItemTransfer(transfer *entity.ItemTransfer) error {
+	return repo.db.Create(transfer).Error
+}
+
+func (repo *GormTransactionRepository) GetIncomeItemTransfers(userId uint) ([]entity.ItemTransfer, error) {
+	var transfers []entity.ItemTransfer
+	err := repo.db.Joins("FromUser").Joins("Item").Joins("Variant").Where("to_id = ?", userId).Find(&transfers).Error
+	if err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
+
+func (repo *GormTransactionRepository) GetOutcomeItemTransfers(userId uint) ([]entity.ItemTransfer, error) {
+	var transfers []entity.ItemTransfer
+	err := repo.db.Joins("ToUser").Joins("Item").Joins("Variant").Where("from_id = ?", userId).Find(&transfers).Error
+	if err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
@@ -10,7 +10,8 @@ import (
 
 func setupTransactionDB() *gorm.DB {
 	db, _ := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
-	db.AutoMigrate(&entity.User{}, &entity.Item{}, &entity.Transaction{}, &entity.InventoryItem{})
+	db.AutoMigrate(&entity.User{}, &entity.Item{}, &entity.ItemVariant{}, &entity.Transaction{}, &entity.InventoryItem{}, &entity.ItemTransfer{},
+		&entity.DailyTransfer{})
 	return db
 }
 
@@ -27,6 +28,13 @@ func TestGormTransactionRepository_CreateTransaction(t *testing.T) {
 	err := repo.CreateTransaction(tx)
 	assert.NoError(t, err)
 	assert.NotZero(t, tx.ID)
+
+	assert.NoError(t, repo.CreateTransaction(&entity.Transaction{FromId: fromUser.ID, ToId: toUser.ID, Amount: 50}))
+	var daily []entity.DailyTransfer
+	db.Find(&daily)
+	assert.Len(t, daily, 1)
+	assert.Equal(t, uint(250), daily[0].Amount)
+	assert.Equal(t, uint(2), daily[0].Count)
 }
 
 func TestGormTransactionRepository_GetItemByName(t *testing.T) {
@@ -123,3 +131,78 @@ func TestGormTransactionRepository_GetOutcomeTransactions(t *testing.T) {
 	assert.Len(t, transactions, 1)
 	assert.Equal(t, "bob", transactions[0].ToUser.Name)
 }
+
+func TestGormTransactionRepository_RemoveItems(t *testing.T) {
+	db := setupTransactionDB()
+	repo := NewGormTransactionRepository(db)
+
+	user := &entity.User{Name: "alice"}
+	item := &entity.Item{Name: "cup", Price: 20}
+	db.Create(user)
+	db.Create(item)
+	assert.NoError(t, repo.AddItems(user.ID, item.ID, 0, 2))
+
+	removed, err := repo.RemoveItems(user.ID, item.ID, 0, 3)
+	assert.NoError(t, err)
+	assert.False(t, removed)
+
+	removed, err = repo.RemoveItems(user.ID, item.ID, 0, 2)
+	assert.NoError(t, err)
+	assert.True(t, removed)
+
+	inventory, err := repo.GetUserInventory(user.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, inventory)
+}
+
+func TestGormTransactionRepository_GetItemTransfers(t *testing.T) {
+	db := setupTransactionDB()
+	repo := NewGormTransactionRepository(db)
+
+	fromUser := &entity.User{Name: "alice"}
+	toUser := &entity.User{Name: "bob"}
+	item := &entity.Item{Name: "cup", Price: 20}
+	db.Create(fromUser)
+	db.Create(toUser)
+	db.Create(item)
+	err := repo.CreateItemTransfer(&entity.ItemTransfer{FromId: fromUser.ID, ToId: toUser.ID, ItemID: item.ID, Quantity: 1})
+	assert.NoError(t, err)
+
+	income, err := repo.GetIncomeItemTransfers(toUser.ID)
+	assert.NoError(t, err)
+	assert.Len(t, income, 1)
+	assert.Equal(t, "alice", income[0].FromUser.Name)
+	assert.Equal(t, "cup", income[0].Item.Name)
+
+	outcome, err := repo.GetOutcomeItemTransfers(fromUser.ID)
+	assert.NoError(t, err)
+	assert.Len(t, outcome, 1)
+	assert.Equal(t, "bob", outcome[0].ToUser.Name)
+}
+
+func TestGormTransactionRepository_Variants(t *testing.T) {
+	db := setupTransactionDB()
+	repo := NewGormTransactionRepository(db)
+
+	user := &entity.User{Name: "alice"}
+	item := &entity.Item{Name: "hoody", Price: 300}
+	db.Create(user)
+	db.Create(item)
+	small := &entity.ItemVariant{ItemID: item.ID, Name: "S"}
+	large := &entity.ItemVariant{ItemID: item.ID, Name: "L"}
+	db.Create(small)
+	db.Create(large)
+
+	assert.NoError(t, repo.AddItems(user.ID, item.ID, small.ID, 1))
+	assert.NoError(t, repo.AddItems(user.ID, item.ID, large.ID, 2))
+	assert.NoError(t, repo.AddItems(user.ID, item.ID, large.ID, 1))
+
+	inventory, err := repo.GetUserInventory(user.ID)
+	assert.NoError(t, err)
+	assert.Len(t, inventory, 2)
+	quantities := map[string]uint{}
+	for _, v := range inventory {
+		quantities[v.Variant.Name] = v.Quantity
+	}
+	assert.Equal(t, map[string]uint{"S": 1, "L": 3}, quantities)
+}
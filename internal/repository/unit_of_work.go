@@ -4,17 +4,29 @@ import (
 	"database/sql"
 	"gorm.io/gorm"
 	"merch_shop/internal/entity"
+	"time"
 )
 
 type UserRepository interface {
 	CreateUser(user *entity.User) error
 	UpdateUser(user *entity.User) error
+	UpdateUserColumns(user *entity.User, columns ...string) error
 	FindUserByName(name string) (*entity.User, error)
 	FindUserById(userId uint) (*entity.User, error)
+	FindUserByResetToken(tokenHash string) (*entity.User, error)
+	FindUserByOIDCSubject(subject string) (*entity.User, error)
+	FindUserByEmail(email string) (*entity.User, error)
+	SearchUsersByPrefix(prefix string, limit int) ([]entity.User, error)
+	FindUsersByInitial(initial string, limit int) ([]entity.User, error)
 }
 
 type TransactionRepository interface {
 	AddItem(userId uint, itemId uint) error
+	AddItems(userId uint, itemId uint, variantId uint, quantity uint) error
+	RemoveItems(userId uint, itemId uint, variantId uint, quantity uint) (bool, error)
+	CreateItemTransfer(transfer *entity.ItemTransfer) error
+	GetIncomeItemTransfers(userId uint) ([]entity.ItemTransfer, error)
+	GetOutcomeItemTransfers(userId uint) ([]entity.ItemTransfer, error)
 	GetItemByName(name string) (*entity.Item, error)
 	CreateTransaction(transaction *entity.Transaction) error
 	GetOutcomeTransactions(userId uint) ([]entity.Transaction, error)
@@ -22,10 +34,160 @@ type TransactionRepository interface {
 	GetUserInventory(userId uint) ([]entity.InventoryItem, error)
 }
 
+type ItemRepository interface {
+	SetItemCategory(itemId uint, category string) error
+	SetPurchaseLimit(itemId uint, purchaseLimit uint) error
+	DecrementStock(itemId uint) (bool, error)
+	AddStock(itemId uint, quantity uint) error
+	RestockItem(itemId uint, quantity uint) error
+	CreateVariant(variant *entity.ItemVariant) error
+	FindVariant(itemId uint, name string) (*entity.ItemVariant, error)
+	CountVariants(itemId uint) (int64, error)
+	DecrementVariantStock(variantId uint) (bool, error)
+	AddVariantStock(variantId uint, quantity uint) error
+	RestockVariant(variantId uint, quantity uint) error
+	GetItems(category string, tags []string) ([]entity.Item, error)
+	ReplaceItemTags(itemId uint, tags []string) error
+	CreateCategory(category *entity.Category) error
+	FindCategoryByName(name string) (*entity.Category, error)
+	GetCategories() ([]entity.Category, error)
+	CreateItemPrice(price *entity.ItemPrice) error
+	FindEffectivePrice(itemId uint, at time.Time) (*entity.ItemPrice, error)
+	GetItemPrices(itemId uint) ([]entity.ItemPrice, error)
+}
+
+type PaymentRequestRepository interface {
+	CreatePaymentRequest(request *entity.PaymentRequest) error
+	UpdatePaymentRequest(request *entity.PaymentRequest) error
+	FindPaymentRequestById(requestId uint) (*entity.PaymentRequest, error)
+	GetIncomingPaymentRequests(userId uint, now time.Time) ([]entity.PaymentRequest, error)
+	GetOutgoingPaymentRequests(userId uint, now time.Time) ([]entity.PaymentRequest, error)
+}
+
+type PurchaseRepository interface {
+	CreatePurchase(purchase *entity.Purchase) error
+	UpdatePurchase(purchase *entity.Purchase) error
+	FindPurchaseById(purchaseId uint) (*entity.Purchase, error)
+	FindReturnablePurchase(ownerId uint, itemId uint, variantId uint, since time.Time) (*entity.Purchase, error)
+	GetUserPurchases(ownerId uint) ([]entity.Purchase, error)
+	GetRefundedPurchases(buyerId uint) ([]entity.Purchase, error)
+	CountActivePurchases(buyerId uint, itemId uint) (int64, error)
+	GetOpenOrders() ([]entity.Purchase, error)
+	CountPromoRedemptions(buyerId uint, promoCodeId uint) (int64, error)
+}
+
+type PromoCodeRepository interface {
+	CreatePromoCode(promoCode *entity.PromoCode) error
+	FindPromoCodeByCode(code string) (*entity.PromoCode, error)
+	GetPromoCodes() ([]entity.PromoCode, error)
+	RedeemPromoCode(promoCodeId uint) (bool, error)
+	ReleasePromoCode(promoCodeId uint) error
+}
+
+type WishlistRepository interface {
+	AddWishlistItem(item *entity.WishlistItem) error
+	RemoveWishlistItem(userId uint, itemId uint, variantId uint) (bool, error)
+	GetWishlist(userId uint) ([]entity.WishlistItem, error)
+}
+
+type NotificationRepository interface {
+	CreateNotification(notification *entity.Notification) error
+	GetNotifications(userId uint) ([]entity.Notification, error)
+	MarkNotificationRead(userId uint, notificationId uint, at time.Time) (bool, error)
+}
+
+type GroupPurchaseRepository interface {
+	CreateGroupPurchase(groupPurchase *entity.GroupPurchase) error
+	UpdateGroupPurchase(groupPurchase *entity.GroupPurchase) error
+	FindGroupPurchaseById(groupPurchaseId uint) (*entity.GroupPurchase, error)
+	GetOpenGroupPurchases() ([]entity.GroupPurchase, error)
+	GetOverdueGroupPurchases(now time.Time) ([]entity.GroupPurchase, error)
+	CreatePledge(pledge *entity.Pledge) error
+}
+
+type WalletRepository interface {
+	CreateWallet(wallet *entity.Wallet) error
+	UpdateWallet(wallet *entity.Wallet) error
+	FindWalletById(walletId uint) (*entity.Wallet, error)
+	FindWalletByName(name string) (*entity.Wallet, error)
+	GetUserWallets(userId uint) ([]entity.Wallet, error)
+	SaveWalletMember(member *entity.WalletMember) error
+	RemoveWalletMember(walletId uint, userId uint) (bool, error)
+	CreateWalletTransaction(transaction *entity.WalletTransaction) error
+	GetWalletTransactions(walletId uint) ([]entity.WalletTransaction, error)
+}
+
+type TeamRepository interface {
+	CreateTeam(team *entity.Team) error
+	UpdateTeam(team *entity.Team) error
+	FindTeamByName(name string) (*entity.Team, error)
+	GetTeams() ([]entity.Team, error)
+	AddTeamMember(member *entity.TeamMember) error
+	SetTeamManager(teamId uint, userId uint, manager bool) error
+	RemoveTeamMember(teamId uint, userId uint) (bool, error)
+	GetTeamStats(teamId uint, from time.Time, to time.Time) (TeamStats, error)
+}
+
+type LeaderboardRepository interface {
+	GetLeaderboard(metric string, from time.Time, to time.Time, limit int) ([]LeaderboardEntry, error)
+}
+
+type AchievementRepository interface {
+	GetUserBadges(userId uint) ([]entity.UserBadge, error)
+	AwardBadge(badge *entity.UserBadge) (bool, error)
+	GetUserMetric(userId uint, metric string) (uint, error)
+	GetUserIds() ([]uint, error)
+}
+
+type LoginThrottleRepository interface {
+	FindThrottle(kind string, value string) (*entity.LoginThrottle, error)
+	RecordFailure(kind string, value string, now time.Time, staleBefore time.Time) (*entity.LoginThrottle, error)
+	ReleaseFailure(kind string, value string) error
+	DeleteThrottle(kind string, value string) (bool, error)
+	GetThrottles(minFailures uint, since time.Time) ([]entity.LoginThrottle, error)
+}
+
+type RecoveryCodeRepository interface {
+	CreateRecoveryCodes(codes []entity.RecoveryCode) error
+	DeleteRecoveryCodes(userId uint) error
+	UseRecoveryCode(userId uint, codeHash string, now time.Time) (bool, error)
+	CountUnusedRecoveryCodes(userId uint) (int64, error)
+}
+
+type APIKeyRepository interface {
+	CreateAPIKey(key *entity.APIKey) error
+	UpdateAPIKey(key *entity.APIKey) error
+	FindAPIKeyById(id uint) (*entity.APIKey, error)
+	FindAPIKeyByHash(keyHash string) (*entity.APIKey, error)
+	GetAPIKeys(userId uint) ([]entity.APIKey, error)
+	TouchAPIKey(id uint, now time.Time, before time.Time) error
+}
+
+type OIDCLoginRepository interface {
+	CreateOIDCLogin(login *entity.OIDCLogin) error
+	TakeOIDCLogin(stateHash string) (*entity.OIDCLogin, error)
+	DeleteExpiredOIDCLogins(before time.Time) error
+}
+
 type UnitOfWork interface {
 	BeginTransaction(opts ...*sql.TxOptions) (TransactionUnitOfWork, error)
 	UserRepository() UserRepository
 	TransactionRepository() TransactionRepository
+	ItemRepository() ItemRepository
+	PaymentRequestRepository() PaymentRequestRepository
+	PurchaseRepository() PurchaseRepository
+	PromoCodeRepository() PromoCodeRepository
+	WishlistRepository() WishlistRepository
+	NotificationRepository() NotificationRepository
+	GroupPurchaseRepository() GroupPurchaseRepository
+	WalletRepository() WalletRepository
+	TeamRepository() TeamRepository
+	LeaderboardRepository() LeaderboardRepository
+	AchievementRepository() AchievementRepository
+	LoginThrottleRepository() LoginThrottleRepository
+	RecoveryCodeRepository() RecoveryCodeRepository
+	APIKeyRepository() APIKeyRepository
+	OIDCLoginRepository() OIDCLoginRepository
 }
 
 type TransactionUnitOfWork interface {
@@ -68,3 +230,63 @@ func (u *GormUnitOfWork) UserRepository() UserRepository {
 func (u *GormUnitOfWork) TransactionRepository() TransactionRepository {
 	return NewGormTransactionRepository(u.db)
 }
+
+func (u *GormUnitOfWork) ItemRepository() ItemRepository {
+	return NewGormItemRepository(u.db)
+}
+
+func (u *GormUnitOfWork) PaymentRequestRepository() PaymentRequestRepository {
+	return NewGormPaymentRequestRepository(u.db)
+}
+
+func (u *GormUnitOfWork) PurchaseRepository() PurchaseRepository {
+	return NewGormPurchaseRepository(u.db)
+}
+
+func (u *GormUnitOfWork) PromoCodeRepository() PromoCodeRepository {
+	return NewGormPromoCodeRepository(u.db)
+}
+
+func (u *GormUnitOfWork) WishlistRepository() WishlistRepository {
+	return NewGormWishlistRepository(u.db)
+}
+
+func (u *GormUnitOfWork) NotificationRepository() NotificationRepository {
+	return NewGormNotificationRepository(u.db)
+}
+
+func (u *GormUnitOfWork) GroupPurchaseRepository() GroupPurchaseRepository {
+	return NewGormGroupPurchaseRepository(u.db)
+}
+
+func (u *GormUnitOfWork) WalletRepository() WalletRepository {
+	return NewGormWalletRepository(u.db)
+}
+
+func (u *GormUnitOfWork) TeamRepository() TeamRepository {
+	return NewGormTeamRepository(u.db)
+}
+
+func (u *GormUnitOfWork) LeaderboardRepository() LeaderboardRepository {
+	return NewGormLeaderboardRepository(u.db)
+}
+
+func (u *GormUnitOfWork) AchievementRepository() AchievementRepository {
+	return NewGormAchievementRepository(u.db)
+}
+
+func (u *GormUnitOfWork) LoginThrottleRepository() LoginThrottleRepository {
+	return NewGormLoginThrottleRepository(u.db)
+}
+
+func (u *GormUnitOfWork) RecoveryCodeRepository() RecoveryCodeRepository {
+	return NewGormRecoveryCodeRepository(u.db)
+}
+
+func (u *GormUnitOfWork) APIKeyRepository() APIKeyRepository {
+	return NewGormAPIKeyRepository(u.db)
+}
+
+func (u *GormUnitOfWork) OIDCLoginRepository() OIDCLoginRepository {
+	return NewGormOIDCLoginRepository(u.db)
+}
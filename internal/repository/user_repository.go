@@ -4,8 +4,11 @@ import (
 	"errors"
 	"gorm.io/gorm"
 	"merch_shop/internal/entity"
+	"strings"
 )
 
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 type GormUserRepository struct {
 	db *gorm.DB
 }
@@ -42,10 +45,89 @@ func (repo *GormUserRepository) FindUserByName(name string) (*entity.User, error
 	return user, nil
 }
 
+func (repo *GormUserRepository) FindUserByResetToken(tokenHash string) (*entity.User, error) {
+	user := new(entity.User)
+	err := repo.db.Where("reset_token_hash = ?", tokenHash).First(user).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (repo *GormUserRepository) FindUserByOIDCSubject(subject string) (*entity.User, error) {
+	user := new(entity.User)
+	err := repo.db.Where("oidc_subject = ?", subject).First(user).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// FindUserByEmail matches email ignoring case.
+func (repo *GormUserRepository) FindUserByEmail(email string) (*entity.User, error) {
+	user := new(entity.User)
+	err := repo.db.Where("LOWER(email) = ?", strings.ToLower(email)).First(user).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
 func (repo *GormUserRepository) UpdateUser(user *entity.User) error {
 	return repo.db.Save(user).Error
 }
 
+// UpdateUserColumns writes only the given columns of the user, so changes others committed since
+// the user was read, such as to the balance, are kept.
+func (repo *GormUserRepository) UpdateUserColumns(user *entity.User, columns ...string) error {
+	return repo.db.Model(user).Select(columns).Updates(user).Error
+}
+
 func (repo *GormUserRepository) CreateUser(user *entity.User) error {
 	return repo.db.Create(user).Error
 }
+
+// SearchUsersByPrefix finds users whose login name or any word of the display name starts with prefix,
+// ignoring case.
+func (repo *GormUserRepository) SearchUsersByPrefix(prefix string, limit int) ([]entity.User, error) {
+	pattern := likeEscaper.Replace(strings.ToLower(prefix))
+	var users []entity.User
+	err := repo.db.
+		Where(`LOWER(name) LIKE ? ESCAPE '\' OR LOWER(display_name) LIKE ? ESCAPE '\' OR LOWER(display_name) LIKE ? ESCAPE '\'`,
+			pattern+"%", pattern+"%", "% "+pattern+"%").
+		Order("name").
+		Limit(limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// FindUsersByInitial returns users whose login name or display name starts with the letter,
+// as candidates for fuzzy matching.
+func (repo *GormUserRepository) FindUsersByInitial(initial string, limit int) ([]entity.User, error) {
+	pattern := likeEscaper.Replace(strings.ToLower(initial)) + "%"
+	var users []entity.User
+	err := repo.db.
+		Where(`LOWER(name) LIKE ? ESCAPE '\' OR LOWER(display_name) LIKE ? ESCAPE '\'`, pattern, pattern).
+		Order("name").
+		Limit(limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
@@ -54,6 +54,31 @@ func TestGormUserRepository_FindUserByName(t *testing.T) {
 	})
 }
 
+func TestGormUserRepository_FindUserByOIDCSubject(t *testing.T) {
+	db := setupUserDB()
+	repo := NewGormUserRepository(db)
+	subject := "sub-1"
+	user := &entity.User{Name: "alice", Email: "Alice@Example.com", OIDCSubject: &subject, Balance: 1000}
+	db.Create(user)
+	db.Create(&entity.User{Name: "bob", Balance: 1000})
+
+	found, err := repo.FindUserByOIDCSubject("sub-1")
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, found.ID)
+
+	found, err = repo.FindUserByOIDCSubject("sub-2")
+	assert.NoError(t, err)
+	assert.Nil(t, found)
+
+	found, err = repo.FindUserByEmail("alice@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, found.ID)
+
+	found, err = repo.FindUserByEmail("bob@example.com")
+	assert.NoError(t, err)
+	assert.Nil(t, found)
+}
+
 func TestGormUserRepository_CreateUser(t *testing.T) {
 	db := setupUserDB()
 	repo := NewGormUserRepository(db)
@@ -83,3 +108,49 @@ func TestGormUserRepository_UpdateUser(t *testing.T) {
 	db.First(&updatedUser, user.ID)
 	assert.Equal(t, uint(1000), updatedUser.Balance)
 }
+
+func TestGormUserRepository_UpdateUserColumns(t *testing.T) {
+	db := setupUserDB()
+	repo := NewGormUserRepository(db)
+
+	user := &entity.User{Name: "test", Balance: 500, Bio: "Hi"}
+	db.Create(user)
+	db.Model(&entity.User{}).Where("id = ?", user.ID).Update("balance", 700)
+
+	user.Bio = ""
+	user.DisplayName = "Tester"
+	err := repo.UpdateUserColumns(user, "display_name", "avatar_url", "department", "bio")
+	assert.NoError(t, err)
+
+	var updatedUser entity.User
+	db.First(&updatedUser, user.ID)
+	assert.Equal(t, "Tester", updatedUser.DisplayName)
+	assert.Empty(t, updatedUser.Bio)
+	assert.Equal(t, uint(700), updatedUser.Balance)
+}
+
+func TestGormUserRepository_SearchUsersByPrefix(t *testing.T) {
+	db := setupUserDB()
+	repo := NewGormUserRepository(db)
+	for _, user := range []*entity.User{
+		{Name: "jsmith42", DisplayName: "John Smith"},
+		{Name: "adoe", DisplayName: "Anna Smithers"},
+		{Name: "smirnov"},
+		{Name: "sm_x"},
+	} {
+		db.Create(user)
+	}
+
+	users, err := repo.SearchUsersByPrefix("Smith", 10)
+	assert.NoError(t, err)
+	assert.Len(t, users, 2)
+	assert.Equal(t, "adoe", users[0].Name)
+
+	users, err = repo.SearchUsersByPrefix("sm_", 10)
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+
+	users, err = repo.FindUsersByInitial("s", 10)
+	assert.NoError(t, err)
+	assert.Len(t, users, 2)
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"merch_shop/internal/entity"
+)
+
+type GormWalletRepository struct {
+	db *gorm.DB
+}
+
+func NewGormWalletRepository(db *gorm.DB) *GormWalletRepository {
+	return &GormWalletRepository{
+		db: db,
+	}
+}
+
+func (repo *GormWalletRepository) CreateWallet(wallet *entity.Wallet) error {
+	return repo.db.Omit("Members").Create(wallet).Error
+}
+
+func (repo *GormWalletRepository) UpdateWallet(wallet *entity.Wallet) error {
+	return repo.db.Omit("Members").Save(wallet).Error
+}
+
+func (repo *GormWalletRepository) FindWalletById(walletId uint) (*entity.Wallet, error) {
+	wallet := new(entity.Wallet)
+	err := repo.db.Preload("Members.User").First(wallet, walletId).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return wallet, nil
+}
+
+func (repo *GormWalletRepository) FindWalletByName(name string) (*entity.Wallet, error) {
+	wallet := new(entity.Wallet)
+	err := repo.db.Preload("Members.User").Where("name = ?", name).First(wallet).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return wallet, nil
+}
+
+// GetUserWallets returns the wallets the user is a member of.
+func (repo *GormWalletRepository) GetUserWallets(userId uint) ([]entity.Wallet, error) {
+	var wallets []entity.Wallet
+	err := repo.db.Preload("Members.User").
+		Where("id IN (?)", repo.db.Model(&entity.WalletMember{}).Select("wallet_id").Where("user_id = ?", userId)).
+		Order("name").
+		Find(&wallets).Error
+	if err != nil {
+		return nil, err
+	}
+	return wallets, nil
+}
+
+// SaveWalletMember adds the user to the wallet or changes the role of an existing member.
+func (repo *GormWalletRepository) SaveWalletMember(member *entity.WalletMember) error {
+	return repo.db.Omit("User").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "wallet_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role", "updated_at"}),
+	}).Create(member).Error
+}
+
+func (repo *GormWalletRepository) RemoveWalletMember(walletId uint, userId uint) (bool, error) {
+	result := repo.db.Unscoped().
+		Where("wallet_id = ? AND user_id = ?", walletId, userId).
+		Delete(&entity.WalletMember{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (repo *GormWalletRepository) CreateWalletTransaction(transaction *entity.WalletTransaction) error {
+	return repo.db.Omit("User", "ToUser", "Item", "Variant").Create(transaction).Error
+}
+
+func (repo *GormWalletRepository) GetWalletTransactions(walletId uint) ([]entity.WalletTransaction, error) {
+	var transactions []entity.WalletTransaction
+	err := repo.db.Joins("User").Joins("ToUser").Joins("Item").Joins("Variant").
+		Where("wallet_id = ?", walletId).
+		Order("wallet_transactions.created_at DESC").
+		Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
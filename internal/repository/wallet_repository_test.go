@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"testing"
+)
+
+func setupWalletDB() *gorm.DB {
+	db, _ := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	db.AutoMigrate(&entity.User{}, &entity.Item{}, &entity.ItemVariant{}, &entity.Wallet{}, &entity.WalletMember{}, &entity.WalletTransaction{})
+	return db
+}
+
+func TestGormWalletRepository(t *testing.T) {
+	db := setupWalletDB()
+	repo := NewGormWalletRepository(db)
+
+	alice := &entity.User{Name: "alice"}
+	bob := &entity.User{Name: "bob"}
+	item := &entity.Item{Name: "cup", Price: 20}
+	db.Create(alice)
+	db.Create(bob)
+	db.Create(item)
+
+	wallet := &entity.Wallet{Name: "platform"}
+	other := &entity.Wallet{Name: "design"}
+	assert.NoError(t, repo.CreateWallet(wallet))
+	assert.NoError(t, repo.CreateWallet(other))
+	assert.NoError(t, repo.SaveWalletMember(&entity.WalletMember{WalletID: wallet.ID, UserID: alice.ID, Role: entity.WalletOwner}))
+	assert.NoError(t, repo.SaveWalletMember(&entity.WalletMember{WalletID: wallet.ID, UserID: bob.ID, Role: entity.WalletViewer}))
+	assert.NoError(t, repo.SaveWalletMember(&entity.WalletMember{WalletID: wallet.ID, UserID: bob.ID, Role: entity.WalletSpender}))
+	assert.NoError(t, repo.SaveWalletMember(&entity.WalletMember{WalletID: other.ID, UserID: alice.ID, Role: entity.WalletOwner}))
+
+	found, err := repo.FindWalletByName("platform")
+	assert.NoError(t, err)
+	assert.Len(t, found.Members, 2)
+	assert.Equal(t, "bob", found.Members[1].User.Name)
+	assert.Equal(t, entity.WalletSpender, found.Members[1].Role)
+
+	wallets, err := repo.GetUserWallets(bob.ID)
+	assert.NoError(t, err)
+	assert.Len(t, wallets, 1)
+	assert.Equal(t, "platform", wallets[0].Name)
+
+	assert.NoError(t, repo.CreateWalletTransaction(&entity.WalletTransaction{WalletID: wallet.ID, Type: entity.WalletDeposit, UserID: alice.ID, Amount: 100}))
+	assert.NoError(t, repo.CreateWalletTransaction(&entity.WalletTransaction{
+		WalletID: wallet.ID, Type: entity.WalletPurchase, UserID: bob.ID, ToId: &alice.ID, Amount: 20, ItemID: &item.ID,
+	}))
+
+	transactions, err := repo.GetWalletTransactions(wallet.ID)
+	assert.NoError(t, err)
+	assert.Len(t, transactions, 2)
+	assert.Equal(t, "cup", transactions[0].Item.Name)
+	assert.Equal(t, "alice", transactions[0].ToUser.Name)
+	assert.Nil(t, transactions[1].Item)
+
+	removed, err := repo.RemoveWalletMember(wallet.ID, bob.ID)
+	assert.NoError(t, err)
+	assert.True(t, removed)
+}
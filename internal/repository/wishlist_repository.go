@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"merch_shop/internal/entity"
+)
+
+type GormWishlistRepository struct {
+	db *gorm.DB
+}
+
+func NewGormWishlistRepository(db *gorm.DB) *GormWishlistRepository {
+	return &GormWishlistRepository{
+		db: db,
+	}
+}
+
+// AddWishlistItem adds the item to the wishlist; adding an item that is already there does nothing.
+func (repo *GormWishlistRepository) AddWishlistItem(item *entity.WishlistItem) error {
+	return repo.db.Omit("User", "Item", "Variant").Clauses(clause.OnConflict{DoNothing: true}).Create(item).Error
+}
+
+func (repo *GormWishlistRepository) RemoveWishlistItem(userId uint, itemId uint, variantId uint) (bool, error) {
+	result := repo.db.Unscoped().
+		Where("user_id = ? AND item_id = ? AND variant_id = ?", userId, itemId, variantId).
+		Delete(&entity.WishlistItem{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (repo *GormWishlistRepository) GetWishlist(userId uint) ([]entity.WishlistItem, error) {
+	var items []entity.WishlistItem
+	err := repo.db.Joins("Item").Joins("Variant").
+		Where("user_id = ?", userId).
+		Order("wishlist_items.created_at").
+		Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"testing"
+)
+
+func setupWishlistDB() *gorm.DB {
+	db, _ := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	db.AutoMigrate(&entity.User{}, &entity.Item{}, &entity.ItemVariant{}, &entity.WishlistItem{})
+	return db
+}
+
+func TestGormWishlistRepository(t *testing.T) {
+	db := setupWishlistDB()
+	repo := NewGormWishlistRepository(db)
+
+	user := &entity.User{Name: "alice"}
+	item := &entity.Item{Name: "pink-hoody", Price: 500}
+	db.Create(user)
+	db.Create(item)
+	variant := &entity.ItemVariant{ItemID: item.ID, Name: "XL"}
+	db.Create(variant)
+
+	assert.NoError(t, repo.AddWishlistItem(&entity.WishlistItem{UserID: user.ID, ItemID: item.ID}))
+	assert.NoError(t, repo.AddWishlistItem(&entity.WishlistItem{UserID: user.ID, ItemID: item.ID}))
+	assert.NoError(t, repo.AddWishlistItem(&entity.WishlistItem{UserID: user.ID, ItemID: item.ID, VariantID: variant.ID}))
+
+	wishlist, err := repo.GetWishlist(user.ID)
+	assert.NoError(t, err)
+	assert.Len(t, wishlist, 2)
+	assert.Equal(t, "pink-hoody", wishlist[0].Item.Name)
+	assert.Equal(t, "XL", wishlist[1].Variant.Name)
+
+	removed, err := repo.RemoveWishlistItem(user.ID, item.ID, 0)
+	assert.NoError(t, err)
+	assert.True(t, removed)
+
+	removed, err = repo.RemoveWishlistItem(user.ID, item.ID, 0)
+	assert.NoError(t, err)
+	assert.False(t, removed)
+}
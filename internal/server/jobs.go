@@ -0,0 +1,30 @@
+package server
+
+import (
+	"context"
+	"log"
+	"merch_shop/internal/repository"
+	"merch_shop/internal/service"
+	"time"
+)
+
+// RunJobs runs periodic background work until ctx is done.
+func (server *Server) RunJobs(ctx context.Context) {
+	groupPurchaseService := service.NewGroupPurchaseService(repository.NewGormUnitOfWork(server.DB), server.Cfg.Achievements.Rules)
+
+	ticker := time.NewTicker(server.Cfg.GroupPurchase.ExpiryCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			expired, err := groupPurchaseService.ExpireGroupPurchases()
+			if err != nil {
+				log.Printf("Failed to expire group purchases: %v", err)
+			} else if expired > 0 {
+				log.Printf("Expired %d group purchases", expired)
+			}
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package server
 
 import (
+	"log"
+	"merch_shop/internal/config"
+	"merch_shop/internal/entity"
 	"merch_shop/internal/handlers"
 	"merch_shop/internal/middleware"
 	"merch_shop/internal/provider"
@@ -8,20 +11,110 @@ import (
 	"merch_shop/internal/service"
 )
 
+// apiKeyRoutes lists the routes service accounts may call with an API key and the scope each needs.
+var apiKeyRoutes = map[string]string{
+	"POST /api/sendCoin":          entity.ScopeCoinsGrant,
+	"POST /api/sendCoin/batch":    entity.ScopeCoinsGrant,
+	"GET /api/items":              entity.ScopeCatalogRead,
+	"GET /api/categories":         entity.ScopeCatalogRead,
+	"GET /api/items/:name/prices": entity.ScopeCatalogRead,
+	"GET /api/info":               entity.ScopeAccountRead,
+}
+
 func (server *Server) ConfigureRoutes() {
 	uow := repository.NewGormUnitOfWork(server.DB)
 	jwtAuth := provider.NewJWTAuth([]byte(server.Cfg.JWT.SigningKey), server.Cfg.JWT.Duration)
 	jwtMiddleware := middleware.JWTAuthMiddleware(jwtAuth)
-	transactionService := service.NewTransactionService(uow)
-	authService := service.NewAuthService(jwtAuth, uow)
+	transactionService := service.NewTransactionService(uow, server.Cfg.Achievements.Rules)
+	passwordPolicy, err := service.LoadPasswordPolicy(server.Cfg.Auth)
+	if err != nil {
+		log.Fatalf("Error loading password policy: %v", err)
+	}
+	var authenticator service.Authenticator = service.NewPasswordAuthenticator(uow, passwordPolicy)
+	if server.Cfg.Auth.Authenticator == config.AuthenticatorLDAP {
+		ldapCfg := server.Cfg.LDAP
+		directory := provider.NewLDAPClient(ldapCfg.URL, ldapCfg.BindDN, ldapCfg.BindPassword, ldapCfg.BaseDN, ldapCfg.UserAttribute, ldapCfg.Timeout)
+		authenticator, err = service.NewLDAPAuthenticator(uow, directory, ldapCfg)
+		if err != nil {
+			log.Fatalf("Error configuring LDAP authenticator: %v", err)
+		}
+	}
+	authService := service.NewAuthService(jwtAuth, uow, authenticator, passwordPolicy, server.Cfg.Auth)
+	sessionMiddleware := middleware.SessionMiddleware(authService)
+	apiKeyService := service.NewAPIKeyService(uow, server.Cfg.Auth.APIKeyRotationGrace)
+	authMiddleware := middleware.APIKeyMiddleware(apiKeyService, jwtMiddleware)
+	paymentRequestService := service.NewPaymentRequestService(uow, server.Cfg.PaymentRequest.TTL, server.Cfg.Achievements.Rules)
+	refundService := service.NewRefundService(uow, server.Cfg.Shop.ReturnWindow)
+	catalogService := service.NewCatalogService(uow)
+	fulfillmentService := service.NewFulfillmentService(uow)
+	promoCodeService := service.NewPromoCodeService(uow)
+	wishlistService := service.NewWishlistService(uow)
+	notificationService := service.NewNotificationService(uow)
+	groupPurchaseService := service.NewGroupPurchaseService(uow, server.Cfg.Achievements.Rules)
+	walletService := service.NewWalletService(uow)
+	teamService := service.NewTeamService(uow)
+	leaderboardService := service.NewLeaderboardService(uow)
+	achievementService := service.NewAchievementService(uow, server.Cfg.Achievements.Rules)
+	profileService := service.NewProfileService(uow)
 
 	transactionHandler := handlers.NewTransactionHandler(transactionService)
 	authHandler := handlers.NewAuthHandler(authService)
+	paymentRequestHandler := handlers.NewPaymentRequestHandler(paymentRequestService)
+	refundHandler := handlers.NewRefundHandler(refundService)
+	catalogHandler := handlers.NewCatalogHandler(catalogService)
+	fulfillmentHandler := handlers.NewFulfillmentHandler(fulfillmentService)
+	promoCodeHandler := handlers.NewPromoCodeHandler(promoCodeService)
+	wishlistHandler := handlers.NewWishlistHandler(wishlistService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	groupPurchaseHandler := handlers.NewGroupPurchaseHandler(groupPurchaseService)
+	walletHandler := handlers.NewWalletHandler(walletService)
+	teamHandler := handlers.NewTeamHandler(teamService)
+	leaderboardHandler := handlers.NewLeaderboardHandler(leaderboardService)
+	achievementHandler := handlers.NewAchievementHandler(achievementService)
+	profileHandler := handlers.NewProfileHandler(profileService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
 	apiRoute := server.Gin.Group("/api")
 	authHandler.Routes(apiRoute)
 
-	protectedRoutes := apiRoute.Group("/", jwtMiddleware)
+	if server.Cfg.OIDC.Issuer != "" {
+		oidcCfg := server.Cfg.OIDC
+		oidcProvider := provider.NewOIDCProvider(oidcCfg.Issuer, oidcCfg.ClientID, oidcCfg.ClientSecret, oidcCfg.RedirectURL, nil)
+		oidcHandler := handlers.NewOIDCHandler(service.NewOIDCService(authService, oidcProvider, oidcCfg))
+		oidcHandler.Routes(apiRoute)
+	}
+
+	protectedRoutes := apiRoute.Group("/", authMiddleware, sessionMiddleware, middleware.RequireAPIKeyScope(apiKeyRoutes))
 
+	authHandler.ProtectedRoutes(protectedRoutes)
 	transactionHandler.Routes(protectedRoutes)
+	paymentRequestHandler.Routes(protectedRoutes)
+	refundHandler.Routes(protectedRoutes)
+	catalogHandler.Routes(protectedRoutes)
+	wishlistHandler.Routes(protectedRoutes)
+	notificationHandler.Routes(protectedRoutes)
+	groupPurchaseHandler.Routes(protectedRoutes)
+	walletHandler.Routes(protectedRoutes)
+	teamHandler.Routes(protectedRoutes)
+	leaderboardHandler.Routes(protectedRoutes)
+	achievementHandler.Routes(protectedRoutes)
+	profileHandler.Routes(protectedRoutes)
+
+	adminRoutes := protectedRoutes.Group("/admin", middleware.RequireRole(entity.RoleAdmin))
+
+	authHandler.AdminRoutes(adminRoutes)
+	refundHandler.AdminRoutes(adminRoutes)
+	catalogHandler.AdminRoutes(adminRoutes)
+	promoCodeHandler.AdminRoutes(adminRoutes)
+	teamHandler.AdminRoutes(adminRoutes)
+	achievementHandler.AdminRoutes(adminRoutes)
+	apiKeyHandler.AdminRoutes(adminRoutes)
+
+	searchRoutes := protectedRoutes.Group("/", middleware.RateLimit(server.Cfg.UserSearch.RateLimit, server.Cfg.UserSearch.RateWindow))
+
+	profileHandler.SearchRoutes(searchRoutes)
+
+	fulfillmentRoutes := protectedRoutes.Group("/fulfillment", middleware.RequireRole(entity.RoleAdmin, entity.RoleFulfillment))
+
+	fulfillmentHandler.Routes(fulfillmentRoutes)
 
 }
@@ -19,9 +19,14 @@ type Server struct {
 }
 
 func NewServer(cfg *config.Config) *Server {
+	engine := gin.Default()
+	// Client IPs key the login throttle and rate limits, so X-Forwarded-For is only believed from known proxies.
+	if err := engine.SetTrustedProxies(cfg.HTTP.TrustedProxies); err != nil {
+		log.Fatalf("trusted proxies: %s\n", err)
+	}
 	return &Server{
 		Cfg: cfg,
-		Gin: gin.Default(),
+		Gin: engine,
 		DB:  db.SetupDB(&cfg.DB),
 	}
 }
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"merch_shop/internal/config"
+	"merch_shop/internal/entity"
+	"merch_shop/internal/model"
+	"merch_shop/internal/repository"
+	"time"
+)
+
+type AchievementService struct {
+	uow   repository.UnitOfWork
+	rules []config.AchievementRule
+	now   func() time.Time
+}
+
+func NewAchievementService(uow repository.UnitOfWork, rules []config.AchievementRule) *AchievementService {
+	return &AchievementService{uow: uow, rules: rules, now: time.Now}
+}
+
+// GetAchievements lists every configured achievement with the user's progress towards it.
+func (a AchievementService) GetAchievements(userId uint) ([]model.Achievement, error) {
+	achievementRepository := a.uow.AchievementRepository()
+	badges, err := achievementRepository.GetUserBadges(userId)
+	if err != nil {
+		return nil, fmt.Errorf("error getting badges")
+	}
+	awarded := make(map[string]time.Time, len(badges))
+	for _, v := range badges {
+		awarded[v.Badge] = v.CreatedAt
+	}
+	metrics := make(map[string]uint)
+	achievements := make([]model.Achievement, 0, len(a.rules))
+	for _, rule := range a.rules {
+		progress, found := metrics[rule.Metric]
+		if !found {
+			progress, err = achievementRepository.GetUserMetric(userId, rule.Metric)
+			if err != nil {
+				return nil, fmt.Errorf("error getting achievement progress")
+			}
+			metrics[rule.Metric] = progress
+		}
+		achievement := model.Achievement{
+			Badge:       rule.Badge,
+			Description: rule.Description,
+			Metric:      rule.Metric,
+			Threshold:   rule.Threshold,
+			Bonus:       rule.Bonus,
+			Progress:    progress,
+		}
+		if at, found := awarded[rule.Badge]; found {
+			achievement.AwardedAt = &at
+		}
+		achievements = append(achievements, achievement)
+	}
+	return achievements, nil
+}
+
+// EvaluateAll checks every user against the rules, so that badges added to the configuration are
+// awarded retroactively for past activity. Each user is evaluated in a separate transaction.
+func (a AchievementService) EvaluateAll() (int, error) {
+	userIds, err := a.uow.AchievementRepository().GetUserIds()
+	if err != nil {
+		return 0, fmt.Errorf("error getting users")
+	}
+	total := 0
+	for _, userId := range userIds {
+		awarded, err := a.evaluate(userId)
+		if err != nil {
+			return total, err
+		}
+		total += awarded
+	}
+	return total, nil
+}
+
+func (a AchievementService) evaluate(userId uint) (int, error) {
+	tx, err := a.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction")
+	}
+	user, err := tx.UserRepository().FindUserById(userId)
+	if err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("failed to find user")
+	}
+	if user == nil {
+		tx.Rollback()
+		return 0, nil
+	}
+	awarded, err := awardAchievements(tx, a.rules, user, a.now())
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	tx.Commit()
+	return awarded, nil
+}
+
+// awardAchievements gives the user every badge whose threshold is reached and which the user does
+// not have yet, notifies the user and credits the bonus coins. It returns the number of new badges.
+// Service accounts grant coins on behalf of integrations and never earn badges.
+// The caller owns tx and is responsible for rolling it back on error.
+func awardAchievements(tx repository.UnitOfWork, rules []config.AchievementRule, user *entity.User, now time.Time) (int, error) {
+	if len(rules) == 0 || user.Role == entity.RoleService {
+		return 0, nil
+	}
+	achievementRepository := tx.AchievementRepository()
+	badges, err := achievementRepository.GetUserBadges(user.ID)
+	if err != nil {
+		return 0, fmt.Errorf("error getting badges")
+	}
+	earned := make(map[string]bool, len(badges))
+	for _, v := range badges {
+		earned[v.Badge] = true
+	}
+
+	metrics := make(map[string]uint)
+	awarded := 0
+	var bonus uint
+	for _, rule := range rules {
+		if earned[rule.Badge] {
+			continue
+		}
+		value, found := metrics[rule.Metric]
+		if !found {
+			value, err = achievementRepository.GetUserMetric(user.ID, rule.Metric)
+			if err != nil {
+				return 0, fmt.Errorf("error getting achievement progress")
+			}
+			metrics[rule.Metric] = value
+		}
+		if value < rule.Threshold {
+			continue
+		}
+		created, err := achievementRepository.AwardBadge(&entity.UserBadge{
+			UserID:      user.ID,
+			Badge:       rule.Badge,
+			Description: rule.Description,
+			Bonus:       rule.Bonus,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to award badge")
+		}
+		if !created {
+			continue
+		}
+		message := fmt.Sprintf("You earned the %s badge", rule.Badge)
+		if rule.Bonus > 0 {
+			message = fmt.Sprintf("You earned the %s badge and %d bonus coins", rule.Badge, rule.Bonus)
+		}
+		err = tx.NotificationRepository().CreateNotification(&entity.Notification{UserID: user.ID, Message: message})
+		if err != nil {
+			return 0, fmt.Errorf("failed to create notification")
+		}
+		awarded++
+		bonus += rule.Bonus
+	}
+
+	if bonus > 0 {
+		previousBalance := user.Balance
+		user.Balance += bonus
+		if err := tx.UserRepository().UpdateUser(user); err != nil {
+			return 0, fmt.Errorf("failed to update user")
+		}
+		if err := notifyAffordable(tx, user, previousBalance, now); err != nil {
+			return 0, err
+		}
+	}
+	return awarded, nil
+}
+
+func badgesModel(badges []entity.UserBadge) []model.Badge {
+	badgesModel := make([]model.Badge, 0, len(badges))
+	for _, v := range badges {
+		badgesModel = append(badgesModel, model.Badge{
+			Badge:       v.Badge,
+			Description: v.Description,
+			Bonus:       v.Bonus,
+			AwardedAt:   v.CreatedAt,
+		})
+	}
+	return badgesModel
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+	"merch_shop/internal/config"
+	"merch_shop/internal/entity"
+	"testing"
+	"time"
+)
+
+type MockAchievementRepository struct {
+	mock.Mock
+}
+
+func (m *MockAchievementRepository) GetUserBadges(userId uint) ([]entity.UserBadge, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]entity.UserBadge), args.Error(1)
+}
+
+func (m *MockAchievementRepository) AwardBadge(badge *entity.UserBadge) (bool, error) {
+	args := m.Called(badge)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAchievementRepository) GetUserMetric(userId uint, metric string) (uint, error) {
+	args := m.Called(userId, metric)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockAchievementRepository) GetUserIds() ([]uint, error) {
+	args := m.Called()
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+var testAchievementRules = []config.AchievementRule{
+	{Badge: "first-purchase", Metric: "purchases", Threshold: 1},
+	{Badge: "team-player", Metric: "colleagues_thanked", Threshold: 10, Bonus: 50},
+	{Badge: "recognised", Metric: "coins_received", Threshold: 1000, Bonus: 100},
+}
+
+func TestTransactionService_SendCoin_AwardsAchievements(t *testing.T) {
+	fromUser := &entity.User{Model: gorm.Model{ID: 1}, Balance: 200}
+	toUser := &entity.User{Model: gorm.Model{ID: 2}, Name: "user2", Balance: 950}
+
+	userRepo := &MockUserRepository{}
+	userRepo.On("FindUserById", uint(1)).Return(fromUser, nil)
+	userRepo.On("FindUserByName", "user2").Return(toUser, nil)
+	userRepo.On("UpdateUser", mock.Anything).Return(nil)
+
+	transactionRepo := &MockTransactionRepository{}
+	transactionRepo.On("CreateTransaction", mock.Anything).Return(nil)
+
+	achievementRepo := &MockAchievementRepository{}
+	achievementRepo.On("GetUserBadges", uint(1)).Return([]entity.UserBadge{{UserID: 1, Badge: "first-purchase"}}, nil)
+	achievementRepo.On("GetUserMetric", uint(1), "colleagues_thanked").Return(uint(10), nil)
+	achievementRepo.On("GetUserMetric", uint(1), "coins_received").Return(uint(0), nil)
+	achievementRepo.On("AwardBadge", mock.MatchedBy(func(badge *entity.UserBadge) bool {
+		return badge.UserID == 1 && badge.Badge == "team-player" && badge.Bonus == 50
+	})).Return(true, nil)
+	achievementRepo.On("GetUserBadges", uint(2)).Return([]entity.UserBadge{}, nil)
+	achievementRepo.On("GetUserMetric", uint(2), "purchases").Return(uint(0), nil)
+	achievementRepo.On("GetUserMetric", uint(2), "colleagues_thanked").Return(uint(0), nil)
+	achievementRepo.On("GetUserMetric", uint(2), "coins_received").Return(uint(1050), nil)
+	achievementRepo.On("AwardBadge", mock.MatchedBy(func(badge *entity.UserBadge) bool {
+		return badge.UserID == 2 && badge.Badge == "recognised"
+	})).Return(false, nil)
+
+	notificationRepo := &MockNotificationRepository{}
+	notificationRepo.On("CreateNotification", mock.MatchedBy(func(notification *entity.Notification) bool {
+		return notification.UserID == 1 && notification.Message == "You earned the team-player badge and 50 bonus coins"
+	})).Return(nil)
+
+	tuow := &MockTransactionUnitOfWork{
+		UserRepo:         userRepo,
+		TransactionRepo:  transactionRepo,
+		WishlistRepo:     emptyWishlist(1, 2),
+		NotificationRepo: notificationRepo,
+		AchievementRepo:  achievementRepo,
+	}
+	service := NewTransactionService(&MockUnitOfWork{transactionUnitOfWork: tuow}, testAchievementRules)
+
+	err := service.SendCoin(1, "user2", 100)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(150), fromUser.Balance)
+	assert.Equal(t, uint(1050), toUser.Balance)
+	achievementRepo.AssertNotCalled(t, "GetUserMetric", uint(1), "purchases")
+	achievementRepo.AssertExpectations(t)
+	notificationRepo.AssertNumberOfCalls(t, "CreateNotification", 1)
+	assert.True(t, tuow.commitCalled)
+}
+
+func TestPaymentRequestService_AcceptPaymentRequest_AwardsAchievements(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	request := &entity.PaymentRequest{Model: gorm.Model{ID: 5}, RequesterId: 1, PayerId: 2, Amount: 100,
+		Status: entity.PaymentRequestPending, ExpiresAt: now.Add(time.Hour)}
+	requester := &entity.User{Model: gorm.Model{ID: 1}, Balance: 950}
+	payer := &entity.User{Model: gorm.Model{ID: 2}, Balance: 200}
+
+	userRepo := &MockUserRepository{}
+	userRepo.On("FindUserById", uint(1)).Return(requester, nil)
+	userRepo.On("FindUserById", uint(2)).Return(payer, nil)
+	userRepo.On("UpdateUser", mock.Anything).Return(nil)
+
+	transactionRepo := &MockTransactionRepository{}
+	transactionRepo.On("CreateTransaction", mock.Anything).Return(nil)
+
+	paymentRequestRepo := &MockPaymentRequestRepository{}
+	paymentRequestRepo.On("FindPaymentRequestById", uint(5)).Return(request, nil)
+	paymentRequestRepo.On("UpdatePaymentRequest", request).Return(nil)
+
+	achievementRepo := &MockAchievementRepository{}
+	achievementRepo.On("GetUserBadges", uint(2)).Return([]entity.UserBadge{{UserID: 2, Badge: "first-purchase"}, {UserID: 2, Badge: "team-player"}}, nil)
+	achievementRepo.On("GetUserMetric", uint(2), "coins_received").Return(uint(0), nil)
+	achievementRepo.On("GetUserBadges", uint(1)).Return([]entity.UserBadge{{UserID: 1, Badge: "first-purchase"}, {UserID: 1, Badge: "team-player"}}, nil)
+	achievementRepo.On("GetUserMetric", uint(1), "coins_received").Return(uint(1050), nil)
+	achievementRepo.On("AwardBadge", mock.MatchedBy(func(badge *entity.UserBadge) bool {
+		return badge.UserID == 1 && badge.Badge == "recognised" && badge.Bonus == 100
+	})).Return(true, nil)
+
+	notificationRepo := &MockNotificationRepository{}
+	notificationRepo.On("CreateNotification", mock.MatchedBy(func(notification *entity.Notification) bool {
+		return notification.UserID == 1 && notification.Message == "You earned the recognised badge and 100 bonus coins"
+	})).Return(nil)
+
+	tuow := &MockTransactionUnitOfWork{
+		UserRepo:           userRepo,
+		TransactionRepo:    transactionRepo,
+		PaymentRequestRepo: paymentRequestRepo,
+		WishlistRepo:       emptyWishlist(1),
+		NotificationRepo:   notificationRepo,
+		AchievementRepo:    achievementRepo,
+	}
+	service := NewPaymentRequestService(&MockUnitOfWork{transactionUnitOfWork: tuow}, time.Hour, testAchievementRules)
+	service.now = func() time.Time { return now }
+
+	err := service.AcceptPaymentRequest(2, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(100), payer.Balance)
+	assert.Equal(t, uint(1150), requester.Balance)
+	achievementRepo.AssertExpectations(t)
+	assert.True(t, tuow.commitCalled)
+}
+
+func TestAwardAchievements_SkipsServiceAccounts(t *testing.T) {
+	bot := &entity.User{Model: gorm.Model{ID: 1}, Balance: 500, Role: entity.RoleService}
+	achievementRepo := &MockAchievementRepository{}
+
+	awarded, err := awardAchievements(&MockTransactionUnitOfWork{AchievementRepo: achievementRepo}, testAchievementRules, bot, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, awarded)
+	assert.Equal(t, uint(500), bot.Balance)
+	achievementRepo.AssertNotCalled(t, "GetUserBadges", mock.Anything)
+}
+
+func TestAchievementService_GetAchievements(t *testing.T) {
+	achievementRepo := &MockAchievementRepository{}
+	achievementRepo.On("GetUserBadges", uint(1)).Return([]entity.UserBadge{{UserID: 1, Badge: "first-purchase"}}, nil)
+	achievementRepo.On("GetUserMetric", uint(1), "purchases").Return(uint(3), nil)
+	achievementRepo.On("GetUserMetric", uint(1), "colleagues_thanked").Return(uint(4), nil)
+	achievementRepo.On("GetUserMetric", uint(1), "coins_received").Return(uint(200), nil)
+
+	service := NewAchievementService(&MockUnitOfWork{transactionUnitOfWork: &MockTransactionUnitOfWork{AchievementRepo: achievementRepo}}, testAchievementRules)
+
+	res, err := service.GetAchievements(1)
+	assert.NoError(t, err)
+	assert.Len(t, res, 3)
+	assert.NotNil(t, res[0].AwardedAt)
+	assert.Equal(t, uint(4), res[1].Progress)
+	assert.Nil(t, res[1].AwardedAt)
+}
+
+func TestAchievementService_EvaluateAll(t *testing.T) {
+	alice := &entity.User{Model: gorm.Model{ID: 1}, Balance: 10}
+
+	userRepo := &MockUserRepository{}
+	userRepo.On("FindUserById", uint(1)).Return(alice, nil)
+	userRepo.On("FindUserById", uint(2)).Return((*entity.User)(nil), nil)
+	userRepo.On("UpdateUser", alice).Return(nil)
+
+	achievementRepo := &MockAchievementRepository{}
+	achievementRepo.On("GetUserIds").Return([]uint{1, 2}, nil)
+	achievementRepo.On("GetUserBadges", uint(1)).Return([]entity.UserBadge{}, nil)
+	achievementRepo.On("GetUserMetric", uint(1), "purchases").Return(uint(1), nil)
+	achievementRepo.On("GetUserMetric", uint(1), "colleagues_thanked").Return(uint(12), nil)
+	achievementRepo.On("GetUserMetric", uint(1), "coins_received").Return(uint(0), nil)
+	achievementRepo.On("AwardBadge", mock.Anything).Return(true, nil)
+
+	notificationRepo := &MockNotificationRepository{}
+	notificationRepo.On("CreateNotification", mock.Anything).Return(nil)
+
+	tuow := &MockTransactionUnitOfWork{
+		UserRepo:         userRepo,
+		WishlistRepo:     emptyWishlist(1),
+		NotificationRepo: notificationRepo,
+		AchievementRepo:  achievementRepo,
+	}
+	service := NewAchievementService(&MockUnitOfWork{transactionUnitOfWork: tuow}, testAchievementRules)
+
+	awarded, err := service.EvaluateAll()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, awarded)
+	assert.Equal(t, uint(60), alice.Balance)
+	achievementRepo.AssertNumberOfCalls(t, "AwardBadge", 2)
+	assert.True(t, tuow.commitCalled)
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"merch_shop/internal/entity"
+	"merch_shop/internal/model"
+	"merch_shop/internal/provider"
+	"merch_shop/internal/repository"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	apiKeyPrefix = "msk_"
+	// apiKeyTouchInterval limits how often the last use of a key is written.
+	apiKeyTouchInterval = time.Minute
+)
+
+type APIKeyService struct {
+	uow           repository.UnitOfWork
+	rotationGrace time.Duration
+	now           func() time.Time
+}
+
+func NewAPIKeyService(uow repository.UnitOfWork, rotationGrace time.Duration) APIKeyService {
+	return APIKeyService{uow: uow, rotationGrace: rotationGrace, now: time.Now}
+}
+
+// CreateServiceAccount creates a user that can only be signed in with API keys. Balance is the
+// budget the account can grant.
+func (a APIKeyService) CreateServiceAccount(request model.ServiceAccountRequest) (model.ServiceAccount, error) {
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return model.ServiceAccount{}, fmt.Errorf("name is required")
+	}
+	userRepository := a.uow.UserRepository()
+	existing, err := userRepository.FindUserByName(name)
+	if err != nil {
+		return model.ServiceAccount{}, fmt.Errorf("failed to find user")
+	}
+	if existing != nil {
+		return model.ServiceAccount{}, fmt.Errorf("user already exists")
+	}
+	user := &entity.User{Name: name, Balance: request.Balance, Role: entity.RoleService}
+	if err := userRepository.CreateUser(user); err != nil {
+		return model.ServiceAccount{}, fmt.Errorf("failed to create user")
+	}
+	return model.ServiceAccount{Name: user.Name, Balance: user.Balance, APIKeys: []model.APIKey{}}, nil
+}
+
+func (a APIKeyService) GetServiceAccount(name string) (model.ServiceAccount, error) {
+	user, err := a.findServiceAccount(a.uow, name)
+	if err != nil {
+		return model.ServiceAccount{}, err
+	}
+	if user == nil {
+		return model.ServiceAccount{}, fmt.Errorf("service account not found")
+	}
+	keys, err := a.uow.APIKeyRepository().GetAPIKeys(user.ID)
+	if err != nil {
+		return model.ServiceAccount{}, fmt.Errorf("failed to get api keys")
+	}
+	account := model.ServiceAccount{Name: user.Name, Balance: user.Balance, APIKeys: make([]model.APIKey, 0, len(keys))}
+	for _, v := range keys {
+		account.APIKeys = append(account.APIKeys, toAPIKeyModel(v))
+	}
+	return account, nil
+}
+
+// FundServiceAccount adds amount to the budget of the service account.
+func (a APIKeyService) FundServiceAccount(name string, amount uint) error {
+	tx, err := a.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction")
+	}
+	user, err := a.findServiceAccount(tx, name)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if user == nil {
+		tx.Rollback()
+		return fmt.Errorf("service account not found")
+	}
+	user.Balance += amount
+	if err := tx.UserRepository().UpdateUser(user); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to update user")
+	}
+	tx.Commit()
+	return nil
+}
+
+// CreateAPIKey issues a key for the service account. The key is only returned here; just its hash
+// is stored.
+func (a APIKeyService) CreateAPIKey(accountName string, request model.APIKeyRequest) (model.NewAPIKey, error) {
+	if len(request.Scopes) == 0 {
+		return model.NewAPIKey{}, fmt.Errorf("at least one scope is required")
+	}
+	for _, scope := range request.Scopes {
+		if !slices.Contains(entity.APIKeyScopes, scope) {
+			return model.NewAPIKey{}, fmt.Errorf("unknown scope %s", scope)
+		}
+	}
+	user, err := a.findServiceAccount(a.uow, accountName)
+	if err != nil {
+		return model.NewAPIKey{}, err
+	}
+	if user == nil {
+		return model.NewAPIKey{}, fmt.Errorf("service account not found")
+	}
+	scopes := slices.Clone(request.Scopes)
+	slices.Sort(scopes)
+	key := &entity.APIKey{UserID: user.ID, Name: request.Name, Scopes: strings.Join(slices.Compact(scopes), ",")}
+	secret, err := a.createKey(a.uow, key)
+	if err != nil {
+		return model.NewAPIKey{}, err
+	}
+	return model.NewAPIKey{APIKey: toAPIKeyModel(*key), Key: secret}, nil
+}
+
+// RotateAPIKey issues a replacement with the same name and scopes. The old key keeps working for
+// the rotation grace period so that integrations can switch without downtime.
+func (a APIKeyService) RotateAPIKey(id uint) (model.NewAPIKey, error) {
+	tx, err := a.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return model.NewAPIKey{}, fmt.Errorf("failed to begin transaction")
+	}
+	apiKeyRepository := tx.APIKeyRepository()
+	old, err := apiKeyRepository.FindAPIKeyById(id)
+	if err != nil {
+		tx.Rollback()
+		return model.NewAPIKey{}, fmt.Errorf("failed to find api key")
+	}
+	now := a.now()
+	if old == nil || !apiKeyActive(old, now) {
+		tx.Rollback()
+		return model.NewAPIKey{}, fmt.Errorf("api key not found")
+	}
+	expiresAt := now.Add(a.rotationGrace)
+	if old.ExpiresAt == nil || expiresAt.Before(*old.ExpiresAt) {
+		old.ExpiresAt = &expiresAt
+	}
+	if err := apiKeyRepository.UpdateAPIKey(old); err != nil {
+		tx.Rollback()
+		return model.NewAPIKey{}, fmt.Errorf("failed to update api key")
+	}
+	key := &entity.APIKey{UserID: old.UserID, Name: old.Name, Scopes: old.Scopes}
+	secret, err := a.createKey(tx, key)
+	if err != nil {
+		tx.Rollback()
+		return model.NewAPIKey{}, err
+	}
+	tx.Commit()
+	return model.NewAPIKey{APIKey: toAPIKeyModel(*key), Key: secret}, nil
+}
+
+func (a APIKeyService) RevokeAPIKey(id uint) error {
+	apiKeyRepository := a.uow.APIKeyRepository()
+	key, err := apiKeyRepository.FindAPIKeyById(id)
+	if err != nil {
+		return fmt.Errorf("failed to find api key")
+	}
+	if key == nil || key.RevokedAt != nil {
+		return fmt.Errorf("api key not found")
+	}
+	now := a.now()
+	key.RevokedAt = &now
+	if err := apiKeyRepository.UpdateAPIKey(key); err != nil {
+		return fmt.Errorf("failed to update api key")
+	}
+	return nil
+}
+
+// AuthenticateAPIKey returns the claims of the service account the key belongs to, and records the
+// use of the key.
+func (a APIKeyService) AuthenticateAPIKey(secret string) (provider.UserClaims, error) {
+	apiKeyRepository := a.uow.APIKeyRepository()
+	key, err := apiKeyRepository.FindAPIKeyByHash(hashToken(secret))
+	if err != nil {
+		return provider.UserClaims{}, fmt.Errorf("failed to find api key")
+	}
+	now := a.now()
+	if key == nil || !apiKeyActive(key, now) {
+		return provider.UserClaims{}, fmt.Errorf("api key is invalid")
+	}
+	user, err := a.uow.UserRepository().FindUserById(key.UserID)
+	if err != nil {
+		return provider.UserClaims{}, fmt.Errorf("failed to find user")
+	}
+	if user == nil || user.Role != entity.RoleService {
+		return provider.UserClaims{}, fmt.Errorf("api key is invalid")
+	}
+	if err := apiKeyRepository.TouchAPIKey(key.ID, now, now.Add(-apiKeyTouchInterval)); err != nil {
+		return provider.UserClaims{}, fmt.Errorf("failed to update api key")
+	}
+	return provider.UserClaims{
+		UserId:         user.ID,
+		Role:           user.Role,
+		SessionVersion: user.SessionVersion,
+		APIKeyID:       key.ID,
+		Scopes:         strings.Split(key.Scopes, ","),
+	}, nil
+}
+
+func (a APIKeyService) findServiceAccount(uow repository.UnitOfWork, name string) (*entity.User, error) {
+	user, err := uow.UserRepository().FindUserByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user")
+	}
+	if user == nil || user.Role != entity.RoleService {
+		return nil, nil
+	}
+	return user, nil
+}
+
+// createKey generates the secret of key and stores key.
+func (a APIKeyService) createKey(uow repository.UnitOfWork, key *entity.APIKey) (string, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate api key")
+	}
+	secret := apiKeyPrefix + token
+	key.Prefix = secret[:len(apiKeyPrefix)+8]
+	key.KeyHash = hashToken(secret)
+	key.CreatedAt = a.now()
+	if err := uow.APIKeyRepository().CreateAPIKey(key); err != nil {
+		return "", fmt.Errorf("failed to create api key")
+	}
+	return secret, nil
+}
+
+func apiKeyActive(key *entity.APIKey, now time.Time) bool {
+	return key.RevokedAt == nil && (key.ExpiresAt == nil || now.Before(*key.ExpiresAt))
+}
+
+func toAPIKeyModel(key entity.APIKey) model.APIKey {
+	return model.APIKey{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     strings.Split(key.Scopes, ","),
+		CreatedAt:  key.CreatedAt,
+		LastUsedAt: key.LastUsedAt,
+		ExpiresAt:  key.ExpiresAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"merch_shop/internal/model"
+	"strings"
+	"testing"
+	"time"
+)
+
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) CreateAPIKey(key *entity.APIKey) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) UpdateAPIKey(key *entity.APIKey) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) FindAPIKeyById(id uint) (*entity.APIKey, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) FindAPIKeyByHash(keyHash string) (*entity.APIKey, error) {
+	args := m.Called(keyHash)
+	return args.Get(0).(*entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetAPIKeys(userId uint) ([]entity.APIKey, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) TouchAPIKey(id uint, now time.Time, before time.Time) error {
+	args := m.Called(id, now, before)
+	return args.Error(0)
+}
+
+func TestAPIKeyService_CreateAPIKey(t *testing.T) {
+	account := &entity.User{Model: gorm.Model{ID: 5}, Name: "hr-bot", Role: entity.RoleService}
+	userRepo := &MockUserRepository{}
+	userRepo.On("FindUserByName", "hr-bot").Return(account, nil)
+	userRepo.On("FindUserByName", "alice").Return(&entity.User{Model: gorm.Model{ID: 1}, Name: "alice", Role: entity.RoleUser}, nil)
+	apiKeyRepo := &MockAPIKeyRepository{}
+	apiKeyRepo.On("CreateAPIKey", mock.AnythingOfType("*entity.APIKey")).Return(nil)
+
+	service := NewAPIKeyService(&MockUnitOfWork{transactionUnitOfWork: &MockTransactionUnitOfWork{UserRepo: userRepo, APIKeyRepo: apiKeyRepo}}, time.Hour)
+
+	t.Run("Success", func(t *testing.T) {
+		key, err := service.CreateAPIKey("hr-bot", model.APIKeyRequest{Name: "payroll", Scopes: []string{entity.ScopeCoinsGrant, entity.ScopeAccountRead}})
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(key.Key, "msk_"))
+		assert.True(t, strings.HasPrefix(key.Key, key.Prefix))
+		assert.Equal(t, []string{entity.ScopeAccountRead, entity.ScopeCoinsGrant}, key.Scopes)
+
+		stored := apiKeyRepo.Calls[len(apiKeyRepo.Calls)-1].Arguments.Get(0).(*entity.APIKey)
+		assert.Equal(t, uint(5), stored.UserID)
+		assert.Equal(t, hashToken(key.Key), stored.KeyHash)
+	})
+
+	t.Run("UnknownScope", func(t *testing.T) {
+		_, err := service.CreateAPIKey("hr-bot", model.APIKeyRequest{Name: "payroll", Scopes: []string{"coins:mint"}})
+		assert.EqualError(t, err, "unknown scope coins:mint")
+	})
+
+	t.Run("NotAServiceAccount", func(t *testing.T) {
+		_, err := service.CreateAPIKey("alice", model.APIKeyRequest{Name: "payroll", Scopes: []string{entity.ScopeCoinsGrant}})
+		assert.EqualError(t, err, "service account not found")
+	})
+}
+
+func TestAPIKeyService_AuthenticateAPIKey(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	expired := now.Add(-time.Second)
+	account := &entity.User{Model: gorm.Model{ID: 5}, Name: "hr-bot", Role: entity.RoleService}
+	userRepo := &MockUserRepository{}
+	userRepo.On("FindUserById", uint(5)).Return(account, nil)
+	apiKeyRepo := &MockAPIKeyRepository{}
+	apiKeyRepo.On("FindAPIKeyByHash", hashToken("msk_valid")).
+		Return(&entity.APIKey{ID: 1, UserID: 5, Scopes: "catalog:read,coins:grant"}, nil)
+	apiKeyRepo.On("FindAPIKeyByHash", hashToken("msk_rotated")).
+		Return(&entity.APIKey{ID: 2, UserID: 5, Scopes: "coins:grant", ExpiresAt: &expired}, nil)
+	apiKeyRepo.On("FindAPIKeyByHash", hashToken("msk_revoked")).
+		Return(&entity.APIKey{ID: 3, UserID: 5, Scopes: "coins:grant", RevokedAt: &expired}, nil)
+	apiKeyRepo.On("TouchAPIKey", uint(1), now, now.Add(-time.Minute)).Return(nil)
+
+	service := NewAPIKeyService(&MockUnitOfWork{transactionUnitOfWork: &MockTransactionUnitOfWork{UserRepo: userRepo, APIKeyRepo: apiKeyRepo}}, time.Hour)
+	service.now = func() time.Time { return now }
+
+	claims, err := service.AuthenticateAPIKey("msk_valid")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(5), claims.UserId)
+	assert.Equal(t, entity.RoleService, claims.Role)
+	assert.Equal(t, uint(1), claims.APIKeyID)
+	assert.Equal(t, []string{entity.ScopeCatalogRead, entity.ScopeCoinsGrant}, claims.Scopes)
+	apiKeyRepo.AssertCalled(t, "TouchAPIKey", uint(1), now, now.Add(-time.Minute))
+
+	for _, key := range []string{"msk_rotated", "msk_revoked"} {
+		_, err = service.AuthenticateAPIKey(key)
+		assert.EqualError(t, err, "api key is invalid", key)
+	}
+}
+
+func TestAPIKeyService_RotateAPIKey(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	old := &entity.APIKey{ID: 1, UserID: 5, Name: "payroll", Scopes: "coins:grant"}
+	apiKeyRepo := &MockAPIKeyRepository{}
+	apiKeyRepo.On("FindAPIKeyById", uint(1)).Return(old, nil)
+	apiKeyRepo.On("UpdateAPIKey", old).Return(nil)
+	apiKeyRepo.On("CreateAPIKey", mock.AnythingOfType("*entity.APIKey")).Return(nil)
+	tx := &MockTransactionUnitOfWork{APIKeyRepo: apiKeyRepo}
+
+	service := NewAPIKeyService(&MockUnitOfWork{transactionUnitOfWork: tx}, 24*time.Hour)
+	service.now = func() time.Time { return now }
+
+	key, err := service.RotateAPIKey(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "payroll", key.Name)
+	assert.Equal(t, []string{entity.ScopeCoinsGrant}, key.Scopes)
+	assert.Equal(t, now.Add(24*time.Hour), *old.ExpiresAt)
+	assert.True(t, tx.commitCalled)
+}
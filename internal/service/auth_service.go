@@ -1,62 +1,635 @@
 package service
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"golang.org/x/crypto/bcrypt"
+	"merch_shop/internal/config"
 	"merch_shop/internal/entity"
+	"merch_shop/internal/model"
 	"merch_shop/internal/provider"
 	"merch_shop/internal/repository"
+	"strings"
+	"time"
 )
 
 const START_BALANCE = 1000
 
+const recoveryCodeCount = 10
+
+// errPasswordsNotManaged is returned for password changes when passwords are checked by an external store.
+var errPasswordsNotManaged = fmt.Errorf("passwords are managed by the directory")
+
+// errServiceAccountPassword is returned for password changes of service accounts, which sign in with API keys.
+var errServiceAccountPassword = fmt.Errorf("service accounts have no password")
+
+// TooManyAttemptsError is returned while a username or client IP is backed off or locked out.
+type TooManyAttemptsError struct {
+	RetryAfter time.Duration
+}
+
+func (e *TooManyAttemptsError) Error() string {
+	return fmt.Sprintf("too many failed attempts, try again in %s", e.RetryAfter.Round(time.Second))
+}
+
 type AuthService struct {
-	jwtAuth *provider.JWTAuth
-	uow     repository.UnitOfWork
+	jwtAuth       *provider.JWTAuth
+	uow           repository.UnitOfWork
+	authenticator Authenticator
+	passwords     PasswordPolicy
+	cfg           config.Auth
+	now           func() time.Time
 }
 
-func NewAuthService(jwtAuth *provider.JWTAuth, uow repository.UnitOfWork) AuthService {
-	return AuthService{jwtAuth: jwtAuth, uow: uow}
+func NewAuthService(jwtAuth *provider.JWTAuth, uow repository.UnitOfWork, authenticator Authenticator, passwords PasswordPolicy, cfg config.Auth) AuthService {
+	return AuthService{jwtAuth: jwtAuth, uow: uow, authenticator: authenticator, passwords: passwords, cfg: cfg, now: time.Now}
 }
 
-func (auth AuthService) Authenticate(username, password string) (string, error) {
-	userRepository := auth.uow.UserRepository()
+// Authenticate signs the user in with the password checked by the authenticator. Failed sign-ins are
+// counted per username and per client IP; see checkThrottle for when further attempts are refused.
+// Users with two-factor authentication get a challenge token for VerifyTwoFactor instead of a token.
+func (auth AuthService) Authenticate(username, password, ip string) (model.AuthResponse, error) {
+	if err := auth.reserveAttempt(username, ip, auth.now()); err != nil {
+		return model.AuthResponse{}, err
+	}
+	user, err := auth.authenticator.Authenticate(username, password)
+	if errors.Is(err, ErrInvalidCredentials) {
+		return model.AuthResponse{}, err
+	}
+	if err := auth.releaseAttempt(username, ip); err != nil {
+		return model.AuthResponse{}, err
+	}
+	if err != nil {
+		return model.AuthResponse{}, err
+	}
+	return auth.signIn(user)
+}
 
-	user, err := userRepository.FindUserByName(username)
+// signIn issues a token for a user whose first factor was checked, or a challenge token when the
+// user has two-factor authentication.
+func (auth AuthService) signIn(user *entity.User) (model.AuthResponse, error) {
+	if user.TOTPEnabled {
+		// Failed attempts are only reset once the second factor is verified too, so that
+		// authenticator codes cannot be guessed without limit.
+		challengeToken, err := auth.jwtAuth.GenerateChallengeToken(user.ID, user.SessionVersion, auth.cfg.TwoFactorChallengeTTL)
+		if err != nil {
+			return model.AuthResponse{}, fmt.Errorf("failed to generate token")
+		}
+		return model.AuthResponse{TwoFactorRequired: true, ChallengeToken: challengeToken}, nil
+	}
+	if err := auth.resetFailures(user.Name); err != nil {
+		return model.AuthResponse{}, err
+	}
+
+	token, err := auth.generateToken(user)
 	if err != nil {
-		return "", fmt.Errorf("failed to find user by username %s", username)
+		return model.AuthResponse{}, err
 	}
-	if user == nil {
-		passwordHash, err := hashPassword(password)
+	return model.AuthResponse{Token: token}, nil
+}
+
+// VerifyTwoFactor completes the sign-in started by Authenticate with an authenticator code or an
+// unused recovery code. Wrong codes count as failed sign-ins.
+func (auth AuthService) VerifyTwoFactor(challengeToken string, code string, ip string) (string, error) {
+	claims, err := auth.jwtAuth.VerifyChallengeToken(challengeToken)
+	if err != nil {
+		return "", fmt.Errorf("challenge token is invalid or expired")
+	}
+	user, err := auth.uow.UserRepository().FindUserById(claims.UserId)
+	if err != nil {
+		return "", fmt.Errorf("failed to find user")
+	}
+	if user == nil || !user.TOTPEnabled || user.SessionVersion != claims.SessionVersion {
+		return "", fmt.Errorf("challenge token is invalid or expired")
+	}
+	now := auth.now()
+	if err := auth.reserveAttempt(user.Name, ip, now); err != nil {
+		return "", err
+	}
+	verified, err := auth.verifySecondFactor(user, code, now)
+	if err == nil && !verified {
+		return "", fmt.Errorf("code is incorrect")
+	}
+	if err := auth.releaseAttempt(user.Name, ip); err != nil {
+		return "", err
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := auth.resetFailures(user.Name); err != nil {
+		return "", err
+	}
+	return auth.generateToken(user)
+}
+
+// GetLockouts lists the usernames and client IPs that are currently locked out.
+func (auth AuthService) GetLockouts() ([]model.Lockout, error) {
+	minFailures := auth.maxFailures(entity.ThrottleUser)
+	if ipFailures := auth.maxFailures(entity.ThrottleIP); minFailures == 0 || (ipFailures > 0 && ipFailures < minFailures) {
+		minFailures = ipFailures
+	}
+	if minFailures == 0 {
+		return []model.Lockout{}, nil
+	}
+	now := auth.now()
+	throttles, err := auth.uow.LoginThrottleRepository().GetThrottles(minFailures, now.Add(-auth.cfg.LockoutDuration))
+	if err != nil {
+		return nil, fmt.Errorf("error getting lockouts")
+	}
+	lockouts := make([]model.Lockout, 0, len(throttles))
+	for _, v := range throttles {
+		max := auth.maxFailures(v.Kind)
+		if max == 0 || v.Failures < max {
+			continue
+		}
+		lockouts = append(lockouts, model.Lockout{
+			Kind:        v.Kind,
+			Value:       v.Value,
+			Failures:    v.Failures,
+			LockedUntil: v.LastFailureAt.Add(auth.cfg.LockoutDuration),
+		})
+	}
+	return lockouts, nil
+}
+
+// ClearLockout forgets the failed sign-ins of a username or client IP.
+func (auth AuthService) ClearLockout(kind string, value string) error {
+	if kind != entity.ThrottleUser && kind != entity.ThrottleIP {
+		return fmt.Errorf("unknown lockout kind")
+	}
+	deleted, err := auth.uow.LoginThrottleRepository().DeleteThrottle(kind, value)
+	if err != nil {
+		return fmt.Errorf("failed to clear lockout")
+	}
+	if !deleted {
+		return fmt.Errorf("lockout not found")
+	}
+	return nil
+}
+
+// checkThrottle refuses the attempt while the username or IP is locked out after reaching its failure
+// limit. A username is also backed off after each failure, for LoginBackoff doubled with every
+// further failure. It returns the failures counted so far; they are forgotten once the last one is
+// older than LockoutDuration.
+func (auth AuthService) checkThrottle(kind string, value string, now time.Time) (uint, error) {
+	if auth.maxFailures(kind) == 0 {
+		return 0, nil
+	}
+	throttle, err := auth.uow.LoginThrottleRepository().FindThrottle(kind, value)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check failed attempts")
+	}
+	if throttle == nil || throttle.LastFailureAt.Before(now.Add(-auth.cfg.LockoutDuration)) {
+		return 0, nil
+	}
+	if retryAfter := throttle.LastFailureAt.Add(auth.throttleWait(kind, throttle.Failures)).Sub(now); retryAfter > 0 {
+		return 0, &TooManyAttemptsError{RetryAfter: retryAfter}
+	}
+	return throttle.Failures, nil
+}
+
+// throttleWait is how long further attempts are refused after the last of the given failures.
+func (auth AuthService) throttleWait(kind string, failures uint) time.Duration {
+	switch {
+	case failures == 0:
+		return 0
+	case failures >= auth.maxFailures(kind):
+		return auth.cfg.LockoutDuration
+	case kind == entity.ThrottleUser:
+		if failures <= 30 {
+			return min(auth.cfg.LoginBackoff<<(failures-1), auth.cfg.LockoutDuration)
+		}
+		return auth.cfg.LockoutDuration
+	}
+	return 0
+}
+
+// reserveAttempt checks the throttle and counts the attempt as failed before the credentials are
+// verified, so that concurrent attempts cannot all slip through one check. An attempt that finds
+// failures counted by others since its check is refused as if they had come first. Callers release
+// the attempt with releaseAttempt unless the credentials turn out to be wrong.
+func (auth AuthService) reserveAttempt(username string, ip string, now time.Time) error {
+	kinds := []string{entity.ThrottleUser, entity.ThrottleIP}
+	values := map[string]string{entity.ThrottleUser: username, entity.ThrottleIP: ip}
+	checked := make(map[string]uint, len(kinds))
+	for _, kind := range kinds {
+		failures, err := auth.checkThrottle(kind, values[kind], now)
+		if err != nil {
+			return err
+		}
+		checked[kind] = failures
+	}
+
+	throttleRepository := auth.uow.LoginThrottleRepository()
+	staleBefore := now.Add(-auth.cfg.LockoutDuration)
+	reserved := make(map[string]string, len(kinds))
+	for _, kind := range kinds {
+		if auth.maxFailures(kind) == 0 {
+			continue
+		}
+		throttle, err := throttleRepository.RecordFailure(kind, values[kind], now, staleBefore)
 		if err != nil {
-			return "", fmt.Errorf("failed to hash password")
+			auth.releaseFailures(reserved)
+			return fmt.Errorf("failed to record attempt")
+		}
+		reserved[kind] = values[kind]
+		if throttle.Failures <= checked[kind]+1 {
+			continue
 		}
-		user = &entity.User{
-			Name:         username,
-			PasswordHash: passwordHash,
-			Balance:      START_BALANCE,
+		if wait := auth.throttleWait(kind, throttle.Failures-1); wait > 0 {
+			if err := auth.releaseFailures(reserved); err != nil {
+				return err
+			}
+			return &TooManyAttemptsError{RetryAfter: wait}
 		}
+	}
+	return nil
+}
 
-		if err := userRepository.CreateUser(user); err != nil {
-			return "", fmt.Errorf("failed to create user")
+// releaseAttempt takes back an attempt reserved by reserveAttempt that did not fail.
+func (auth AuthService) releaseAttempt(username string, ip string) error {
+	return auth.releaseFailures(map[string]string{entity.ThrottleUser: username, entity.ThrottleIP: ip})
+}
+
+func (auth AuthService) releaseFailures(values map[string]string) error {
+	throttleRepository := auth.uow.LoginThrottleRepository()
+	for kind, value := range values {
+		if auth.maxFailures(kind) == 0 {
+			continue
+		}
+		if err := throttleRepository.ReleaseFailure(kind, value); err != nil {
+			return fmt.Errorf("failed to release attempt")
 		}
-	} else if !verifyPassword(password, user.PasswordHash) {
+	}
+	return nil
+}
+
+func (auth AuthService) resetFailures(username string) error {
+	if auth.maxFailures(entity.ThrottleUser) == 0 {
+		return nil
+	}
+	if _, err := auth.uow.LoginThrottleRepository().DeleteThrottle(entity.ThrottleUser, username); err != nil {
+		return fmt.Errorf("failed to reset failed attempts")
+	}
+	return nil
+}
+
+func (auth AuthService) maxFailures(kind string) uint {
+	if kind == entity.ThrottleIP {
+		return auth.cfg.MaxFailedLoginsPerIP
+	}
+	return auth.cfg.MaxFailedLogins
+}
+
+// ValidateSession reports an error when tokens of the given session version are no longer accepted.
+func (auth AuthService) ValidateSession(userId uint, sessionVersion uint) error {
+	user, err := auth.uow.UserRepository().FindUserById(userId)
+	if err != nil {
+		return fmt.Errorf("failed to find user")
+	}
+	if user == nil {
+		return fmt.Errorf("user not found")
+	}
+	if user.SessionVersion != sessionVersion {
+		return fmt.Errorf("session expired")
+	}
+	return nil
+}
+
+// ChangePassword replaces the user's password and signs out all existing sessions.
+// It returns a token for the new session. Wrong old passwords count towards the sign-in throttle.
+func (auth AuthService) ChangePassword(userId uint, oldPassword string, newPassword string, ip string) (string, error) {
+	userRepository := auth.uow.UserRepository()
+	user, err := userRepository.FindUserById(userId)
+	if err != nil {
+		return "", fmt.Errorf("failed to find user")
+	}
+	if user == nil {
+		return "", fmt.Errorf("user not found")
+	}
+	if !auth.authenticator.ManagesPasswords() {
+		return "", errPasswordsNotManaged
+	}
+	if user.Role == entity.RoleService {
+		return "", errServiceAccountPassword
+	}
+	if err := auth.reserveAttempt(user.Name, ip, auth.now()); err != nil {
+		return "", err
+	}
+	if !verifyPassword(oldPassword, user.PasswordHash) {
 		return "", fmt.Errorf("password is incorrect")
 	}
+	if err := auth.releaseAttempt(user.Name, ip); err != nil {
+		return "", err
+	}
+	if err := auth.setPassword(user, newPassword); err != nil {
+		return "", err
+	}
+	return auth.generateToken(user)
+}
 
-	token, err := auth.jwtAuth.GenerateToken(user.ID)
+// IssueResetToken creates a one-time password reset token for the user, replacing any earlier one.
+// Only the token's hash is stored.
+func (auth AuthService) IssueResetToken(userName string) (model.PasswordResetToken, error) {
+	if !auth.authenticator.ManagesPasswords() {
+		return model.PasswordResetToken{}, errPasswordsNotManaged
+	}
+	userRepository := auth.uow.UserRepository()
+	user, err := userRepository.FindUserByName(userName)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate token")
+		return model.PasswordResetToken{}, fmt.Errorf("failed to find user")
 	}
-	return token, nil
+	if user == nil {
+		return model.PasswordResetToken{}, fmt.Errorf("user not found")
+	}
+	if user.Role == entity.RoleService {
+		return model.PasswordResetToken{}, errServiceAccountPassword
+	}
+	token, err := randomToken()
+	if err != nil {
+		return model.PasswordResetToken{}, fmt.Errorf("failed to generate reset token")
+	}
+	expiresAt := auth.now().Add(auth.cfg.PasswordResetTTL)
+	user.ResetTokenHash = hashToken(token)
+	user.ResetTokenExpiresAt = &expiresAt
+	if err := userRepository.UpdateUserColumns(user, "reset_token_hash", "reset_token_expires_at"); err != nil {
+		return model.PasswordResetToken{}, fmt.Errorf("failed to update user")
+	}
+	return model.PasswordResetToken{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// ResetPassword sets a new password using a reset token, signs out all existing sessions and
+// returns a token for the new session. The reset token cannot be used again.
+func (auth AuthService) ResetPassword(token string, newPassword string) (string, error) {
+	if !auth.authenticator.ManagesPasswords() {
+		return "", errPasswordsNotManaged
+	}
+	userRepository := auth.uow.UserRepository()
+	user, err := userRepository.FindUserByResetToken(hashToken(token))
+	if err != nil {
+		return "", fmt.Errorf("failed to find user")
+	}
+	if user == nil || user.ResetTokenExpiresAt == nil || !auth.now().Before(*user.ResetTokenExpiresAt) {
+		return "", fmt.Errorf("reset token is invalid or expired")
+	}
+	user.ResetTokenHash = ""
+	user.ResetTokenExpiresAt = nil
+	if err := auth.setPassword(user, newPassword); err != nil {
+		return "", err
+	}
+	return auth.generateToken(user)
+}
+
+// GetTwoFactorStatus reports whether the user signs in with a second factor and how many recovery
+// codes are left.
+func (auth AuthService) GetTwoFactorStatus(userId uint) (model.TwoFactorStatus, error) {
+	user, err := auth.findUser(userId)
+	if err != nil {
+		return model.TwoFactorStatus{}, err
+	}
+	if !user.TOTPEnabled {
+		return model.TwoFactorStatus{}, nil
+	}
+	count, err := auth.uow.RecoveryCodeRepository().CountUnusedRecoveryCodes(userId)
+	if err != nil {
+		return model.TwoFactorStatus{}, fmt.Errorf("failed to count recovery codes")
+	}
+	return model.TwoFactorStatus{Enabled: true, RecoveryCodesLeft: count}, nil
+}
+
+// BeginTwoFactorEnrolment generates a new authenticator secret for the user. It takes effect once
+// a code generated from it is confirmed with ConfirmTwoFactor.
+func (auth AuthService) BeginTwoFactorEnrolment(userId uint) (model.TwoFactorEnrolment, error) {
+	user, err := auth.findUser(userId)
+	if err != nil {
+		return model.TwoFactorEnrolment{}, err
+	}
+	if user.TOTPEnabled {
+		return model.TwoFactorEnrolment{}, fmt.Errorf("two-factor authentication is already enabled")
+	}
+	secret, err := provider.GenerateTOTPSecret()
+	if err != nil {
+		return model.TwoFactorEnrolment{}, fmt.Errorf("failed to generate secret")
+	}
+	user.TOTPSecret = secret
+	if err := auth.uow.UserRepository().UpdateUserColumns(user, "totp_secret"); err != nil {
+		return model.TwoFactorEnrolment{}, fmt.Errorf("failed to update user")
+	}
+	return model.TwoFactorEnrolment{
+		Secret:          secret,
+		ProvisioningURI: provider.TOTPProvisioningURI(auth.cfg.TOTPIssuer, user.Name, secret),
+	}, nil
+}
+
+// ConfirmTwoFactor enables two-factor authentication once the user proves their authenticator
+// works, and returns the recovery codes. They are shown only this once.
+func (auth AuthService) ConfirmTwoFactor(userId uint, code string) (model.RecoveryCodes, error) {
+	user, err := auth.findUser(userId)
+	if err != nil {
+		return model.RecoveryCodes{}, err
+	}
+	if user.TOTPEnabled {
+		return model.RecoveryCodes{}, fmt.Errorf("two-factor authentication is already enabled")
+	}
+	if user.TOTPSecret == "" {
+		return model.RecoveryCodes{}, fmt.Errorf("two-factor enrolment was not started")
+	}
+	step, ok := provider.ValidateTOTP(user.TOTPSecret, code, auth.now())
+	if !ok {
+		return model.RecoveryCodes{}, fmt.Errorf("code is incorrect")
+	}
+	user.TOTPEnabled = true
+	user.TOTPLastStep = step
+	return auth.saveTwoFactor(user, recoveryCodeCount)
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes of the user, used or not. It requires an
+// authenticator code.
+func (auth AuthService) RegenerateRecoveryCodes(userId uint, code string) (model.RecoveryCodes, error) {
+	user, err := auth.findUser(userId)
+	if err != nil {
+		return model.RecoveryCodes{}, err
+	}
+	if !user.TOTPEnabled {
+		return model.RecoveryCodes{}, fmt.Errorf("two-factor authentication is not enabled")
+	}
+	step, ok := provider.ValidateTOTP(user.TOTPSecret, code, auth.now())
+	if !ok || step <= user.TOTPLastStep {
+		return model.RecoveryCodes{}, fmt.Errorf("code is incorrect")
+	}
+	user.TOTPLastStep = step
+	return auth.saveTwoFactor(user, recoveryCodeCount)
+}
+
+// DisableTwoFactor turns two-factor authentication off and deletes the recovery codes. It requires
+// the user's password and a code from the authenticator app or an unused recovery code, and wrong
+// ones count towards the same throttle as sign-ins.
+func (auth AuthService) DisableTwoFactor(userId uint, password string, code string, ip string) error {
+	user, err := auth.findUser(userId)
+	if err != nil {
+		return err
+	}
+	if !user.TOTPEnabled {
+		return fmt.Errorf("two-factor authentication is not enabled")
+	}
+	now := auth.now()
+	if err := auth.reserveAttempt(user.Name, ip, now); err != nil {
+		return err
+	}
+	verified, err := auth.authenticator.VerifyPassword(user, password)
+	if err == nil && !verified {
+		return fmt.Errorf("password is incorrect")
+	}
+	if err == nil {
+		verified, err = auth.verifySecondFactor(user, code, now)
+		if err == nil && !verified {
+			return fmt.Errorf("code is incorrect")
+		}
+	}
+	if err := auth.releaseAttempt(user.Name, ip); err != nil {
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	user.TOTPEnabled = false
+	user.TOTPSecret = ""
+	user.TOTPLastStep = 0
+	_, err = auth.saveTwoFactor(user, 0)
+	return err
 }
 
-func hashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	return string(bytes), err
+// verifySecondFactor accepts an authenticator code that was not used before, or marks a matching
+// unused recovery code as used.
+func (auth AuthService) verifySecondFactor(user *entity.User, code string, now time.Time) (bool, error) {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if step, ok := provider.ValidateTOTP(user.TOTPSecret, code, now); ok {
+		if step <= user.TOTPLastStep {
+			return false, nil
+		}
+		user.TOTPLastStep = step
+		if err := auth.uow.UserRepository().UpdateUserColumns(user, "totp_last_step"); err != nil {
+			return false, fmt.Errorf("failed to update user")
+		}
+		return true, nil
+	}
+	used, err := auth.uow.RecoveryCodeRepository().UseRecoveryCode(user.ID, hashToken(normalizeRecoveryCode(code)), now)
+	if err != nil {
+		return false, fmt.Errorf("failed to check recovery code")
+	}
+	return used, nil
+}
+
+// saveTwoFactor stores the user's two-factor settings and replaces their recovery codes with
+// count new ones, which it returns.
+func (auth AuthService) saveTwoFactor(user *entity.User, count int) (model.RecoveryCodes, error) {
+	codes := make([]string, 0, count)
+	recoveryCodes := make([]entity.RecoveryCode, 0, count)
+	for len(codes) < count {
+		code, err := randomRecoveryCode()
+		if err != nil {
+			return model.RecoveryCodes{}, fmt.Errorf("failed to generate recovery codes")
+		}
+		codes = append(codes, code)
+		recoveryCodes = append(recoveryCodes, entity.RecoveryCode{UserID: user.ID, CodeHash: hashToken(normalizeRecoveryCode(code))})
+	}
+
+	tx, err := auth.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return model.RecoveryCodes{}, fmt.Errorf("failed to begin transaction")
+	}
+	if err := tx.UserRepository().UpdateUserColumns(user, "totp_secret", "totp_enabled", "totp_last_step"); err != nil {
+		tx.Rollback()
+		return model.RecoveryCodes{}, fmt.Errorf("failed to update user")
+	}
+	if err := tx.RecoveryCodeRepository().DeleteRecoveryCodes(user.ID); err != nil {
+		tx.Rollback()
+		return model.RecoveryCodes{}, fmt.Errorf("failed to delete recovery codes")
+	}
+	if len(recoveryCodes) > 0 {
+		if err := tx.RecoveryCodeRepository().CreateRecoveryCodes(recoveryCodes); err != nil {
+			tx.Rollback()
+			return model.RecoveryCodes{}, fmt.Errorf("failed to save recovery codes")
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return model.RecoveryCodes{}, fmt.Errorf("failed to commit transaction")
+	}
+	return model.RecoveryCodes{Codes: codes}, nil
+}
+
+func (auth AuthService) findUser(userId uint) (*entity.User, error) {
+	user, err := auth.uow.UserRepository().FindUserById(userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user")
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+	return user, nil
+}
+
+// setPassword stores the new password hash and bumps the session version so that tokens issued
+// with the old password stop working. A pending reset token is written along with them.
+func (auth AuthService) setPassword(user *entity.User, password string) error {
+	if err := auth.passwords.Validate(password); err != nil {
+		return err
+	}
+	passwordHash, err := auth.passwords.Hash(password)
+	if err != nil {
+		return fmt.Errorf("failed to hash password")
+	}
+	user.PasswordHash = passwordHash
+	user.SessionVersion++
+	err = auth.uow.UserRepository().UpdateUserColumns(user, "password_hash", "session_version", "reset_token_hash", "reset_token_expires_at")
+	if err != nil {
+		return fmt.Errorf("failed to update user")
+	}
+	return nil
+}
+
+func (auth AuthService) generateToken(user *entity.User) (string, error) {
+	token, err := auth.jwtAuth.GenerateToken(user.ID, user.Role, user.SessionVersion)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token")
+	}
+	return token, nil
 }
 
 func verifyPassword(password, hash string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
 }
+
+// randomRecoveryCode returns 50 random bits as ten base32 characters, e.g. "k3m9q-2xw7p".
+func randomRecoveryCode() (string, error) {
+	bytes := make([]byte, 10)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	code := strings.ToLower(base32.StdEncoding.EncodeToString(bytes))[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(code, "-", ""))
+}
+
+func randomToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
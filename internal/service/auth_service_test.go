@@ -5,14 +5,25 @@ import (
 	"testing"
 	"time"
 
+	"merch_shop/internal/config"
 	"merch_shop/internal/entity"
 	"merch_shop/internal/provider"
 	"merch_shop/internal/repository"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
+var testPasswords = NewPasswordPolicy(8, []string{"password123"}, bcrypt.MinCost)
+
+var testAuthConfig = config.Auth{PasswordResetTTL: time.Hour}
+
+func newTestAuthService(jwtAuth *provider.JWTAuth, uow repository.UnitOfWork, cfg config.Auth) AuthService {
+	return NewAuthService(jwtAuth, uow, NewPasswordAuthenticator(uow, testPasswords), testPasswords, cfg)
+}
+
 type MockAuthUserRepository struct {
 	mock.Mock
 }
@@ -27,6 +38,11 @@ func (m *MockAuthUserRepository) UpdateUser(user *entity.User) error {
 	return args.Error(0)
 }
 
+func (m *MockAuthUserRepository) UpdateUserColumns(user *entity.User, columns ...string) error {
+	args := m.Called(user, columns)
+	return args.Error(0)
+}
+
 func (m *MockAuthUserRepository) FindUserByName(name string) (*entity.User, error) {
 	args := m.Called(name)
 	return args.Get(0).(*entity.User), args.Error(1)
@@ -37,8 +53,89 @@ func (m *MockAuthUserRepository) FindUserById(userId uint) (*entity.User, error)
 	return args.Get(0).(*entity.User), args.Error(1)
 }
 
+func (m *MockAuthUserRepository) FindUserByResetToken(tokenHash string) (*entity.User, error) {
+	args := m.Called(tokenHash)
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *MockAuthUserRepository) FindUserByOIDCSubject(subject string) (*entity.User, error) {
+	args := m.Called(subject)
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *MockAuthUserRepository) FindUserByEmail(email string) (*entity.User, error) {
+	args := m.Called(email)
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *MockAuthUserRepository) SearchUsersByPrefix(prefix string, limit int) ([]entity.User, error) {
+	args := m.Called(prefix, limit)
+	return args.Get(0).([]entity.User), args.Error(1)
+}
+
+func (m *MockAuthUserRepository) FindUsersByInitial(initial string, limit int) ([]entity.User, error) {
+	args := m.Called(initial, limit)
+	return args.Get(0).([]entity.User), args.Error(1)
+}
+
+type MockLoginThrottleRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoginThrottleRepository) FindThrottle(kind string, value string) (*entity.LoginThrottle, error) {
+	args := m.Called(kind, value)
+	return args.Get(0).(*entity.LoginThrottle), args.Error(1)
+}
+
+func (m *MockLoginThrottleRepository) RecordFailure(kind string, value string, now time.Time, staleBefore time.Time) (*entity.LoginThrottle, error) {
+	args := m.Called(kind, value, now, staleBefore)
+	return args.Get(0).(*entity.LoginThrottle), args.Error(1)
+}
+
+func (m *MockLoginThrottleRepository) ReleaseFailure(kind string, value string) error {
+	args := m.Called(kind, value)
+	return args.Error(0)
+}
+
+func (m *MockLoginThrottleRepository) DeleteThrottle(kind string, value string) (bool, error) {
+	args := m.Called(kind, value)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockLoginThrottleRepository) GetThrottles(minFailures uint, since time.Time) ([]entity.LoginThrottle, error) {
+	args := m.Called(minFailures, since)
+	return args.Get(0).([]entity.LoginThrottle), args.Error(1)
+}
+
+type MockRecoveryCodeRepository struct {
+	mock.Mock
+}
+
+func (m *MockRecoveryCodeRepository) CreateRecoveryCodes(codes []entity.RecoveryCode) error {
+	args := m.Called(codes)
+	return args.Error(0)
+}
+
+func (m *MockRecoveryCodeRepository) DeleteRecoveryCodes(userId uint) error {
+	args := m.Called(userId)
+	return args.Error(0)
+}
+
+func (m *MockRecoveryCodeRepository) UseRecoveryCode(userId uint, codeHash string, now time.Time) (bool, error) {
+	args := m.Called(userId, codeHash, now)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRecoveryCodeRepository) CountUnusedRecoveryCodes(userId uint) (int64, error) {
+	args := m.Called(userId)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 type MockAuthUnitOfWork struct {
-	userRepo *MockAuthUserRepository
+	userRepo         *MockAuthUserRepository
+	throttleRepo     *MockLoginThrottleRepository
+	recoveryCodeRepo *MockRecoveryCodeRepository
+	oidcLoginRepo    *MockOIDCLoginRepository
 }
 
 func (m *MockAuthUnitOfWork) BeginTransaction(opts ...*sql.TxOptions) (repository.TransactionUnitOfWork, error) {
@@ -53,6 +150,66 @@ func (m *MockAuthUnitOfWork) TransactionRepository() repository.TransactionRepos
 	panic("not implemented")
 }
 
+func (m *MockAuthUnitOfWork) ItemRepository() repository.ItemRepository {
+	panic("not implemented")
+}
+
+func (m *MockAuthUnitOfWork) PaymentRequestRepository() repository.PaymentRequestRepository {
+	panic("not implemented")
+}
+
+func (m *MockAuthUnitOfWork) PurchaseRepository() repository.PurchaseRepository {
+	panic("not implemented")
+}
+
+func (m *MockAuthUnitOfWork) PromoCodeRepository() repository.PromoCodeRepository {
+	panic("not implemented")
+}
+
+func (m *MockAuthUnitOfWork) WishlistRepository() repository.WishlistRepository {
+	panic("not implemented")
+}
+
+func (m *MockAuthUnitOfWork) NotificationRepository() repository.NotificationRepository {
+	panic("not implemented")
+}
+
+func (m *MockAuthUnitOfWork) GroupPurchaseRepository() repository.GroupPurchaseRepository {
+	panic("not implemented")
+}
+
+func (m *MockAuthUnitOfWork) WalletRepository() repository.WalletRepository {
+	panic("not implemented")
+}
+
+func (m *MockAuthUnitOfWork) TeamRepository() repository.TeamRepository {
+	panic("not implemented")
+}
+
+func (m *MockAuthUnitOfWork) LeaderboardRepository() repository.LeaderboardRepository {
+	panic("not implemented")
+}
+
+func (m *MockAuthUnitOfWork) AchievementRepository() repository.AchievementRepository {
+	panic("not implemented")
+}
+
+func (m *MockAuthUnitOfWork) LoginThrottleRepository() repository.LoginThrottleRepository {
+	return m.throttleRepo
+}
+
+func (m *MockAuthUnitOfWork) RecoveryCodeRepository() repository.RecoveryCodeRepository {
+	return m.recoveryCodeRepo
+}
+
+func (m *MockAuthUnitOfWork) APIKeyRepository() repository.APIKeyRepository {
+	panic("not implemented")
+}
+
+func (m *MockAuthUnitOfWork) OIDCLoginRepository() repository.OIDCLoginRepository {
+	return m.oidcLoginRepo
+}
+
 func TestAuthService_Authenticate(t *testing.T) {
 	t.Run("NewUser", func(t *testing.T) {
 		userRepo := &MockAuthUserRepository{}
@@ -61,16 +218,16 @@ func TestAuthService_Authenticate(t *testing.T) {
 
 		uow := &MockAuthUnitOfWork{userRepo: userRepo}
 		jwtAuth := provider.NewJWTAuth([]byte("test_secret"), time.Hour)
-		service := NewAuthService(jwtAuth, uow)
+		service := newTestAuthService(jwtAuth, uow, testAuthConfig)
 
-		token, err := service.Authenticate("newuser", "password")
+		response, err := service.Authenticate("newuser", "password", "10.0.0.1")
 		assert.NoError(t, err)
-		assert.NotEmpty(t, token)
+		assert.NotEmpty(t, response.Token)
 		userRepo.AssertExpectations(t)
 	})
 
 	t.Run("ExistingUserCorrectPassword", func(t *testing.T) {
-		hashedPassword, _ := hashPassword("password")
+		hashedPassword, _ := testPasswords.Hash("password")
 		existingUser := &entity.User{
 			Name:         "existinguser",
 			PasswordHash: hashedPassword,
@@ -81,11 +238,44 @@ func TestAuthService_Authenticate(t *testing.T) {
 
 		uow := &MockAuthUnitOfWork{userRepo: userRepo}
 		jwtAuth := provider.NewJWTAuth([]byte("test_secret"), time.Hour)
-		service := NewAuthService(jwtAuth, uow)
+		service := newTestAuthService(jwtAuth, uow, testAuthConfig)
 
-		token, err := service.Authenticate("existinguser", "password")
+		response, err := service.Authenticate("existinguser", "password", "10.0.0.1")
 		assert.NoError(t, err)
-		assert.NotEmpty(t, token)
+		assert.NotEmpty(t, response.Token)
+	})
+
+	t.Run("NewUserWeakPassword", func(t *testing.T) {
+		userRepo := &MockAuthUserRepository{}
+		userRepo.On("FindUserByName", "newuser").Return((*entity.User)(nil), nil)
+
+		uow := &MockAuthUnitOfWork{userRepo: userRepo}
+		jwtAuth := provider.NewJWTAuth([]byte("test_secret"), time.Hour)
+		service := newTestAuthService(jwtAuth, uow, testAuthConfig)
+
+		_, err := service.Authenticate("newuser", "1", "10.0.0.1")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+		userRepo.AssertNotCalled(t, "CreateUser", mock.Anything)
+	})
+
+	t.Run("RehashesOlderCost", func(t *testing.T) {
+		oldHash, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost+1)
+		existingUser := &entity.User{Name: "existinguser", PasswordHash: string(oldHash)}
+
+		userRepo := &MockAuthUserRepository{}
+		userRepo.On("FindUserByName", "existinguser").Return(existingUser, nil)
+		userRepo.On("UpdateUserColumns", existingUser, []string{"password_hash"}).Return(nil)
+
+		uow := &MockAuthUnitOfWork{userRepo: userRepo}
+		jwtAuth := provider.NewJWTAuth([]byte("test_secret"), time.Hour)
+		service := newTestAuthService(jwtAuth, uow, testAuthConfig)
+
+		_, err := service.Authenticate("existinguser", "password", "10.0.0.1")
+		assert.NoError(t, err)
+		cost, _ := bcrypt.Cost([]byte(existingUser.PasswordHash))
+		assert.Equal(t, bcrypt.MinCost, cost)
+		assert.True(t, verifyPassword("password", existingUser.PasswordHash))
+		userRepo.AssertExpectations(t)
 	})
 
 	t.Run("ExistingUserIncorrectPassword", func(t *testing.T) {
@@ -99,9 +289,374 @@ func TestAuthService_Authenticate(t *testing.T) {
 
 		uow := &MockAuthUnitOfWork{userRepo: userRepo}
 		jwtAuth := provider.NewJWTAuth([]byte("test_secret"), time.Hour)
-		service := NewAuthService(jwtAuth, uow)
+		service := newTestAuthService(jwtAuth, uow, testAuthConfig)
+
+		_, err := service.Authenticate("existinguser", "password", "10.0.0.1")
+		assert.EqualError(t, err, "password is incorrect")
+	})
+
+	t.Run("ServiceAccount", func(t *testing.T) {
+		hashedPassword, _ := testPasswords.Hash("password")
+		serviceAccount := &entity.User{Name: "bot", PasswordHash: hashedPassword, Role: entity.RoleService}
+
+		userRepo := &MockAuthUserRepository{}
+		userRepo.On("FindUserByName", "bot").Return(serviceAccount, nil)
+
+		uow := &MockAuthUnitOfWork{userRepo: userRepo}
+		jwtAuth := provider.NewJWTAuth([]byte("test_secret"), time.Hour)
+		service := newTestAuthService(jwtAuth, uow, testAuthConfig)
+
+		_, err := service.Authenticate("bot", "password", "10.0.0.1")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+}
+
+func TestAuthService_ChangePassword(t *testing.T) {
+	jwtAuth := provider.NewJWTAuth([]byte("test_secret"), time.Hour)
+
+	t.Run("WrongOldPassword", func(t *testing.T) {
+		hashedPassword, _ := testPasswords.Hash("password")
+		user := &entity.User{Model: gorm.Model{ID: 1}, PasswordHash: hashedPassword}
+
+		userRepo := &MockAuthUserRepository{}
+		userRepo.On("FindUserById", uint(1)).Return(user, nil)
 
-		_, err := service.Authenticate("existinguser", "password")
+		service := newTestAuthService(jwtAuth, &MockAuthUnitOfWork{userRepo: userRepo}, testAuthConfig)
+
+		_, err := service.ChangePassword(1, "guess", "new password", "10.0.0.1")
 		assert.EqualError(t, err, "password is incorrect")
+		userRepo.AssertNotCalled(t, "UpdateUserColumns", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Throttled", func(t *testing.T) {
+		now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+		hashedPassword, _ := testPasswords.Hash("password")
+		user := &entity.User{Model: gorm.Model{ID: 1}, Name: "alice", PasswordHash: hashedPassword}
+
+		userRepo := &MockAuthUserRepository{}
+		userRepo.On("FindUserById", uint(1)).Return(user, nil)
+		throttleRepo := &MockLoginThrottleRepository{}
+		throttleRepo.On("FindThrottle", entity.ThrottleUser, "alice").
+			Return(&entity.LoginThrottle{Failures: 3, LastFailureAt: now.Add(-5 * time.Minute)}, nil)
+
+		cfg := testAuthConfig
+		cfg.MaxFailedLogins = 3
+		cfg.LockoutDuration = 15 * time.Minute
+		service := newTestAuthService(jwtAuth, &MockAuthUnitOfWork{userRepo: userRepo, throttleRepo: throttleRepo}, cfg)
+		service.now = func() time.Time { return now }
+
+		_, err := service.ChangePassword(1, "password", "new password", "10.0.0.1")
+		assert.EqualError(t, err, "too many failed attempts, try again in 10m0s")
+		userRepo.AssertNotCalled(t, "UpdateUserColumns", mock.Anything, mock.Anything)
 	})
+
+	t.Run("InvalidatesSessions", func(t *testing.T) {
+		hashedPassword, _ := testPasswords.Hash("password")
+		user := &entity.User{Model: gorm.Model{ID: 1}, PasswordHash: hashedPassword, SessionVersion: 2}
+
+		userRepo := &MockAuthUserRepository{}
+		userRepo.On("FindUserById", uint(1)).Return(user, nil)
+		userRepo.On("UpdateUserColumns", user, []string{"password_hash", "session_version", "reset_token_hash", "reset_token_expires_at"}).Return(nil)
+
+		service := newTestAuthService(jwtAuth, &MockAuthUnitOfWork{userRepo: userRepo}, testAuthConfig)
+
+		token, err := service.ChangePassword(1, "password", "new password", "10.0.0.1")
+		assert.NoError(t, err)
+		assert.True(t, verifyPassword("new password", user.PasswordHash))
+		assert.Equal(t, uint(3), user.SessionVersion)
+
+		claims, err := jwtAuth.VerifyToken(token)
+		assert.NoError(t, err)
+		assert.NoError(t, service.ValidateSession(1, claims.SessionVersion))
+		assert.EqualError(t, service.ValidateSession(1, 2), "session expired")
+	})
+
+	t.Run("ServiceAccount", func(t *testing.T) {
+		hashedPassword, _ := testPasswords.Hash("password")
+		user := &entity.User{Model: gorm.Model{ID: 1}, PasswordHash: hashedPassword, Role: entity.RoleService}
+
+		userRepo := &MockAuthUserRepository{}
+		userRepo.On("FindUserById", uint(1)).Return(user, nil)
+
+		service := newTestAuthService(jwtAuth, &MockAuthUnitOfWork{userRepo: userRepo}, testAuthConfig)
+
+		_, err := service.ChangePassword(1, "password", "new password", "10.0.0.1")
+		assert.EqualError(t, err, "service accounts have no password")
+		userRepo.AssertNotCalled(t, "UpdateUserColumns", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuthService_IssueResetToken_ServiceAccount(t *testing.T) {
+	jwtAuth := provider.NewJWTAuth([]byte("test_secret"), time.Hour)
+	user := &entity.User{Model: gorm.Model{ID: 1}, Name: "bot", Role: entity.RoleService}
+
+	userRepo := &MockAuthUserRepository{}
+	userRepo.On("FindUserByName", "bot").Return(user, nil)
+
+	service := newTestAuthService(jwtAuth, &MockAuthUnitOfWork{userRepo: userRepo}, testAuthConfig)
+
+	_, err := service.IssueResetToken("bot")
+	assert.EqualError(t, err, "service accounts have no password")
+	assert.Empty(t, user.ResetTokenHash)
+	userRepo.AssertNotCalled(t, "UpdateUserColumns", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_ResetPassword(t *testing.T) {
+	jwtAuth := provider.NewJWTAuth([]byte("test_secret"), time.Hour)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	user := &entity.User{Model: gorm.Model{ID: 1}, Name: "alice"}
+
+	userRepo := &MockAuthUserRepository{}
+	userRepo.On("FindUserByName", "alice").Return(user, nil)
+	userRepo.On("UpdateUserColumns", user, mock.Anything).Return(nil)
+
+	service := newTestAuthService(jwtAuth, &MockAuthUnitOfWork{userRepo: userRepo}, testAuthConfig)
+	service.now = func() time.Time { return now }
+
+	reset, err := service.IssueResetToken("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(time.Hour), reset.ExpiresAt)
+	assert.NotEqual(t, reset.Token, user.ResetTokenHash)
+	userRepo.On("FindUserByResetToken", user.ResetTokenHash).Return(user, nil)
+	userRepo.On("FindUserByResetToken", mock.Anything).Return((*entity.User)(nil), nil)
+
+	t.Run("Expired", func(t *testing.T) {
+		service.now = func() time.Time { return now.Add(time.Hour) }
+
+		_, err := service.ResetPassword(reset.Token, "new password")
+		assert.EqualError(t, err, "reset token is invalid or expired")
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		service.now = func() time.Time { return now.Add(time.Minute) }
+
+		_, err := service.ResetPassword(reset.Token, "new password")
+		assert.NoError(t, err)
+		assert.True(t, verifyPassword("new password", user.PasswordHash))
+		assert.Equal(t, uint(1), user.SessionVersion)
+		assert.Empty(t, user.ResetTokenHash)
+		assert.Nil(t, user.ResetTokenExpiresAt)
+	})
+}
+
+func TestAuthService_Throttling(t *testing.T) {
+	jwtAuth := provider.NewJWTAuth([]byte("test_secret"), time.Hour)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	cfg := config.Auth{MaxFailedLogins: 3, MaxFailedLoginsPerIP: 10, LockoutDuration: 15 * time.Minute, LoginBackoff: time.Second}
+	hashedPassword, _ := testPasswords.Hash("password")
+	existingUser := &entity.User{Name: "alice", PasswordHash: hashedPassword}
+
+	newService := func(throttleRepo *MockLoginThrottleRepository) AuthService {
+		userRepo := &MockAuthUserRepository{}
+		userRepo.On("FindUserByName", "alice").Return(existingUser, nil)
+		userRepo.On("FindUserByName", "nobody").Return((*entity.User)(nil), nil)
+		service := newTestAuthService(jwtAuth, &MockAuthUnitOfWork{userRepo: userRepo, throttleRepo: throttleRepo}, cfg)
+		service.now = func() time.Time { return now }
+		return service
+	}
+
+	t.Run("RecordsFailure", func(t *testing.T) {
+		throttleRepo := &MockLoginThrottleRepository{}
+		throttleRepo.On("FindThrottle", mock.Anything, mock.Anything).Return((*entity.LoginThrottle)(nil), nil)
+		throttleRepo.On("RecordFailure", entity.ThrottleUser, "alice", now, now.Add(-15*time.Minute)).Return(&entity.LoginThrottle{Failures: 1}, nil)
+		throttleRepo.On("RecordFailure", entity.ThrottleIP, "10.0.0.1", now, now.Add(-15*time.Minute)).Return(&entity.LoginThrottle{Failures: 1}, nil)
+
+		_, err := newService(throttleRepo).Authenticate("alice", "wrong password", "10.0.0.1")
+		assert.EqualError(t, err, "password is incorrect")
+		throttleRepo.AssertExpectations(t)
+	})
+
+	t.Run("BacksOff", func(t *testing.T) {
+		throttleRepo := &MockLoginThrottleRepository{}
+		throttleRepo.On("FindThrottle", entity.ThrottleUser, "alice").
+			Return(&entity.LoginThrottle{Failures: 2, LastFailureAt: now.Add(-time.Second)}, nil)
+
+		_, err := newService(throttleRepo).Authenticate("alice", "password", "10.0.0.1")
+		var tooManyAttempts *TooManyAttemptsError
+		assert.ErrorAs(t, err, &tooManyAttempts)
+		assert.Equal(t, time.Second, tooManyAttempts.RetryAfter)
+	})
+
+	t.Run("LocksOut", func(t *testing.T) {
+		throttleRepo := &MockLoginThrottleRepository{}
+		throttleRepo.On("FindThrottle", entity.ThrottleUser, "alice").
+			Return(&entity.LoginThrottle{Failures: 3, LastFailureAt: now.Add(-5 * time.Minute)}, nil)
+
+		_, err := newService(throttleRepo).Authenticate("alice", "password", "10.0.0.1")
+		assert.EqualError(t, err, "too many failed attempts, try again in 10m0s")
+	})
+
+	t.Run("LocksOutIP", func(t *testing.T) {
+		throttleRepo := &MockLoginThrottleRepository{}
+		throttleRepo.On("FindThrottle", entity.ThrottleUser, "alice").Return((*entity.LoginThrottle)(nil), nil)
+		throttleRepo.On("FindThrottle", entity.ThrottleIP, "10.0.0.1").
+			Return(&entity.LoginThrottle{Failures: 10, LastFailureAt: now.Add(-time.Minute)}, nil)
+
+		_, err := newService(throttleRepo).Authenticate("alice", "password", "10.0.0.1")
+		assert.EqualError(t, err, "too many failed attempts, try again in 14m0s")
+	})
+
+	t.Run("SuccessResetsFailures", func(t *testing.T) {
+		throttleRepo := &MockLoginThrottleRepository{}
+		throttleRepo.On("FindThrottle", entity.ThrottleUser, "alice").
+			Return(&entity.LoginThrottle{Failures: 2, LastFailureAt: now.Add(-time.Minute)}, nil)
+		throttleRepo.On("FindThrottle", entity.ThrottleIP, "10.0.0.1").Return((*entity.LoginThrottle)(nil), nil)
+		throttleRepo.On("RecordFailure", entity.ThrottleUser, "alice", now, now.Add(-15*time.Minute)).Return(&entity.LoginThrottle{Failures: 3}, nil)
+		throttleRepo.On("RecordFailure", entity.ThrottleIP, "10.0.0.1", now, now.Add(-15*time.Minute)).Return(&entity.LoginThrottle{Failures: 1}, nil)
+		throttleRepo.On("ReleaseFailure", entity.ThrottleUser, "alice").Return(nil)
+		throttleRepo.On("ReleaseFailure", entity.ThrottleIP, "10.0.0.1").Return(nil)
+		throttleRepo.On("DeleteThrottle", entity.ThrottleUser, "alice").Return(true, nil)
+
+		_, err := newService(throttleRepo).Authenticate("alice", "password", "10.0.0.1")
+		assert.NoError(t, err)
+		throttleRepo.AssertExpectations(t)
+	})
+
+	t.Run("RefusesConcurrentAttempts", func(t *testing.T) {
+		throttleRepo := &MockLoginThrottleRepository{}
+		throttleRepo.On("FindThrottle", mock.Anything, mock.Anything).Return((*entity.LoginThrottle)(nil), nil)
+		throttleRepo.On("RecordFailure", entity.ThrottleUser, "alice", now, now.Add(-15*time.Minute)).Return(&entity.LoginThrottle{Failures: 2}, nil)
+		throttleRepo.On("ReleaseFailure", entity.ThrottleUser, "alice").Return(nil)
+
+		_, err := newService(throttleRepo).Authenticate("alice", "password", "10.0.0.1")
+		var tooManyAttempts *TooManyAttemptsError
+		assert.ErrorAs(t, err, &tooManyAttempts)
+		assert.Equal(t, time.Second, tooManyAttempts.RetryAfter)
+		throttleRepo.AssertExpectations(t)
+		throttleRepo.AssertNotCalled(t, "RecordFailure", entity.ThrottleIP, "10.0.0.1", mock.Anything, mock.Anything)
+	})
+
+	t.Run("UnknownUserRejectedPassword", func(t *testing.T) {
+		throttleRepo := &MockLoginThrottleRepository{}
+		throttleRepo.On("FindThrottle", mock.Anything, mock.Anything).Return((*entity.LoginThrottle)(nil), nil)
+		throttleRepo.On("RecordFailure", entity.ThrottleUser, "nobody", now, now.Add(-15*time.Minute)).Return(&entity.LoginThrottle{Failures: 1}, nil)
+		throttleRepo.On("RecordFailure", entity.ThrottleIP, "10.0.0.1", now, now.Add(-15*time.Minute)).Return(&entity.LoginThrottle{Failures: 1}, nil)
+
+		_, err := newService(throttleRepo).Authenticate("nobody", "short", "10.0.0.1")
+		assert.EqualError(t, err, "password is incorrect")
+		throttleRepo.AssertExpectations(t)
+	})
+
+	t.Run("GetLockouts", func(t *testing.T) {
+		throttleRepo := &MockLoginThrottleRepository{}
+		throttleRepo.On("GetThrottles", uint(3), now.Add(-15*time.Minute)).Return([]entity.LoginThrottle{
+			{Kind: entity.ThrottleUser, Value: "alice", Failures: 3, LastFailureAt: now.Add(-time.Minute)},
+			{Kind: entity.ThrottleIP, Value: "10.0.0.1", Failures: 4, LastFailureAt: now.Add(-time.Minute)},
+		}, nil)
+
+		lockouts, err := newService(throttleRepo).GetLockouts()
+		assert.NoError(t, err)
+		assert.Len(t, lockouts, 1)
+		assert.Equal(t, "alice", lockouts[0].Value)
+		assert.Equal(t, now.Add(14*time.Minute), lockouts[0].LockedUntil)
+	})
+}
+
+func TestAuthService_TwoFactorSignIn(t *testing.T) {
+	jwtAuth := provider.NewJWTAuth([]byte("test_secret"), time.Hour)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	secret, _ := provider.GenerateTOTPSecret()
+	hashedPassword, _ := testPasswords.Hash("password")
+	user := &entity.User{Model: gorm.Model{ID: 1}, Name: "alice", PasswordHash: hashedPassword, TOTPEnabled: true, TOTPSecret: secret}
+
+	userRepo := &MockAuthUserRepository{}
+	userRepo.On("FindUserByName", "alice").Return(user, nil)
+	userRepo.On("FindUserById", uint(1)).Return(user, nil)
+	userRepo.On("UpdateUserColumns", user, []string{"totp_last_step"}).Return(nil)
+	recoveryCodeRepo := &MockRecoveryCodeRepository{}
+	recoveryCodeRepo.On("UseRecoveryCode", uint(1), hashToken("abcde12345"), now).Return(true, nil)
+	recoveryCodeRepo.On("UseRecoveryCode", uint(1), mock.Anything, now).Return(false, nil)
+
+	cfg := testAuthConfig
+	cfg.TwoFactorChallengeTTL = time.Minute
+	service := newTestAuthService(jwtAuth, &MockAuthUnitOfWork{userRepo: userRepo, recoveryCodeRepo: recoveryCodeRepo}, cfg)
+	service.now = func() time.Time { return now }
+
+	response, err := service.Authenticate("alice", "password", "10.0.0.1")
+	assert.NoError(t, err)
+	assert.True(t, response.TwoFactorRequired)
+	assert.Empty(t, response.Token)
+	_, err = jwtAuth.VerifyToken(response.ChallengeToken)
+	assert.Error(t, err, "a challenge token must not be accepted as a session token")
+
+	code, _ := provider.TOTPCode(secret, provider.TOTPStep(now))
+
+	t.Run("AuthenticatorCode", func(t *testing.T) {
+		token, err := service.VerifyTwoFactor(response.ChallengeToken, code, "10.0.0.1")
+		assert.NoError(t, err)
+		assert.NotEmpty(t, token)
+		assert.Equal(t, provider.TOTPStep(now), user.TOTPLastStep)
+	})
+
+	t.Run("ReusedCode", func(t *testing.T) {
+		_, err := service.VerifyTwoFactor(response.ChallengeToken, code, "10.0.0.1")
+		assert.EqualError(t, err, "code is incorrect")
+	})
+
+	t.Run("RecoveryCode", func(t *testing.T) {
+		token, err := service.VerifyTwoFactor(response.ChallengeToken, "ABCDE-12345", "10.0.0.1")
+		assert.NoError(t, err)
+		assert.NotEmpty(t, token)
+	})
+
+	t.Run("SessionTokenIsNotAChallenge", func(t *testing.T) {
+		sessionToken, _ := jwtAuth.GenerateToken(1, entity.RoleUser, 0)
+		_, err := service.VerifyTwoFactor(sessionToken, code, "10.0.0.1")
+		assert.EqualError(t, err, "challenge token is invalid or expired")
+	})
+}
+
+func TestAuthService_TwoFactorEnrolment(t *testing.T) {
+	jwtAuth := provider.NewJWTAuth([]byte("test_secret"), time.Hour)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	hashedPassword, _ := testPasswords.Hash("password")
+	user := &entity.User{Model: gorm.Model{ID: 1}, Name: "alice", PasswordHash: hashedPassword}
+
+	userRepo := &MockUserRepository{}
+	userRepo.On("FindUserById", uint(1)).Return(user, nil)
+	userRepo.On("UpdateUserColumns", user, []string{"totp_secret"}).Return(nil)
+	userRepo.On("UpdateUserColumns", user, []string{"totp_last_step"}).Return(nil)
+	userRepo.On("UpdateUserColumns", user, []string{"totp_secret", "totp_enabled", "totp_last_step"}).Return(nil)
+	recoveryCodeRepo := &MockRecoveryCodeRepository{}
+	recoveryCodeRepo.On("DeleteRecoveryCodes", uint(1)).Return(nil)
+	recoveryCodeRepo.On("CreateRecoveryCodes", mock.AnythingOfType("[]entity.RecoveryCode")).Return(nil)
+	recoveryCodeRepo.On("UseRecoveryCode", uint(1), mock.Anything, mock.Anything).Return(false, nil)
+	tx := &MockTransactionUnitOfWork{UserRepo: userRepo, RecoveryCodeRepo: recoveryCodeRepo}
+
+	cfg := testAuthConfig
+	cfg.TOTPIssuer = "Merch Shop"
+	service := newTestAuthService(jwtAuth, &MockUnitOfWork{transactionUnitOfWork: tx}, cfg)
+	service.now = func() time.Time { return now }
+
+	enrolment, err := service.BeginTwoFactorEnrolment(1)
+	assert.NoError(t, err)
+	assert.Equal(t, enrolment.Secret, user.TOTPSecret)
+	assert.False(t, user.TOTPEnabled)
+	assert.Contains(t, enrolment.ProvisioningURI, "otpauth://totp/Merch%20Shop:alice?")
+
+	_, err = service.ConfirmTwoFactor(1, "000000")
+	assert.EqualError(t, err, "code is incorrect")
+	assert.False(t, user.TOTPEnabled)
+
+	code, _ := provider.TOTPCode(user.TOTPSecret, provider.TOTPStep(now))
+	recoveryCodes, err := service.ConfirmTwoFactor(1, code)
+	assert.NoError(t, err)
+	assert.True(t, user.TOTPEnabled)
+	assert.Len(t, recoveryCodes.Codes, recoveryCodeCount)
+	assert.True(t, tx.commitCalled)
+	stored := recoveryCodeRepo.Calls[len(recoveryCodeRepo.Calls)-1].Arguments.Get(0).([]entity.RecoveryCode)
+	assert.Equal(t, hashToken(normalizeRecoveryCode(recoveryCodes.Codes[0])), stored[0].CodeHash)
+
+	_, err = service.BeginTwoFactorEnrolment(1)
+	assert.EqualError(t, err, "two-factor authentication is already enabled")
+
+	later := now.Add(time.Minute)
+	service.now = func() time.Time { return later }
+	code, _ = provider.TOTPCode(user.TOTPSecret, provider.TOTPStep(later))
+	assert.EqualError(t, service.DisableTwoFactor(1, "guess", code, "10.0.0.1"), "password is incorrect")
+	assert.EqualError(t, service.DisableTwoFactor(1, "password", "000000", "10.0.0.1"), "code is incorrect")
+	assert.True(t, user.TOTPEnabled)
+	assert.NoError(t, service.DisableTwoFactor(1, "password", code, "10.0.0.1"))
+	assert.False(t, user.TOTPEnabled)
+	assert.Empty(t, user.TOTPSecret)
 }
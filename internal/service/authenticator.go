@@ -0,0 +1,93 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"merch_shop/internal/entity"
+	"merch_shop/internal/repository"
+)
+
+// ErrInvalidCredentials is returned by authenticators for a wrong username or password. Sign-ins
+// failing with it count towards the login throttle.
+var ErrInvalidCredentials = errors.New("password is incorrect")
+
+// Authenticator checks the passwords of users signing in against a credential store.
+type Authenticator interface {
+	// Authenticate returns the user the credentials belong to, registering or updating the local user
+	// as the store requires.
+	Authenticate(username string, password string) (*entity.User, error)
+	// VerifyPassword reports whether password is the current password of the signed-in user.
+	VerifyPassword(user *entity.User, password string) (bool, error)
+	// ManagesPasswords reports whether passwords are stored by the shop, and so can be changed and reset.
+	ManagesPasswords() bool
+}
+
+// PasswordAuthenticator checks the bcrypt hashes stored with the users and registers unknown usernames
+// on their first sign-in.
+type PasswordAuthenticator struct {
+	uow       repository.UnitOfWork
+	passwords PasswordPolicy
+}
+
+func NewPasswordAuthenticator(uow repository.UnitOfWork, passwords PasswordPolicy) PasswordAuthenticator {
+	return PasswordAuthenticator{uow: uow, passwords: passwords}
+}
+
+func (p PasswordAuthenticator) Authenticate(username string, password string) (*entity.User, error) {
+	userRepository := p.uow.UserRepository()
+
+	user, err := userRepository.FindUserByName(username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user by username %s", username)
+	}
+	if user == nil {
+		if err := p.passwords.Validate(password); err != nil {
+			// Answer like a wrong password of an existing user would, so usernames cannot be probed
+			// and the attempt counts towards the throttle.
+			p.passwords.DummyVerify(password)
+			return nil, ErrInvalidCredentials
+		}
+		passwordHash, err := p.passwords.Hash(password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash password")
+		}
+		user = &entity.User{
+			Name:         username,
+			PasswordHash: passwordHash,
+			Balance:      START_BALANCE,
+			Role:         entity.RoleUser,
+		}
+
+		if err := userRepository.CreateUser(user); err != nil {
+			return nil, fmt.Errorf("failed to create user")
+		}
+		return user, nil
+	}
+	if user.Role == entity.RoleService {
+		// Service accounts sign in with API keys only.
+		p.passwords.DummyVerify(password)
+		return nil, ErrInvalidCredentials
+	}
+	if !verifyPassword(password, user.PasswordHash) {
+		return nil, ErrInvalidCredentials
+	}
+	if p.passwords.NeedsRehash(user.PasswordHash) {
+		passwordHash, err := p.passwords.Hash(password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash password")
+		}
+		user.PasswordHash = passwordHash
+		if err := userRepository.UpdateUserColumns(user, "password_hash"); err != nil {
+			return nil, fmt.Errorf("failed to update user")
+		}
+	}
+	return user, nil
+}
+
+func (p PasswordAuthenticator) VerifyPassword(user *entity.User, password string) (bool, error) {
+	return verifyPassword(password, user.PasswordHash), nil
+}
+
+func (p PasswordAuthenticator) ManagesPasswords() bool {
+	return true
+}
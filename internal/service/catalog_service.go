@@ -0,0 +1,313 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"merch_shop/internal/entity"
+	"merch_shop/internal/model"
+	"merch_shop/internal/repository"
+	"slices"
+	"strings"
+	"time"
+)
+
+type CatalogService struct {
+	uow repository.UnitOfWork
+	now func() time.Time
+}
+
+func NewCatalogService(uow repository.UnitOfWork) *CatalogService {
+	return &CatalogService{uow: uow, now: time.Now}
+}
+
+// GetItems lists the catalog with the prices in effect right now, optionally
+// narrowed to one category and to items carrying all of the given tags.
+func (c CatalogService) GetItems(category string, tags []string) ([]model.Item, error) {
+	items, err := c.uow.ItemRepository().GetItems(category, normalizeTags(tags))
+	if err != nil {
+		return nil, fmt.Errorf("error getting items")
+	}
+	now := c.now()
+	itemsModel := make([]model.Item, 0, len(items))
+	for _, v := range items {
+		price, err := effectivePrice(c.uow, &v, now)
+		if err != nil {
+			return nil, err
+		}
+		item := toItemModel(v)
+		item.Price = price
+		itemsModel = append(itemsModel, item)
+	}
+	return itemsModel, nil
+}
+
+func (c CatalogService) GetCategories() ([]model.Category, error) {
+	categories, err := c.uow.ItemRepository().GetCategories()
+	if err != nil {
+		return nil, fmt.Errorf("error getting categories")
+	}
+	categoriesModel := make([]model.Category, 0, len(categories))
+	for _, v := range categories {
+		categoriesModel = append(categoriesModel, model.Category{Name: v.Name})
+	}
+	return categoriesModel, nil
+}
+
+func (c CatalogService) CreateCategory(name string) (model.Category, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return model.Category{}, fmt.Errorf("category name is empty")
+	}
+	itemRepository := c.uow.ItemRepository()
+	existing, err := itemRepository.FindCategoryByName(name)
+	if err != nil {
+		return model.Category{}, fmt.Errorf("failed to find category")
+	}
+	if existing != nil {
+		return model.Category{}, fmt.Errorf("category already exists")
+	}
+	if err := itemRepository.CreateCategory(&entity.Category{Name: name}); err != nil {
+		return model.Category{}, fmt.Errorf("failed to create category")
+	}
+	return model.Category{Name: name}, nil
+}
+
+// SetItemCategory moves the item to an existing category; an empty category leaves it uncategorized.
+func (c CatalogService) SetItemCategory(name string, category string) error {
+	item, err := c.uow.TransactionRepository().GetItemByName(name)
+	if err != nil {
+		return fmt.Errorf("failed to find item")
+	}
+	if item == nil {
+		return fmt.Errorf("item not found")
+	}
+	if category != "" {
+		found, err := c.uow.ItemRepository().FindCategoryByName(category)
+		if err != nil {
+			return fmt.Errorf("failed to find category")
+		}
+		if found == nil {
+			return fmt.Errorf("category not found")
+		}
+	}
+	if err := c.uow.ItemRepository().SetItemCategory(item.ID, category); err != nil {
+		return fmt.Errorf("failed to update item")
+	}
+	return nil
+}
+
+// SetItemTags replaces all tags of the item.
+func (c CatalogService) SetItemTags(name string, tags []string) error {
+	tx, err := c.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction")
+	}
+
+	item, err := tx.TransactionRepository().GetItemByName(name)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to find item")
+	}
+	if item == nil {
+		tx.Rollback()
+		return fmt.Errorf("item not found")
+	}
+	if err := tx.ItemRepository().ReplaceItemTags(item.ID, normalizeTags(tags)); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to update tags")
+	}
+
+	tx.Commit()
+	return nil
+}
+
+// GetPriceHistory lists past, current and scheduled prices of the item, oldest first.
+func (c CatalogService) GetPriceHistory(name string) ([]model.ItemPrice, error) {
+	item, err := c.uow.TransactionRepository().GetItemByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find item")
+	}
+	if item == nil {
+		return nil, fmt.Errorf("item not found")
+	}
+	prices, err := c.uow.ItemRepository().GetItemPrices(item.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting price history")
+	}
+	pricesModel := make([]model.ItemPrice, 0, len(prices)+1)
+	if len(prices) == 0 {
+		pricesModel = append(pricesModel, model.ItemPrice{Price: item.Price, EffectiveFrom: item.CreatedAt})
+	}
+	for _, v := range prices {
+		pricesModel = append(pricesModel, model.ItemPrice{Price: v.Price, EffectiveFrom: v.EffectiveFrom})
+	}
+	return pricesModel, nil
+}
+
+// SchedulePrice adds a price change taking effect at effectiveFrom, or immediately when it is nil.
+// The first change also records the original price so that the history is complete.
+func (c CatalogService) SchedulePrice(name string, price uint, effectiveFrom *time.Time) (model.ItemPrice, error) {
+	now := c.now()
+	if effectiveFrom == nil {
+		effectiveFrom = &now
+	}
+	if effectiveFrom.Before(now) {
+		return model.ItemPrice{}, fmt.Errorf("price change cannot take effect in the past")
+	}
+
+	tx, err := c.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return model.ItemPrice{}, fmt.Errorf("failed to begin transaction")
+	}
+
+	item, err := tx.TransactionRepository().GetItemByName(name)
+	if err != nil {
+		tx.Rollback()
+		return model.ItemPrice{}, fmt.Errorf("failed to find item")
+	}
+	if item == nil {
+		tx.Rollback()
+		return model.ItemPrice{}, fmt.Errorf("item not found")
+	}
+	itemRepository := tx.ItemRepository()
+	prices, err := itemRepository.GetItemPrices(item.ID)
+	if err != nil {
+		tx.Rollback()
+		return model.ItemPrice{}, fmt.Errorf("error getting price history")
+	}
+	if len(prices) == 0 {
+		initial := entity.ItemPrice{ItemID: item.ID, Price: item.Price, EffectiveFrom: item.CreatedAt}
+		if err := itemRepository.CreateItemPrice(&initial); err != nil {
+			tx.Rollback()
+			return model.ItemPrice{}, fmt.Errorf("failed to create price")
+		}
+	}
+	itemPrice := entity.ItemPrice{ItemID: item.ID, Price: price, EffectiveFrom: *effectiveFrom}
+	if err := itemRepository.CreateItemPrice(&itemPrice); err != nil {
+		tx.Rollback()
+		return model.ItemPrice{}, fmt.Errorf("failed to create price")
+	}
+
+	tx.Commit()
+	return model.ItemPrice{Price: itemPrice.Price, EffectiveFrom: itemPrice.EffectiveFrom}, nil
+}
+
+// Restock adds units to the item's stock, or to the variant's stock when variant is set,
+// starting stock tracking where there was none, and optionally replaces the per-user purchase limit.
+// A zero quantity leaves the stock as it is, so the limit can be changed without starting tracking.
+func (c CatalogService) Restock(name string, variant string, quantity uint, purchaseLimit *uint) (model.Item, error) {
+	tx, err := c.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return model.Item{}, fmt.Errorf("failed to begin transaction")
+	}
+
+	item, itemVariant, err := findItem(tx, name, variant)
+	if err != nil {
+		tx.Rollback()
+		return model.Item{}, err
+	}
+	if quantity == 0 && purchaseLimit == nil {
+		tx.Rollback()
+		return model.Item{}, fmt.Errorf("nothing to restock")
+	}
+	// Only the changed columns are written, so purchases taking stock meanwhile are not undone.
+	switch {
+	case quantity == 0:
+		// Only the purchase limit changes.
+	case itemVariant != nil:
+		if err := tx.ItemRepository().RestockVariant(itemVariant.ID, quantity); err != nil {
+			tx.Rollback()
+			return model.Item{}, fmt.Errorf("failed to update variant")
+		}
+	default:
+		if err := tx.ItemRepository().RestockItem(item.ID, quantity); err != nil {
+			tx.Rollback()
+			return model.Item{}, fmt.Errorf("failed to update item")
+		}
+		item.Stock = addStock(item.Stock, quantity)
+	}
+	if purchaseLimit != nil {
+		if err := tx.ItemRepository().SetPurchaseLimit(item.ID, *purchaseLimit); err != nil {
+			tx.Rollback()
+			return model.Item{}, fmt.Errorf("failed to update item")
+		}
+		item.PurchaseLimit = *purchaseLimit
+	}
+	price, err := effectivePrice(tx, item, c.now())
+	if err != nil {
+		tx.Rollback()
+		return model.Item{}, err
+	}
+
+	tx.Commit()
+	itemModel := toItemModel(*item)
+	itemModel.Price = price
+	return itemModel, nil
+}
+
+func (c CatalogService) AddVariant(name string, variant model.ItemVariant) (model.ItemVariant, error) {
+	item, err := c.uow.TransactionRepository().GetItemByName(name)
+	if err != nil {
+		return model.ItemVariant{}, fmt.Errorf("failed to find item")
+	}
+	if item == nil {
+		return model.ItemVariant{}, fmt.Errorf("item not found")
+	}
+	itemVariant := entity.ItemVariant{
+		ItemID:     item.ID,
+		Name:       variant.Name,
+		PriceDelta: variant.PriceDelta,
+		Stock:      variant.Stock,
+	}
+	if err := c.uow.ItemRepository().CreateVariant(&itemVariant); err != nil {
+		return model.ItemVariant{}, fmt.Errorf("failed to create variant")
+	}
+	return variant, nil
+}
+
+// effectivePrice returns the item's price at the given moment according to its price history.
+func effectivePrice(tx repository.UnitOfWork, item *entity.Item, at time.Time) (uint, error) {
+	price, err := tx.ItemRepository().FindEffectivePrice(item.ID, at)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find price")
+	}
+	if price == nil {
+		return item.Price, nil
+	}
+	return price.Price, nil
+}
+
+// normalizeTags lowercases tags and drops blanks and duplicates.
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+func addStock(stock *uint, quantity uint) *uint {
+	total := quantity
+	if stock != nil {
+		total += *stock
+	}
+	return &total
+}
+
+func toItemModel(item entity.Item) model.Item {
+	tags := make([]string, 0, len(item.Tags))
+	for _, v := range item.Tags {
+		tags = append(tags, v.Tag)
+	}
+	return model.Item{
+		Name:          item.Name,
+		Price:         item.Price,
+		Category:      item.Category,
+		Tags:          tags,
+		Stock:         item.Stock,
+		PurchaseLimit: item.PurchaseLimit,
+	}
+}
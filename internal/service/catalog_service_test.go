@@ -0,0 +1,229 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"merch_shop/internal/model"
+	"testing"
+	"time"
+)
+
+func TestCatalogService_Restock(t *testing.T) {
+	t.Run("StartsTracking", func(t *testing.T) {
+		item := &entity.Item{Model: gorm.Model{ID: 3}, Name: "pink-hoody", Price: 500}
+		limit := uint(2)
+
+		transactionRepo := &MockTransactionRepository{}
+		transactionRepo.On("GetItemByName", "pink-hoody").Return(item, nil)
+
+		itemRepo := &MockItemRepository{}
+		itemRepo.On("RestockItem", uint(3), uint(40)).Return(nil)
+		itemRepo.On("SetPurchaseLimit", uint(3), uint(2)).Return(nil)
+		itemRepo.On("FindEffectivePrice", uint(3), mock.Anything).Return((*entity.ItemPrice)(nil), nil)
+
+		tuow := &MockTransactionUnitOfWork{TransactionRepo: transactionRepo, ItemRepo: itemRepo}
+		service := NewCatalogService(&MockUnitOfWork{transactionUnitOfWork: tuow})
+
+		res, err := service.Restock("pink-hoody", "", 40, &limit)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(40), *res.Stock)
+		assert.Equal(t, uint(2), res.PurchaseLimit)
+		assert.True(t, tuow.commitCalled)
+	})
+
+	t.Run("AddsToStock", func(t *testing.T) {
+		stock := uint(5)
+		item := &entity.Item{Model: gorm.Model{ID: 3}, Name: "pink-hoody", Stock: &stock, PurchaseLimit: 2}
+
+		transactionRepo := &MockTransactionRepository{}
+		transactionRepo.On("GetItemByName", "pink-hoody").Return(item, nil)
+
+		itemRepo := &MockItemRepository{}
+		itemRepo.On("RestockItem", uint(3), uint(10)).Return(nil)
+		itemRepo.On("FindEffectivePrice", uint(3), mock.Anything).Return((*entity.ItemPrice)(nil), nil)
+
+		tuow := &MockTransactionUnitOfWork{TransactionRepo: transactionRepo, ItemRepo: itemRepo}
+		service := NewCatalogService(&MockUnitOfWork{transactionUnitOfWork: tuow})
+
+		res, err := service.Restock("pink-hoody", "", 10, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(15), *res.Stock)
+		assert.Equal(t, uint(2), res.PurchaseLimit)
+	})
+
+	t.Run("OnlyPurchaseLimit", func(t *testing.T) {
+		item := &entity.Item{Model: gorm.Model{ID: 3}, Name: "pink-hoody", Price: 500}
+		limit := uint(1)
+
+		transactionRepo := &MockTransactionRepository{}
+		transactionRepo.On("GetItemByName", "pink-hoody").Return(item, nil)
+
+		itemRepo := &MockItemRepository{}
+		itemRepo.On("SetPurchaseLimit", uint(3), uint(1)).Return(nil)
+		itemRepo.On("FindEffectivePrice", uint(3), mock.Anything).Return((*entity.ItemPrice)(nil), nil)
+
+		tuow := &MockTransactionUnitOfWork{TransactionRepo: transactionRepo, ItemRepo: itemRepo}
+		service := NewCatalogService(&MockUnitOfWork{transactionUnitOfWork: tuow})
+
+		res, err := service.Restock("pink-hoody", "", 0, &limit)
+		assert.NoError(t, err)
+		assert.Nil(t, res.Stock)
+		assert.Nil(t, item.Stock)
+		assert.Equal(t, uint(1), res.PurchaseLimit)
+		// Untracked stock is never decremented, so the item can still be bought.
+		assert.NoError(t, takeStock(tuow, item, nil))
+		itemRepo.AssertNotCalled(t, "DecrementStock", mock.Anything)
+		itemRepo.AssertNotCalled(t, "RestockItem", mock.Anything, mock.Anything)
+	})
+
+	t.Run("NothingToRestock", func(t *testing.T) {
+		item := &entity.Item{Model: gorm.Model{ID: 3}, Name: "pink-hoody", Price: 500}
+
+		transactionRepo := &MockTransactionRepository{}
+		transactionRepo.On("GetItemByName", "pink-hoody").Return(item, nil)
+
+		tuow := &MockTransactionUnitOfWork{TransactionRepo: transactionRepo, ItemRepo: &MockItemRepository{}}
+		service := NewCatalogService(&MockUnitOfWork{transactionUnitOfWork: tuow})
+
+		_, err := service.Restock("pink-hoody", "", 0, nil)
+		assert.EqualError(t, err, "nothing to restock")
+		assert.True(t, tuow.rollbackCalled)
+	})
+}
+
+func TestCatalogService_AddVariant(t *testing.T) {
+	item := &entity.Item{Model: gorm.Model{ID: 3}, Name: "hoody", Price: 300}
+
+	transactionRepo := &MockTransactionRepository{}
+	transactionRepo.On("GetItemByName", "hoody").Return(item, nil)
+
+	itemRepo := &MockItemRepository{}
+	itemRepo.On("CreateVariant", mock.MatchedBy(func(v *entity.ItemVariant) bool {
+		return v.ItemID == 3 && v.Name == "XL" && v.PriceDelta == 20
+	})).Return(nil)
+
+	tuow := &MockTransactionUnitOfWork{TransactionRepo: transactionRepo, ItemRepo: itemRepo}
+	service := NewCatalogService(&MockUnitOfWork{transactionUnitOfWork: tuow})
+
+	_, err := service.AddVariant("hoody", model.ItemVariant{Name: "XL", PriceDelta: 20})
+	assert.NoError(t, err)
+	itemRepo.AssertExpectations(t)
+}
+
+func TestCatalogService_SchedulePrice(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("InThePast", func(t *testing.T) {
+		service := NewCatalogService(&MockUnitOfWork{transactionUnitOfWork: &MockTransactionUnitOfWork{}})
+		service.now = func() time.Time { return now }
+
+		effectiveFrom := now.Add(-time.Hour)
+		_, err := service.SchedulePrice("cup", 30, &effectiveFrom)
+		assert.EqualError(t, err, "price change cannot take effect in the past")
+	})
+
+	t.Run("RecordsOriginalPrice", func(t *testing.T) {
+		createdAt := now.Add(-time.Hour * 24 * 30)
+		item := &entity.Item{Model: gorm.Model{ID: 3, CreatedAt: createdAt}, Name: "cup", Price: 20}
+		effectiveFrom := now.Add(time.Hour * 24)
+
+		transactionRepo := &MockTransactionRepository{}
+		transactionRepo.On("GetItemByName", "cup").Return(item, nil)
+
+		itemRepo := &MockItemRepository{}
+		itemRepo.On("GetItemPrices", uint(3)).Return([]entity.ItemPrice{}, nil)
+		itemRepo.On("CreateItemPrice", mock.MatchedBy(func(p *entity.ItemPrice) bool {
+			return p.Price == 20 && p.EffectiveFrom.Equal(createdAt)
+		})).Return(nil).Once()
+		itemRepo.On("CreateItemPrice", mock.MatchedBy(func(p *entity.ItemPrice) bool {
+			return p.Price == 30 && p.EffectiveFrom.Equal(effectiveFrom)
+		})).Return(nil).Once()
+
+		tuow := &MockTransactionUnitOfWork{TransactionRepo: transactionRepo, ItemRepo: itemRepo}
+		service := NewCatalogService(&MockUnitOfWork{transactionUnitOfWork: tuow})
+		service.now = func() time.Time { return now }
+
+		res, err := service.SchedulePrice("cup", 30, &effectiveFrom)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(30), res.Price)
+		itemRepo.AssertExpectations(t)
+		assert.True(t, tuow.commitCalled)
+	})
+}
+
+func TestCatalogService_GetItems(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	itemRepo := &MockItemRepository{}
+	itemRepo.On("GetItems", "", []string{}).Return([]entity.Item{
+		{Model: gorm.Model{ID: 3}, Name: "cup", Price: 20},
+		{Model: gorm.Model{ID: 4}, Name: "pen", Price: 10},
+	}, nil)
+	itemRepo.On("FindEffectivePrice", uint(3), now).Return(&entity.ItemPrice{ItemID: 3, Price: 25}, nil)
+	itemRepo.On("FindEffectivePrice", uint(4), now).Return((*entity.ItemPrice)(nil), nil)
+
+	service := NewCatalogService(&MockUnitOfWork{transactionUnitOfWork: &MockTransactionUnitOfWork{ItemRepo: itemRepo}})
+	service.now = func() time.Time { return now }
+
+	items, err := service.GetItems("", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(25), items[0].Price)
+	assert.Equal(t, uint(10), items[1].Price)
+}
+
+func TestCatalogService_SetItemCategory(t *testing.T) {
+	t.Run("UnknownCategory", func(t *testing.T) {
+		item := &entity.Item{Model: gorm.Model{ID: 3}, Name: "hoody"}
+
+		transactionRepo := &MockTransactionRepository{}
+		transactionRepo.On("GetItemByName", "hoody").Return(item, nil)
+
+		itemRepo := &MockItemRepository{}
+		itemRepo.On("FindCategoryByName", "food").Return((*entity.Category)(nil), nil)
+
+		tuow := &MockTransactionUnitOfWork{TransactionRepo: transactionRepo, ItemRepo: itemRepo}
+		service := NewCatalogService(&MockUnitOfWork{transactionUnitOfWork: tuow})
+
+		err := service.SetItemCategory("hoody", "food")
+		assert.EqualError(t, err, "category not found")
+		itemRepo.AssertNotCalled(t, "SetItemCategory", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		item := &entity.Item{Model: gorm.Model{ID: 3}, Name: "hoody"}
+
+		transactionRepo := &MockTransactionRepository{}
+		transactionRepo.On("GetItemByName", "hoody").Return(item, nil)
+
+		itemRepo := &MockItemRepository{}
+		itemRepo.On("FindCategoryByName", "apparel").Return(&entity.Category{Name: "apparel"}, nil)
+		itemRepo.On("SetItemCategory", uint(3), "apparel").Return(nil)
+
+		tuow := &MockTransactionUnitOfWork{TransactionRepo: transactionRepo, ItemRepo: itemRepo}
+		service := NewCatalogService(&MockUnitOfWork{transactionUnitOfWork: tuow})
+
+		err := service.SetItemCategory("hoody", "apparel")
+		assert.NoError(t, err)
+		itemRepo.AssertExpectations(t)
+	})
+}
+
+func TestCatalogService_SetItemTags(t *testing.T) {
+	item := &entity.Item{Model: gorm.Model{ID: 3}, Name: "hoody"}
+
+	transactionRepo := &MockTransactionRepository{}
+	transactionRepo.On("GetItemByName", "hoody").Return(item, nil)
+
+	itemRepo := &MockItemRepository{}
+	itemRepo.On("ReplaceItemTags", uint(3), []string{"new", "sale"}).Return(nil)
+
+	tuow := &MockTransactionUnitOfWork{TransactionRepo: transactionRepo, ItemRepo: itemRepo}
+	service := NewCatalogService(&MockUnitOfWork{transactionUnitOfWork: tuow})
+
+	err := service.SetItemTags("hoody", []string{" New", "sale", "new", ""})
+	assert.NoError(t, err)
+	itemRepo.AssertExpectations(t)
+	assert.True(t, tuow.commitCalled)
+}
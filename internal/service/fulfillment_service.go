@@ -0,0 +1,140 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"merch_shop/internal/entity"
+	"merch_shop/internal/model"
+	"merch_shop/internal/repository"
+	"slices"
+	"time"
+)
+
+// fulfillmentSteps lists the statuses an order moves through, in order.
+var fulfillmentSteps = []string{
+	entity.FulfillmentPlaced,
+	entity.FulfillmentPacked,
+	entity.FulfillmentReady,
+	entity.FulfillmentDelivered,
+}
+
+type FulfillmentService struct {
+	uow repository.UnitOfWork
+	now func() time.Time
+}
+
+func NewFulfillmentService(uow repository.UnitOfWork) *FulfillmentService {
+	return &FulfillmentService{uow: uow, now: time.Now}
+}
+
+// GetQueue returns the orders that still have to be handed over, grouped by item and variant.
+func (f FulfillmentService) GetQueue() ([]model.FulfillmentQueue, error) {
+	purchases, err := f.uow.PurchaseRepository().GetOpenOrders()
+	if err != nil {
+		return nil, fmt.Errorf("error getting orders")
+	}
+
+	queue := make([]model.FulfillmentQueue, 0)
+	groups := make(map[[2]uint]int)
+	for _, v := range purchases {
+		key := [2]uint{v.ItemID, v.VariantID}
+		i, ok := groups[key]
+		if !ok {
+			i = len(queue)
+			groups[key] = i
+			queue = append(queue, model.FulfillmentQueue{
+				Item:    v.Item.Name,
+				Variant: v.Variant.Name,
+				Orders:  []model.FulfillmentOrder{},
+			})
+		}
+		queue[i].Count++
+		queue[i].Orders = append(queue[i].Orders, model.FulfillmentOrder{
+			PurchaseId: v.ID,
+			Owner:      v.Owner.Name,
+			Status:     v.FulfillmentStatus,
+			CreatedAt:  v.CreatedAt,
+		})
+	}
+	return queue, nil
+}
+
+// AdvanceOrder moves an order forward to the given status, possibly skipping steps.
+func (f FulfillmentService) AdvanceOrder(purchaseId uint, status string) (model.Purchase, error) {
+	target := slices.Index(fulfillmentSteps, status)
+	if target <= 0 {
+		return model.Purchase{}, fmt.Errorf("unknown fulfillment status")
+	}
+
+	tx, err := f.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return model.Purchase{}, fmt.Errorf("failed to begin transaction")
+	}
+
+	purchase, err := findOrder(tx, purchaseId)
+	if err != nil {
+		tx.Rollback()
+		return model.Purchase{}, err
+	}
+	if target <= slices.Index(fulfillmentSteps, purchase.FulfillmentStatus) {
+		tx.Rollback()
+		return model.Purchase{}, fmt.Errorf("order is already %s", purchase.FulfillmentStatus)
+	}
+
+	now := f.now()
+	purchase.FulfillmentStatus = status
+	switch status {
+	case entity.FulfillmentPacked:
+		purchase.PackedAt = &now
+	case entity.FulfillmentReady:
+		purchase.ReadyAt = &now
+	case entity.FulfillmentDelivered:
+		purchase.DeliveredAt = &now
+	}
+	if err := tx.PurchaseRepository().UpdatePurchase(purchase); err != nil {
+		tx.Rollback()
+		return model.Purchase{}, fmt.Errorf("failed to update purchase")
+	}
+
+	tx.Commit()
+	return toPurchaseModels([]entity.Purchase{*purchase})[0], nil
+}
+
+// CancelOrder cancels an order that was not delivered yet and refunds the coins to the buyer.
+func (f FulfillmentService) CancelOrder(userId uint, purchaseId uint) (model.Refund, error) {
+	tx, err := f.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return model.Refund{}, fmt.Errorf("failed to begin transaction")
+	}
+
+	purchase, err := findOrder(tx, purchaseId)
+	if err != nil {
+		tx.Rollback()
+		return model.Refund{}, err
+	}
+	if purchase.FulfillmentStatus == entity.FulfillmentDelivered {
+		tx.Rollback()
+		return model.Refund{}, fmt.Errorf("order is already delivered")
+	}
+	if err := refundPurchase(tx, purchase, &userId, f.now()); err != nil {
+		tx.Rollback()
+		return model.Refund{}, err
+	}
+
+	tx.Commit()
+	return toRefundModel(*purchase), nil
+}
+
+func findOrder(tx repository.UnitOfWork, purchaseId uint) (*entity.Purchase, error) {
+	purchase, err := tx.PurchaseRepository().FindPurchaseById(purchaseId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find purchase")
+	}
+	if purchase == nil {
+		return nil, fmt.Errorf("purchase not found")
+	}
+	if purchase.RefundedAt != nil || purchase.FulfillmentStatus == entity.FulfillmentCancelled {
+		return nil, fmt.Errorf("order is cancelled")
+	}
+	return purchase, nil
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"testing"
+	"time"
+)
+
+func TestFulfillmentService_GetQueue(t *testing.T) {
+	hoody := entity.Item{Model: gorm.Model{ID: 3}, Name: "hoody"}
+	cup := entity.Item{Model: gorm.Model{ID: 4}, Name: "cup"}
+	xl := entity.ItemVariant{Model: gorm.Model{ID: 7}, ItemID: 3, Name: "XL"}
+
+	purchaseRepo := &MockPurchaseRepository{}
+	purchaseRepo.On("GetOpenOrders").Return([]entity.Purchase{
+		{Model: gorm.Model{ID: 1}, ItemID: 3, Item: hoody, VariantID: 7, Variant: xl, Owner: entity.User{Name: "alice"},
+			FulfillmentStatus: entity.FulfillmentPlaced},
+		{Model: gorm.Model{ID: 2}, ItemID: 4, Item: cup, Owner: entity.User{Name: "bob"},
+			FulfillmentStatus: entity.FulfillmentPacked},
+		{Model: gorm.Model{ID: 3}, ItemID: 3, Item: hoody, VariantID: 7, Variant: xl, Owner: entity.User{Name: "bob"},
+			FulfillmentStatus: entity.FulfillmentPlaced},
+	}, nil)
+
+	tuow := &MockTransactionUnitOfWork{PurchaseRepo: purchaseRepo}
+	service := NewFulfillmentService(&MockUnitOfWork{transactionUnitOfWork: tuow})
+
+	queue, err := service.GetQueue()
+	assert.NoError(t, err)
+	assert.Len(t, queue, 2)
+	assert.Equal(t, "hoody", queue[0].Item)
+	assert.Equal(t, "XL", queue[0].Variant)
+	assert.Equal(t, 2, queue[0].Count)
+	assert.Equal(t, uint(3), queue[0].Orders[1].PurchaseId)
+	assert.Equal(t, "cup", queue[1].Item)
+	assert.Equal(t, 1, queue[1].Count)
+}
+
+func TestFulfillmentService_AdvanceOrder(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("BackwardsTransition", func(t *testing.T) {
+		purchase := &entity.Purchase{Model: gorm.Model{ID: 9}, FulfillmentStatus: entity.FulfillmentReady}
+
+		purchaseRepo := &MockPurchaseRepository{}
+		purchaseRepo.On("FindPurchaseById", uint(9)).Return(purchase, nil)
+
+		tuow := &MockTransactionUnitOfWork{PurchaseRepo: purchaseRepo}
+		service := NewFulfillmentService(&MockUnitOfWork{transactionUnitOfWork: tuow})
+
+		_, err := service.AdvanceOrder(9, entity.FulfillmentPacked)
+		assert.EqualError(t, err, "order is already ready")
+		assert.True(t, tuow.rollbackCalled)
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		purchase := &entity.Purchase{Model: gorm.Model{ID: 9}, FulfillmentStatus: entity.FulfillmentCancelled}
+
+		purchaseRepo := &MockPurchaseRepository{}
+		purchaseRepo.On("FindPurchaseById", uint(9)).Return(purchase, nil)
+
+		tuow := &MockTransactionUnitOfWork{PurchaseRepo: purchaseRepo}
+		service := NewFulfillmentService(&MockUnitOfWork{transactionUnitOfWork: tuow})
+
+		_, err := service.AdvanceOrder(9, entity.FulfillmentDelivered)
+		assert.EqualError(t, err, "order is cancelled")
+		assert.True(t, tuow.rollbackCalled)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		purchase := &entity.Purchase{Model: gorm.Model{ID: 9}, FulfillmentStatus: entity.FulfillmentPlaced}
+
+		purchaseRepo := &MockPurchaseRepository{}
+		purchaseRepo.On("FindPurchaseById", uint(9)).Return(purchase, nil)
+		purchaseRepo.On("UpdatePurchase", purchase).Return(nil)
+
+		tuow := &MockTransactionUnitOfWork{PurchaseRepo: purchaseRepo}
+		service := NewFulfillmentService(&MockUnitOfWork{transactionUnitOfWork: tuow})
+		service.now = func() time.Time { return now }
+
+		res, err := service.AdvanceOrder(9, entity.FulfillmentPacked)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.FulfillmentPacked, res.Status)
+		assert.Equal(t, now, *purchase.PackedAt)
+		assert.True(t, tuow.commitCalled)
+	})
+}
+
+func TestFulfillmentService_CancelOrder(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("AlreadyDelivered", func(t *testing.T) {
+		purchase := &entity.Purchase{Model: gorm.Model{ID: 9}, FulfillmentStatus: entity.FulfillmentDelivered}
+
+		purchaseRepo := &MockPurchaseRepository{}
+		purchaseRepo.On("FindPurchaseById", uint(9)).Return(purchase, nil)
+
+		tuow := &MockTransactionUnitOfWork{PurchaseRepo: purchaseRepo}
+		service := NewFulfillmentService(&MockUnitOfWork{transactionUnitOfWork: tuow})
+
+		_, err := service.CancelOrder(5, 9)
+		assert.EqualError(t, err, "order is already delivered")
+		assert.True(t, tuow.rollbackCalled)
+	})
+
+	t.Run("RefundsCoins", func(t *testing.T) {
+		buyer := &entity.User{Model: gorm.Model{ID: 1}, Balance: 0}
+		purchase := &entity.Purchase{Model: gorm.Model{ID: 9}, BuyerId: 1, OwnerId: 1, ItemID: 3, Price: 50,
+			FulfillmentStatus: entity.FulfillmentPacked}
+
+		userRepo := &MockUserRepository{}
+		userRepo.On("FindUserById", uint(1)).Return(buyer, nil)
+		userRepo.On("UpdateUser", buyer).Return(nil)
+
+		transactionRepo := &MockTransactionRepository{}
+		transactionRepo.On("RemoveItems", uint(1), uint(3), uint(0), uint(1)).Return(true, nil)
+
+		purchaseRepo := &MockPurchaseRepository{}
+		purchaseRepo.On("FindPurchaseById", uint(9)).Return(purchase, nil)
+		purchaseRepo.On("UpdatePurchase", purchase).Return(nil)
+
+		itemRepo := &MockItemRepository{}
+		itemRepo.On("AddStock", uint(3), uint(1)).Return(nil)
+
+		tuow := &MockTransactionUnitOfWork{
+			UserRepo:        userRepo,
+			TransactionRepo: transactionRepo,
+			ItemRepo:        itemRepo,
+			PurchaseRepo:    purchaseRepo,
+		}
+		service := NewFulfillmentService(&MockUnitOfWork{transactionUnitOfWork: tuow})
+		service.now = func() time.Time { return now }
+
+		refund, err := service.CancelOrder(5, 9)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(50), refund.Amount)
+		assert.Equal(t, uint(50), buyer.Balance)
+		assert.Equal(t, entity.FulfillmentCancelled, purchase.FulfillmentStatus)
+		assert.Equal(t, now, *purchase.CancelledAt)
+		assert.True(t, tuow.commitCalled)
+	})
+}
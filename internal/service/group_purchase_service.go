@@ -0,0 +1,372 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"merch_shop/internal/config"
+	"merch_shop/internal/entity"
+	"merch_shop/internal/model"
+	"merch_shop/internal/repository"
+	"time"
+)
+
+type GroupPurchaseService struct {
+	uow   repository.UnitOfWork
+	rules []config.AchievementRule
+	now   func() time.Time
+}
+
+func NewGroupPurchaseService(uow repository.UnitOfWork, rules []config.AchievementRule) *GroupPurchaseService {
+	return &GroupPurchaseService{uow: uow, rules: rules, now: time.Now}
+}
+
+func (g GroupPurchaseService) GetGroupPurchases() ([]model.GroupPurchase, error) {
+	groupPurchases, err := g.uow.GroupPurchaseRepository().GetOpenGroupPurchases()
+	if err != nil {
+		return nil, fmt.Errorf("error getting group purchases")
+	}
+	groupPurchasesModel := make([]model.GroupPurchase, 0, len(groupPurchases))
+	for _, v := range groupPurchases {
+		groupPurchasesModel = append(groupPurchasesModel, toGroupPurchaseModel(v))
+	}
+	return groupPurchasesModel, nil
+}
+
+func (g GroupPurchaseService) GetGroupPurchase(groupPurchaseId uint) (model.GroupPurchase, error) {
+	groupPurchase, err := g.uow.GroupPurchaseRepository().FindGroupPurchaseById(groupPurchaseId)
+	if err != nil {
+		return model.GroupPurchase{}, fmt.Errorf("failed to find group purchase")
+	}
+	if groupPurchase == nil {
+		return model.GroupPurchase{}, fmt.Errorf("group purchase not found")
+	}
+	return toGroupPurchaseModel(*groupPurchase), nil
+}
+
+// CreateGroupPurchase starts collecting pledges for an item for the recipient.
+// The price in effect now is the amount to collect.
+func (g GroupPurchaseService) CreateGroupPurchase(userId uint, request model.GroupPurchaseRequest) (model.GroupPurchase, error) {
+	now := g.now()
+	if !request.Deadline.After(now) {
+		return model.GroupPurchase{}, fmt.Errorf("deadline must be in the future")
+	}
+
+	tx, err := g.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return model.GroupPurchase{}, fmt.Errorf("failed to begin transaction")
+	}
+
+	userRepository := tx.UserRepository()
+	organizer, err := userRepository.FindUserById(userId)
+	if err != nil {
+		tx.Rollback()
+		return model.GroupPurchase{}, fmt.Errorf("failed to find user")
+	}
+	if organizer == nil {
+		tx.Rollback()
+		return model.GroupPurchase{}, fmt.Errorf("user not found")
+	}
+	recipient, err := userRepository.FindUserByName(request.ToUser)
+	if err != nil {
+		tx.Rollback()
+		return model.GroupPurchase{}, fmt.Errorf("failed to find user")
+	}
+	if recipient == nil {
+		tx.Rollback()
+		return model.GroupPurchase{}, fmt.Errorf("user not found")
+	}
+	item, variant, err := findItem(tx, request.Item, request.Variant)
+	if err != nil {
+		tx.Rollback()
+		return model.GroupPurchase{}, err
+	}
+	if err := checkAvailable(tx, item, variant); err != nil {
+		tx.Rollback()
+		return model.GroupPurchase{}, err
+	}
+	if err := checkPurchaseLimit(tx, recipient.ID, item); err != nil {
+		tx.Rollback()
+		return model.GroupPurchase{}, err
+	}
+	basePrice, err := effectivePrice(tx, item, now)
+	if err != nil {
+		tx.Rollback()
+		return model.GroupPurchase{}, err
+	}
+	price := variantPrice(basePrice, variant)
+	if price == 0 {
+		tx.Rollback()
+		return model.GroupPurchase{}, fmt.Errorf("item is free")
+	}
+
+	groupPurchase := entity.GroupPurchase{
+		OrganizerId: organizer.ID,
+		Organizer:   *organizer,
+		RecipientId: recipient.ID,
+		Recipient:   *recipient,
+		ItemID:      item.ID,
+		Item:        *item,
+		VariantID:   variantId(variant),
+		Price:       price,
+		Status:      entity.GroupPurchaseOpen,
+		Deadline:    request.Deadline,
+	}
+	if variant != nil {
+		groupPurchase.Variant = *variant
+	}
+	if err := tx.GroupPurchaseRepository().CreateGroupPurchase(&groupPurchase); err != nil {
+		tx.Rollback()
+		return model.GroupPurchase{}, fmt.Errorf("failed to create group purchase")
+	}
+
+	tx.Commit()
+	return toGroupPurchaseModel(groupPurchase), nil
+}
+
+// Pledge moves coins from the user into the group purchase. The pledge that completes
+// the price buys the item for the recipient.
+func (g GroupPurchaseService) Pledge(userId uint, groupPurchaseId uint, amount uint) (model.GroupPurchase, error) {
+	if amount == 0 {
+		return model.GroupPurchase{}, fmt.Errorf("amount must be positive")
+	}
+	now := g.now()
+
+	tx, err := g.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return model.GroupPurchase{}, fmt.Errorf("failed to begin transaction")
+	}
+
+	groupPurchase, err := findOpenGroupPurchase(tx, groupPurchaseId)
+	if err != nil {
+		tx.Rollback()
+		return model.GroupPurchase{}, err
+	}
+	if !groupPurchase.Deadline.After(now) {
+		tx.Rollback()
+		return model.GroupPurchase{}, fmt.Errorf("group purchase deadline has passed")
+	}
+	if remaining := groupPurchase.Price - groupPurchase.Collected; amount > remaining {
+		tx.Rollback()
+		return model.GroupPurchase{}, fmt.Errorf("only %d coins are left to collect", remaining)
+	}
+	user, err := tx.UserRepository().FindUserById(userId)
+	if err != nil {
+		tx.Rollback()
+		return model.GroupPurchase{}, fmt.Errorf("failed to find user")
+	}
+	if user == nil {
+		tx.Rollback()
+		return model.GroupPurchase{}, fmt.Errorf("user not found")
+	}
+	if user.Balance < amount {
+		tx.Rollback()
+		return model.GroupPurchase{}, fmt.Errorf("insufficient balance")
+	}
+	user.Balance -= amount
+	if err := tx.UserRepository().UpdateUser(user); err != nil {
+		tx.Rollback()
+		return model.GroupPurchase{}, fmt.Errorf("failed to update user")
+	}
+	pledge := entity.Pledge{
+		GroupPurchaseID: groupPurchase.ID,
+		UserID:          user.ID,
+		User:            *user,
+		Amount:          amount,
+	}
+	if err := tx.GroupPurchaseRepository().CreatePledge(&pledge); err != nil {
+		tx.Rollback()
+		return model.GroupPurchase{}, fmt.Errorf("failed to create pledge")
+	}
+	groupPurchase.Pledges = append(groupPurchase.Pledges, pledge)
+	groupPurchase.Collected += amount
+	if groupPurchase.Collected == groupPurchase.Price {
+		if err := completeGroupPurchase(tx, groupPurchase, now); err != nil {
+			tx.Rollback()
+			return model.GroupPurchase{}, err
+		}
+		// The purchase is made by the organizer, who is loaded afresh unless they made the last pledge.
+		organizer := user
+		if groupPurchase.OrganizerId != user.ID {
+			organizer, err = tx.UserRepository().FindUserById(groupPurchase.OrganizerId)
+			if err != nil || organizer == nil {
+				tx.Rollback()
+				return model.GroupPurchase{}, fmt.Errorf("failed to find user")
+			}
+		}
+		if _, err := awardAchievements(tx, g.rules, organizer, now); err != nil {
+			tx.Rollback()
+			return model.GroupPurchase{}, err
+		}
+	}
+	if err := tx.GroupPurchaseRepository().UpdateGroupPurchase(groupPurchase); err != nil {
+		tx.Rollback()
+		return model.GroupPurchase{}, fmt.Errorf("failed to update group purchase")
+	}
+
+	tx.Commit()
+	return toGroupPurchaseModel(*groupPurchase), nil
+}
+
+// CancelGroupPurchase lets the organizer call off a group purchase and returns all pledges.
+func (g GroupPurchaseService) CancelGroupPurchase(userId uint, groupPurchaseId uint) error {
+	tx, err := g.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction")
+	}
+
+	groupPurchase, err := findOpenGroupPurchase(tx, groupPurchaseId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if groupPurchase.OrganizerId != userId {
+		tx.Rollback()
+		return fmt.Errorf("only the organizer can cancel a group purchase")
+	}
+	if err := closeGroupPurchase(tx, groupPurchase, entity.GroupPurchaseCancelled); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	tx.Commit()
+	return nil
+}
+
+// ExpireGroupPurchases returns the pledges of open group purchases whose deadline has passed
+// and reports how many group purchases were expired.
+func (g GroupPurchaseService) ExpireGroupPurchases() (int, error) {
+	tx, err := g.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction")
+	}
+
+	groupPurchases, err := tx.GroupPurchaseRepository().GetOverdueGroupPurchases(g.now())
+	if err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("error getting group purchases")
+	}
+	for i := range groupPurchases {
+		if err := closeGroupPurchase(tx, &groupPurchases[i], entity.GroupPurchaseExpired); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+
+	tx.Commit()
+	return len(groupPurchases), nil
+}
+
+func findOpenGroupPurchase(tx repository.UnitOfWork, groupPurchaseId uint) (*entity.GroupPurchase, error) {
+	groupPurchase, err := tx.GroupPurchaseRepository().FindGroupPurchaseById(groupPurchaseId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find group purchase")
+	}
+	if groupPurchase == nil {
+		return nil, fmt.Errorf("group purchase not found")
+	}
+	if groupPurchase.Status != entity.GroupPurchaseOpen {
+		return nil, fmt.Errorf("group purchase is already %s", groupPurchase.Status)
+	}
+	return groupPurchase, nil
+}
+
+// completeGroupPurchase buys the item for the recipient with the collected coins.
+// The caller owns tx and saves groupPurchase afterwards.
+func completeGroupPurchase(tx repository.UnitOfWork, groupPurchase *entity.GroupPurchase, now time.Time) error {
+	transactionRepository := tx.TransactionRepository()
+	var variant *entity.ItemVariant
+	if groupPurchase.VariantID != 0 {
+		variant = &groupPurchase.Variant
+	}
+	// The recipient may have bought the item up to its limit while pledges were collected.
+	if err := checkPurchaseLimit(tx, groupPurchase.RecipientId, &groupPurchase.Item); err != nil {
+		return err
+	}
+	if err := takeStock(tx, &groupPurchase.Item, variant); err != nil {
+		return err
+	}
+	if err := transactionRepository.AddItems(groupPurchase.RecipientId, groupPurchase.ItemID, groupPurchase.VariantID, 1); err != nil {
+		return fmt.Errorf("failed to add item to inventory: %v", err)
+	}
+	err := tx.PurchaseRepository().CreatePurchase(&entity.Purchase{
+		BuyerId:           groupPurchase.OrganizerId,
+		OwnerId:           groupPurchase.RecipientId,
+		ItemID:            groupPurchase.ItemID,
+		VariantID:         groupPurchase.VariantID,
+		Price:             groupPurchase.Price,
+		FulfillmentStatus: entity.FulfillmentPlaced,
+		GroupPurchaseID:   &groupPurchase.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create purchase")
+	}
+	if groupPurchase.OrganizerId != groupPurchase.RecipientId {
+		err = transactionRepository.CreateItemTransfer(&entity.ItemTransfer{
+			FromId:    groupPurchase.OrganizerId,
+			ToId:      groupPurchase.RecipientId,
+			ItemID:    groupPurchase.ItemID,
+			VariantID: groupPurchase.VariantID,
+			Quantity:  1,
+			Gift:      true,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create item transfer")
+		}
+	}
+	groupPurchase.Status = entity.GroupPurchaseCompleted
+	groupPurchase.CompletedAt = &now
+	return nil
+}
+
+// closeGroupPurchase returns the pledges and leaves the group purchase in the given status.
+func closeGroupPurchase(tx repository.UnitOfWork, groupPurchase *entity.GroupPurchase, status string) error {
+	if err := returnPledges(tx, groupPurchase); err != nil {
+		return err
+	}
+	groupPurchase.Status = status
+	if err := tx.GroupPurchaseRepository().UpdateGroupPurchase(groupPurchase); err != nil {
+		return fmt.Errorf("failed to update group purchase")
+	}
+	return nil
+}
+
+// returnPledges credits every pledge of the group purchase back to the user who made it.
+func returnPledges(tx repository.UnitOfWork, groupPurchase *entity.GroupPurchase) error {
+	userRepository := tx.UserRepository()
+	for _, v := range groupPurchase.Pledges {
+		user, err := userRepository.FindUserById(v.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to find user")
+		}
+		if user == nil {
+			return fmt.Errorf("user not found")
+		}
+		user.Balance += v.Amount
+		if err := userRepository.UpdateUser(user); err != nil {
+			return fmt.Errorf("failed to update user")
+		}
+	}
+	return nil
+}
+
+func toGroupPurchaseModel(groupPurchase entity.GroupPurchase) model.GroupPurchase {
+	pledges := make([]model.Pledge, 0, len(groupPurchase.Pledges))
+	for _, v := range groupPurchase.Pledges {
+		pledges = append(pledges, model.Pledge{
+			User:   v.User.Name,
+			Amount: v.Amount,
+		})
+	}
+	return model.GroupPurchase{
+		Id:        groupPurchase.ID,
+		Item:      groupPurchase.Item.Name,
+		Variant:   groupPurchase.Variant.Name,
+		Organizer: groupPurchase.Organizer.Name,
+		Recipient: groupPurchase.Recipient.Name,
+		Price:     groupPurchase.Price,
+		Collected: groupPurchase.Collected,
+		Status:    groupPurchase.Status,
+		Deadline:  groupPurchase.Deadline,
+		Pledges:   pledges,
+	}
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"testing"
+	"time"
+)
+
+type MockGroupPurchaseRepository struct {
+	mock.Mock
+}
+
+func (m *MockGroupPurchaseRepository) CreateGroupPurchase(groupPurchase *entity.GroupPurchase) error {
+	args := m.Called(groupPurchase)
+	return args.Error(0)
+}
+
+func (m *MockGroupPurchaseRepository) UpdateGroupPurchase(groupPurchase *entity.GroupPurchase) error {
+	args := m.Called(groupPurchase)
+	return args.Error(0)
+}
+
+func (m *MockGroupPurchaseRepository) FindGroupPurchaseById(groupPurchaseId uint) (*entity.GroupPurchase, error) {
+	args := m.Called(groupPurchaseId)
+	return args.Get(0).(*entity.GroupPurchase), args.Error(1)
+}
+
+func (m *MockGroupPurchaseRepository) GetOpenGroupPurchases() ([]entity.GroupPurchase, error) {
+	args := m.Called()
+	return args.Get(0).([]entity.GroupPurchase), args.Error(1)
+}
+
+func (m *MockGroupPurchaseRepository) GetOverdueGroupPurchases(now time.Time) ([]entity.GroupPurchase, error) {
+	args := m.Called(now)
+	return args.Get(0).([]entity.GroupPurchase), args.Error(1)
+}
+
+func (m *MockGroupPurchaseRepository) CreatePledge(pledge *entity.Pledge) error {
+	args := m.Called(pledge)
+	return args.Error(0)
+}
+
+func TestGroupPurchaseService_Pledge(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	newGroupPurchase := func() *entity.GroupPurchase {
+		return &entity.GroupPurchase{
+			Model:       gorm.Model{ID: 7},
+			OrganizerId: 1,
+			RecipientId: 3,
+			ItemID:      4,
+			Item:        entity.Item{Model: gorm.Model{ID: 4}, Name: "hoody"},
+			Price:       300,
+			Collected:   200,
+			Status:      entity.GroupPurchaseOpen,
+			Deadline:    now.Add(time.Hour),
+			Pledges:     []entity.Pledge{{UserID: 1, Amount: 200}},
+		}
+	}
+
+	t.Run("ExceedsRemaining", func(t *testing.T) {
+		groupPurchaseRepo := &MockGroupPurchaseRepository{}
+		groupPurchaseRepo.On("FindGroupPurchaseById", uint(7)).Return(newGroupPurchase(), nil)
+
+		tuow := &MockTransactionUnitOfWork{GroupPurchaseRepo: groupPurchaseRepo}
+		service := NewGroupPurchaseService(&MockUnitOfWork{transactionUnitOfWork: tuow}, nil)
+		service.now = func() time.Time { return now }
+
+		_, err := service.Pledge(2, 7, 150)
+		assert.EqualError(t, err, "only 100 coins are left to collect")
+		assert.True(t, tuow.rollbackCalled)
+	})
+
+	t.Run("DeadlinePassed", func(t *testing.T) {
+		groupPurchaseRepo := &MockGroupPurchaseRepository{}
+		groupPurchaseRepo.On("FindGroupPurchaseById", uint(7)).Return(newGroupPurchase(), nil)
+
+		tuow := &MockTransactionUnitOfWork{GroupPurchaseRepo: groupPurchaseRepo}
+		service := NewGroupPurchaseService(&MockUnitOfWork{transactionUnitOfWork: tuow}, nil)
+		service.now = func() time.Time { return now.Add(time.Hour) }
+
+		_, err := service.Pledge(2, 7, 100)
+		assert.EqualError(t, err, "group purchase deadline has passed")
+	})
+
+	t.Run("CompletesPurchase", func(t *testing.T) {
+		user := &entity.User{Model: gorm.Model{ID: 2}, Name: "bob", Balance: 500}
+
+		userRepo := &MockUserRepository{}
+		userRepo.On("FindUserById", uint(1)).Return(&entity.User{Model: gorm.Model{ID: 1}, Name: "alice"}, nil)
+		userRepo.On("FindUserById", uint(2)).Return(user, nil)
+		userRepo.On("UpdateUser", user).Return(nil)
+
+		transactionRepo := &MockTransactionRepository{}
+		transactionRepo.On("AddItems", uint(3), uint(4), uint(0), uint(1)).Return(nil)
+		transactionRepo.On("CreateItemTransfer", mock.MatchedBy(func(transfer *entity.ItemTransfer) bool {
+			return transfer.FromId == 1 && transfer.ToId == 3 && transfer.Gift
+		})).Return(nil)
+
+		purchaseRepo := &MockPurchaseRepository{}
+		purchaseRepo.On("CreatePurchase", mock.MatchedBy(func(purchase *entity.Purchase) bool {
+			return purchase.OwnerId == 3 && purchase.Price == 300 && *purchase.GroupPurchaseID == 7
+		})).Return(nil)
+
+		groupPurchaseRepo := &MockGroupPurchaseRepository{}
+		groupPurchaseRepo.On("FindGroupPurchaseById", uint(7)).Return(newGroupPurchase(), nil)
+		groupPurchaseRepo.On("CreatePledge", mock.MatchedBy(func(pledge *entity.Pledge) bool {
+			return pledge.GroupPurchaseID == 7 && pledge.UserID == 2 && pledge.Amount == 100
+		})).Return(nil)
+		groupPurchaseRepo.On("UpdateGroupPurchase", mock.Anything).Return(nil)
+
+		tuow := &MockTransactionUnitOfWork{
+			UserRepo:          userRepo,
+			TransactionRepo:   transactionRepo,
+			PurchaseRepo:      purchaseRepo,
+			GroupPurchaseRepo: groupPurchaseRepo,
+		}
+		service := NewGroupPurchaseService(&MockUnitOfWork{transactionUnitOfWork: tuow}, nil)
+		service.now = func() time.Time { return now }
+
+		res, err := service.Pledge(2, 7, 100)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.GroupPurchaseCompleted, res.Status)
+		assert.Equal(t, uint(300), res.Collected)
+		assert.Equal(t, uint(400), user.Balance)
+		purchaseRepo.AssertExpectations(t)
+		transactionRepo.AssertExpectations(t)
+		assert.True(t, tuow.commitCalled)
+	})
+
+	t.Run("RecipientReachedPurchaseLimit", func(t *testing.T) {
+		user := &entity.User{Model: gorm.Model{ID: 2}, Name: "bob", Balance: 500}
+		groupPurchase := newGroupPurchase()
+		groupPurchase.Item.PurchaseLimit = 1
+
+		userRepo := &MockUserRepository{}
+		userRepo.On("FindUserById", uint(2)).Return(user, nil)
+		userRepo.On("UpdateUser", user).Return(nil)
+
+		purchaseRepo := &MockPurchaseRepository{}
+		purchaseRepo.On("CountActivePurchases", uint(3), uint(4)).Return(int64(1), nil)
+
+		groupPurchaseRepo := &MockGroupPurchaseRepository{}
+		groupPurchaseRepo.On("FindGroupPurchaseById", uint(7)).Return(groupPurchase, nil)
+		groupPurchaseRepo.On("CreatePledge", mock.Anything).Return(nil)
+
+		tuow := &MockTransactionUnitOfWork{
+			UserRepo:          userRepo,
+			TransactionRepo:   &MockTransactionRepository{},
+			PurchaseRepo:      purchaseRepo,
+			GroupPurchaseRepo: groupPurchaseRepo,
+		}
+		service := NewGroupPurchaseService(&MockUnitOfWork{transactionUnitOfWork: tuow}, nil)
+		service.now = func() time.Time { return now }
+
+		_, err := service.Pledge(2, 7, 100)
+		assert.EqualError(t, err, "purchase limit for this item is reached")
+		purchaseRepo.AssertNotCalled(t, "CreatePurchase", mock.Anything)
+		assert.True(t, tuow.rollbackCalled)
+	})
+}
+
+func TestGroupPurchaseService_ExpireGroupPurchases(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	alice := &entity.User{Model: gorm.Model{ID: 1}, Name: "alice", Balance: 100}
+	bob := &entity.User{Model: gorm.Model{ID: 2}, Name: "bob", Balance: 0}
+	groupPurchase := entity.GroupPurchase{
+		Model:   gorm.Model{ID: 7},
+		Price:   300,
+		Status:  entity.GroupPurchaseOpen,
+		Pledges: []entity.Pledge{{UserID: 1, Amount: 50}, {UserID: 2, Amount: 70}, {UserID: 1, Amount: 30}},
+	}
+
+	userRepo := &MockUserRepository{}
+	userRepo.On("FindUserById", uint(1)).Return(alice, nil)
+	userRepo.On("FindUserById", uint(2)).Return(bob, nil)
+	userRepo.On("UpdateUser", mock.Anything).Return(nil)
+
+	groupPurchaseRepo := &MockGroupPurchaseRepository{}
+	groupPurchaseRepo.On("GetOverdueGroupPurchases", now).Return([]entity.GroupPurchase{groupPurchase}, nil)
+	groupPurchaseRepo.On("UpdateGroupPurchase", mock.MatchedBy(func(groupPurchase *entity.GroupPurchase) bool {
+		return groupPurchase.Status == entity.GroupPurchaseExpired
+	})).Return(nil)
+
+	tuow := &MockTransactionUnitOfWork{UserRepo: userRepo, GroupPurchaseRepo: groupPurchaseRepo}
+	service := NewGroupPurchaseService(&MockUnitOfWork{transactionUnitOfWork: tuow}, nil)
+	service.now = func() time.Time { return now }
+
+	expired, err := service.ExpireGroupPurchases()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, expired)
+	assert.Equal(t, uint(180), alice.Balance)
+	assert.Equal(t, uint(70), bob.Balance)
+	groupPurchaseRepo.AssertExpectations(t)
+	assert.True(t, tuow.commitCalled)
+}
+
+func TestGroupPurchaseService_CancelGroupPurchase(t *testing.T) {
+	groupPurchaseRepo := &MockGroupPurchaseRepository{}
+	groupPurchaseRepo.On("FindGroupPurchaseById", uint(7)).Return(&entity.GroupPurchase{
+		Model:       gorm.Model{ID: 7},
+		OrganizerId: 1,
+		Status:      entity.GroupPurchaseOpen,
+	}, nil)
+
+	tuow := &MockTransactionUnitOfWork{GroupPurchaseRepo: groupPurchaseRepo}
+	service := NewGroupPurchaseService(&MockUnitOfWork{transactionUnitOfWork: tuow}, nil)
+
+	err := service.CancelGroupPurchase(2, 7)
+	assert.EqualError(t, err, "only the organizer can cancel a group purchase")
+	groupPurchaseRepo.AssertNotCalled(t, "UpdateGroupPurchase", mock.Anything)
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"fmt"
+	"merch_shop/internal/config"
+	"merch_shop/internal/entity"
+	"merch_shop/internal/provider"
+	"merch_shop/internal/repository"
+	"slices"
+	"strings"
+)
+
+// ldapRoles lists the roles LDAP groups can be mapped to. Service accounts only sign in with API keys.
+var ldapRoles = []string{entity.RoleUser, entity.RoleAdmin, entity.RoleFulfillment}
+
+type ldapDirectory interface {
+	Authenticate(username string, password string) (*provider.LDAPUser, error)
+}
+
+type ldapGroupRole struct {
+	group string
+	role  string
+}
+
+// LDAPAuthenticator checks passwords with a bind against the LDAP directory. Users are registered on
+// their first sign-in, and their role follows their directory groups on every sign-in.
+type LDAPAuthenticator struct {
+	uow         repository.UnitOfWork
+	directory   ldapDirectory
+	groupRoles  []ldapGroupRole
+	defaultRole string
+}
+
+func NewLDAPAuthenticator(uow repository.UnitOfWork, directory ldapDirectory, cfg config.LDAP) (LDAPAuthenticator, error) {
+	var groupRoles []ldapGroupRole
+	for _, v := range strings.Split(cfg.GroupRoles, ";") {
+		if strings.TrimSpace(v) == "" {
+			continue
+		}
+		role, group, found := strings.Cut(v, "=")
+		role, group = strings.TrimSpace(role), strings.TrimSpace(group)
+		if !found || group == "" {
+			return LDAPAuthenticator{}, fmt.Errorf("ldap group role %q is not role=group DN", v)
+		}
+		if !slices.Contains(ldapRoles, role) {
+			return LDAPAuthenticator{}, fmt.Errorf("ldap group role %q has unknown role %q", v, role)
+		}
+		groupRoles = append(groupRoles, ldapGroupRole{group: group, role: role})
+	}
+	if cfg.DefaultRole != "" && !slices.Contains(ldapRoles, cfg.DefaultRole) {
+		return LDAPAuthenticator{}, fmt.Errorf("ldap default role %q is unknown", cfg.DefaultRole)
+	}
+	return LDAPAuthenticator{uow: uow, directory: directory, groupRoles: groupRoles, defaultRole: cfg.DefaultRole}, nil
+}
+
+func (l LDAPAuthenticator) Authenticate(username string, password string) (*entity.User, error) {
+	entry, err := l.directory.Authenticate(username, password)
+	if err != nil {
+		return nil, fmt.Errorf("directory is unavailable")
+	}
+	if entry == nil {
+		return nil, ErrInvalidCredentials
+	}
+	role := l.role(entry.Groups)
+	if role == "" {
+		return nil, fmt.Errorf("user is not permitted to sign in")
+	}
+	if entry.Username != "" {
+		username = entry.Username
+	}
+
+	userRepository := l.uow.UserRepository()
+	user, err := userRepository.FindUserByName(username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user by username %s", username)
+	}
+	if user == nil {
+		user = &entity.User{
+			Name:        username,
+			DisplayName: entry.DisplayName,
+			Email:       entry.Email,
+			Balance:     START_BALANCE,
+			Role:        role,
+		}
+		if err := userRepository.CreateUser(user); err != nil {
+			return nil, fmt.Errorf("failed to create user")
+		}
+		return user, nil
+	}
+	if user.Role == entity.RoleService {
+		return nil, ErrInvalidCredentials
+	}
+	if user.Role != role || (entry.Email != "" && user.Email != entry.Email) {
+		if user.Role != role {
+			// Tokens carry the role, so those issued with the old one must stop working.
+			user.SessionVersion++
+		}
+		user.Role = role
+		if entry.Email != "" {
+			user.Email = entry.Email
+		}
+		if err := userRepository.UpdateUserColumns(user, "role", "email", "session_version"); err != nil {
+			return nil, fmt.Errorf("failed to update user")
+		}
+	}
+	return user, nil
+}
+
+func (l LDAPAuthenticator) VerifyPassword(user *entity.User, password string) (bool, error) {
+	entry, err := l.directory.Authenticate(user.Name, password)
+	if err != nil {
+		return false, fmt.Errorf("directory is unavailable")
+	}
+	return entry != nil, nil
+}
+
+func (l LDAPAuthenticator) ManagesPasswords() bool {
+	return false
+}
+
+// role returns the role of the first configured group the user is a member of, or the default role.
+func (l LDAPAuthenticator) role(groups []string) string {
+	for _, v := range l.groupRoles {
+		for _, group := range groups {
+			if strings.EqualFold(v.group, group) {
+				return v.role
+			}
+		}
+	}
+	return l.defaultRole
+}
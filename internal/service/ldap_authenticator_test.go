@@ -0,0 +1,176 @@
+package service
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+	"merch_shop/internal/config"
+	"merch_shop/internal/entity"
+	"merch_shop/internal/provider"
+	"testing"
+	"time"
+)
+
+type MockLDAPDirectory struct {
+	mock.Mock
+}
+
+func (m *MockLDAPDirectory) Authenticate(username string, password string) (*provider.LDAPUser, error) {
+	args := m.Called(username, password)
+	return args.Get(0).(*provider.LDAPUser), args.Error(1)
+}
+
+const (
+	testAdminsGroup    = "cn=shop-admins,ou=groups,dc=example,dc=com"
+	testWarehouseGroup = "cn=warehouse,ou=groups,dc=example,dc=com"
+)
+
+var testLDAPConfig = config.LDAP{
+	GroupRoles:  "admin=" + testAdminsGroup + "; fulfillment=" + testWarehouseGroup,
+	DefaultRole: entity.RoleUser,
+}
+
+func TestNewLDAPAuthenticator(t *testing.T) {
+	for groupRoles, expected := range map[string]string{
+		"":                           "",
+		"admin=" + testAdminsGroup:   "",
+		"admin":                      `ldap group role "admin" is not role=group DN`,
+		"service=" + testAdminsGroup: `ldap group role "service=` + testAdminsGroup + `" has unknown role "service"`,
+	} {
+		_, err := NewLDAPAuthenticator(&MockAuthUnitOfWork{}, &MockLDAPDirectory{}, config.LDAP{GroupRoles: groupRoles})
+		if expected == "" {
+			assert.NoError(t, err, groupRoles)
+		} else {
+			assert.EqualError(t, err, expected)
+		}
+	}
+	_, err := NewLDAPAuthenticator(&MockAuthUnitOfWork{}, &MockLDAPDirectory{}, config.LDAP{DefaultRole: "root"})
+	assert.EqualError(t, err, `ldap default role "root" is unknown`)
+}
+
+func TestLDAPAuthenticator_Authenticate(t *testing.T) {
+	alice := &provider.LDAPUser{
+		DN:          "uid=alice,ou=people,dc=example,dc=com",
+		DisplayName: "Alice Smith",
+		Email:       "alice@example.com",
+		Username:    "alice",
+		Groups:      []string{"cn=staff,ou=groups,dc=example,dc=com", testWarehouseGroup, testAdminsGroup},
+	}
+	setup := func(cfg config.LDAP) (*MockAuthUserRepository, LDAPAuthenticator) {
+		userRepo := &MockAuthUserRepository{}
+		directory := &MockLDAPDirectory{}
+		directory.On("Authenticate", "Alice", "secret").Return(alice, nil)
+		directory.On("Authenticate", "alice", "wrong").Return((*provider.LDAPUser)(nil), nil)
+		directory.On("Authenticate", "bob", "secret").Return(&provider.LDAPUser{Username: "bob"}, nil)
+		directory.On("Authenticate", "carol", "secret").Return((*provider.LDAPUser)(nil), errors.New("connection refused"))
+		authenticator, err := NewLDAPAuthenticator(&MockAuthUnitOfWork{userRepo: userRepo}, directory, cfg)
+		assert.NoError(t, err)
+		return userRepo, authenticator
+	}
+
+	t.Run("NewUserGetsRoleOfFirstListedGroup", func(t *testing.T) {
+		userRepo, authenticator := setup(testLDAPConfig)
+		userRepo.On("FindUserByName", "alice").Return((*entity.User)(nil), nil)
+		userRepo.On("CreateUser", mock.AnythingOfType("*entity.User")).Return(nil)
+
+		user, err := authenticator.Authenticate("Alice", "secret")
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", user.Name)
+		assert.Equal(t, entity.RoleAdmin, user.Role)
+		assert.Equal(t, "Alice Smith", user.DisplayName)
+		assert.Equal(t, "alice@example.com", user.Email)
+		assert.Equal(t, uint(START_BALANCE), user.Balance)
+		assert.Empty(t, user.PasswordHash)
+	})
+
+	t.Run("RoleChangeEndsSessions", func(t *testing.T) {
+		userRepo, authenticator := setup(testLDAPConfig)
+		existing := &entity.User{Model: gorm.Model{ID: 1}, Name: "alice", Role: entity.RoleUser, SessionVersion: 2, DisplayName: "Ali"}
+		userRepo.On("FindUserByName", "alice").Return(existing, nil)
+		userRepo.On("UpdateUserColumns", existing, []string{"role", "email", "session_version"}).Return(nil)
+
+		user, err := authenticator.Authenticate("Alice", "secret")
+		assert.NoError(t, err)
+		assert.Equal(t, entity.RoleAdmin, user.Role)
+		assert.Equal(t, uint(3), user.SessionVersion)
+		assert.Equal(t, "Ali", user.DisplayName)
+		userRepo.AssertCalled(t, "UpdateUserColumns", existing, []string{"role", "email", "session_version"})
+	})
+
+	t.Run("UnchangedUserIsNotUpdated", func(t *testing.T) {
+		userRepo, authenticator := setup(testLDAPConfig)
+		existing := &entity.User{Model: gorm.Model{ID: 1}, Name: "alice", Role: entity.RoleAdmin, Email: "alice@example.com"}
+		userRepo.On("FindUserByName", "alice").Return(existing, nil)
+
+		_, err := authenticator.Authenticate("Alice", "secret")
+		assert.NoError(t, err)
+		userRepo.AssertNotCalled(t, "UpdateUserColumns", mock.Anything, mock.Anything)
+	})
+
+	t.Run("DefaultRole", func(t *testing.T) {
+		userRepo, authenticator := setup(testLDAPConfig)
+		userRepo.On("FindUserByName", "bob").Return((*entity.User)(nil), nil)
+		userRepo.On("CreateUser", mock.AnythingOfType("*entity.User")).Return(nil)
+
+		user, err := authenticator.Authenticate("bob", "secret")
+		assert.NoError(t, err)
+		assert.Equal(t, entity.RoleUser, user.Role)
+	})
+
+	t.Run("NoDefaultRole", func(t *testing.T) {
+		cfg := testLDAPConfig
+		cfg.DefaultRole = ""
+		_, authenticator := setup(cfg)
+
+		_, err := authenticator.Authenticate("bob", "secret")
+		assert.EqualError(t, err, "user is not permitted to sign in")
+	})
+
+	t.Run("WrongPassword", func(t *testing.T) {
+		_, authenticator := setup(testLDAPConfig)
+		_, err := authenticator.Authenticate("alice", "wrong")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("ServiceAccount", func(t *testing.T) {
+		userRepo, authenticator := setup(testLDAPConfig)
+		userRepo.On("FindUserByName", "bob").Return(&entity.User{Name: "bob", Role: entity.RoleService}, nil)
+
+		_, err := authenticator.Authenticate("bob", "secret")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("DirectoryUnavailable", func(t *testing.T) {
+		_, authenticator := setup(testLDAPConfig)
+		_, err := authenticator.Authenticate("carol", "secret")
+		assert.EqualError(t, err, "directory is unavailable")
+	})
+}
+
+func TestAuthService_LDAPAuthenticator(t *testing.T) {
+	user := &entity.User{Model: gorm.Model{ID: 1}, Name: "alice", Role: entity.RoleUser}
+	userRepo := &MockAuthUserRepository{}
+	userRepo.On("FindUserById", uint(1)).Return(user, nil)
+	userRepo.On("FindUserByName", "alice").Return(user, nil)
+	throttleRepo := &MockLoginThrottleRepository{}
+	throttleRepo.On("FindThrottle", mock.Anything, mock.Anything).Return((*entity.LoginThrottle)(nil), nil)
+	throttleRepo.On("RecordFailure", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&entity.LoginThrottle{Failures: 1}, nil)
+	directory := &MockLDAPDirectory{}
+	directory.On("Authenticate", "alice", "wrong").Return((*provider.LDAPUser)(nil), nil)
+	uow := &MockAuthUnitOfWork{userRepo: userRepo, throttleRepo: throttleRepo}
+	authenticator, err := NewLDAPAuthenticator(uow, directory, testLDAPConfig)
+	assert.NoError(t, err)
+	cfg := testAuthConfig
+	cfg.MaxFailedLogins = 5
+	service := NewAuthService(provider.NewJWTAuth([]byte("test_secret"), time.Hour), uow, authenticator, testPasswords, cfg)
+
+	_, err = service.Authenticate("alice", "wrong", "10.0.0.1")
+	assert.EqualError(t, err, "password is incorrect")
+	throttleRepo.AssertCalled(t, "RecordFailure", entity.ThrottleUser, "alice", mock.Anything, mock.Anything)
+
+	_, err = service.ChangePassword(1, "secret", "correct horse battery staple", "10.0.0.1")
+	assert.EqualError(t, err, "passwords are managed by the directory")
+	_, err = service.IssueResetToken("alice")
+	assert.EqualError(t, err, "passwords are managed by the directory")
+}
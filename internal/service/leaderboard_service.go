@@ -0,0 +1,97 @@
+package service
+
+import (
+	"fmt"
+	"merch_shop/internal/model"
+	"merch_shop/internal/repository"
+	"time"
+)
+
+const (
+	PeriodWeek    = "week"
+	PeriodMonth   = "month"
+	PeriodQuarter = "quarter"
+)
+
+type LeaderboardService struct {
+	uow repository.UnitOfWork
+	now func() time.Time
+}
+
+func NewLeaderboardService(uow repository.UnitOfWork) *LeaderboardService {
+	return &LeaderboardService{uow: uow, now: time.Now}
+}
+
+// GetLeaderboard ranks users by the metric over the current calendar week, month or quarter (UTC).
+// Users with equal values share a rank.
+func (l LeaderboardService) GetLeaderboard(metric string, period string, limit int) (model.Leaderboard, error) {
+	from, to, err := periodRange(period, l.now())
+	if err != nil {
+		return model.Leaderboard{}, err
+	}
+	switch metric {
+	case repository.LeaderboardReceived, repository.LeaderboardSent, repository.LeaderboardColleagues:
+	default:
+		return model.Leaderboard{}, fmt.Errorf("unknown leaderboard metric")
+	}
+
+	entries, err := l.uow.LeaderboardRepository().GetLeaderboard(metric, from, to, limit)
+	if err != nil {
+		return model.Leaderboard{}, fmt.Errorf("error getting leaderboard")
+	}
+	entriesModel := make([]model.LeaderboardEntry, 0, len(entries))
+	for i, v := range entries {
+		rank := i + 1
+		if i > 0 && v.Value == entries[i-1].Value {
+			rank = entriesModel[i-1].Rank
+		}
+		entriesModel = append(entriesModel, model.LeaderboardEntry{
+			Rank:  rank,
+			User:  v.Name,
+			Value: v.Value,
+		})
+	}
+	return model.Leaderboard{
+		Metric:  metric,
+		Period:  period,
+		From:    from,
+		To:      to,
+		Entries: entriesModel,
+	}, nil
+}
+
+// SetLeaderboardVisibility hides the user from leaderboards or shows them again.
+func (l LeaderboardService) SetLeaderboardVisibility(userId uint, hidden bool) error {
+	userRepository := l.uow.UserRepository()
+	user, err := userRepository.FindUserById(userId)
+	if err != nil {
+		return fmt.Errorf("failed to find user")
+	}
+	if user == nil {
+		return fmt.Errorf("user not found")
+	}
+	user.LeaderboardOptOut = hidden
+	if err := userRepository.UpdateUserColumns(user, "leaderboard_opt_out"); err != nil {
+		return fmt.Errorf("failed to update user")
+	}
+	return nil
+}
+
+// periodRange returns the start of the calendar period containing now and the start of the next one.
+// Weeks start on Monday.
+func periodRange(period string, now time.Time) (time.Time, time.Time, error) {
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	switch period {
+	case PeriodWeek:
+		from := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+		return from, from.AddDate(0, 0, 7), nil
+	case PeriodMonth:
+		from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return from, from.AddDate(0, 1, 0), nil
+	case PeriodQuarter:
+		from := time.Date(now.Year(), now.Month()-(now.Month()-1)%3, 1, 0, 0, 0, 0, time.UTC)
+		return from, from.AddDate(0, 3, 0), nil
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("unknown leaderboard period")
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"merch_shop/internal/repository"
+	"testing"
+	"time"
+)
+
+type MockLeaderboardRepository struct {
+	mock.Mock
+}
+
+func (m *MockLeaderboardRepository) GetLeaderboard(metric string, from time.Time, to time.Time, limit int) ([]repository.LeaderboardEntry, error) {
+	args := m.Called(metric, from, to, limit)
+	return args.Get(0).([]repository.LeaderboardEntry), args.Error(1)
+}
+
+func TestPeriodRange(t *testing.T) {
+	// A Thursday in the second month of a quarter.
+	now := time.Date(2025, 5, 15, 18, 30, 0, 0, time.UTC)
+
+	from, to, err := periodRange(PeriodWeek, now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 5, 12, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2025, 5, 19, 0, 0, 0, 0, time.UTC), to)
+
+	from, to, err = periodRange(PeriodMonth, now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), to)
+
+	from, to, err = periodRange(PeriodQuarter, now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), to)
+
+	from, _, err = periodRange(PeriodWeek, time.Date(2025, 5, 18, 23, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 5, 12, 0, 0, 0, 0, time.UTC), from, "sunday belongs to the week started on monday")
+
+	_, _, err = periodRange("year", now)
+	assert.EqualError(t, err, "unknown leaderboard period")
+}
+
+func TestLeaderboardService_GetLeaderboard(t *testing.T) {
+	now := time.Date(2025, 5, 15, 18, 30, 0, 0, time.UTC)
+
+	t.Run("UnknownMetric", func(t *testing.T) {
+		service := NewLeaderboardService(&MockUnitOfWork{transactionUnitOfWork: &MockTransactionUnitOfWork{}})
+		service.now = func() time.Time { return now }
+
+		_, err := service.GetLeaderboard("balance", PeriodMonth, 10)
+		assert.EqualError(t, err, "unknown leaderboard metric")
+	})
+
+	t.Run("SharedRanks", func(t *testing.T) {
+		leaderboardRepo := &MockLeaderboardRepository{}
+		leaderboardRepo.On("GetLeaderboard", repository.LeaderboardReceived,
+			time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), 10).
+			Return([]repository.LeaderboardEntry{
+				{UserId: 1, Name: "alice", Value: 300},
+				{UserId: 2, Name: "bob", Value: 300},
+				{UserId: 3, Name: "carol", Value: 100},
+			}, nil)
+
+		service := NewLeaderboardService(&MockUnitOfWork{transactionUnitOfWork: &MockTransactionUnitOfWork{LeaderboardRepo: leaderboardRepo}})
+		service.now = func() time.Time { return now }
+
+		res, err := service.GetLeaderboard(repository.LeaderboardReceived, PeriodMonth, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, res.Entries[0].Rank)
+		assert.Equal(t, 1, res.Entries[1].Rank)
+		assert.Equal(t, 3, res.Entries[2].Rank)
+		assert.Equal(t, "carol", res.Entries[2].User)
+	})
+}
+
+func TestLeaderboardService_SetLeaderboardVisibility(t *testing.T) {
+	user := &entity.User{Model: gorm.Model{ID: 1}, Name: "alice"}
+
+	userRepo := &MockUserRepository{}
+	userRepo.On("FindUserById", uint(1)).Return(user, nil)
+	userRepo.On("UpdateUserColumns", user, []string{"leaderboard_opt_out"}).Return(nil)
+
+	service := NewLeaderboardService(&MockUnitOfWork{transactionUnitOfWork: &MockTransactionUnitOfWork{UserRepo: userRepo}})
+
+	err := service.SetLeaderboardVisibility(1, true)
+	assert.NoError(t, err)
+	assert.True(t, user.LeaderboardOptOut)
+	userRepo.AssertExpectations(t)
+}
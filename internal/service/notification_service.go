@@ -0,0 +1,45 @@
+package service
+
+import (
+	"fmt"
+	"merch_shop/internal/model"
+	"merch_shop/internal/repository"
+	"time"
+)
+
+type NotificationService struct {
+	uow repository.UnitOfWork
+	now func() time.Time
+}
+
+func NewNotificationService(uow repository.UnitOfWork) *NotificationService {
+	return &NotificationService{uow: uow, now: time.Now}
+}
+
+func (n NotificationService) GetNotifications(userId uint) ([]model.Notification, error) {
+	notifications, err := n.uow.NotificationRepository().GetNotifications(userId)
+	if err != nil {
+		return nil, fmt.Errorf("error getting notifications")
+	}
+	notificationsModel := make([]model.Notification, 0, len(notifications))
+	for _, v := range notifications {
+		notificationsModel = append(notificationsModel, model.Notification{
+			Id:        v.ID,
+			Message:   v.Message,
+			CreatedAt: v.CreatedAt,
+			ReadAt:    v.ReadAt,
+		})
+	}
+	return notificationsModel, nil
+}
+
+func (n NotificationService) MarkNotificationRead(userId uint, notificationId uint) error {
+	marked, err := n.uow.NotificationRepository().MarkNotificationRead(userId, notificationId, n.now())
+	if err != nil {
+		return fmt.Errorf("failed to update notification")
+	}
+	if !marked {
+		return fmt.Errorf("notification not found")
+	}
+	return nil
+}
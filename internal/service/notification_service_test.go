@@ -0,0 +1,75 @@
+package service
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"testing"
+	"time"
+)
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) CreateNotification(notification *entity.Notification) error {
+	args := m.Called(notification)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) GetNotifications(userId uint) ([]entity.Notification, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]entity.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) MarkNotificationRead(userId uint, notificationId uint, at time.Time) (bool, error) {
+	args := m.Called(userId, notificationId, at)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestNotificationService_GetNotifications(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		notificationRepo := &MockNotificationRepository{}
+		notificationRepo.On("GetNotifications", uint(1)).Return([]entity.Notification{
+			{Model: gorm.Model{ID: 7, CreatedAt: now}, UserID: 1, Message: "You can now afford pink-hoody"},
+			{Model: gorm.Model{ID: 5, CreatedAt: now.Add(-time.Hour)}, UserID: 1, Message: "Badge earned", ReadAt: &now},
+		}, nil)
+		service := NewNotificationService(&MockUnitOfWork{transactionUnitOfWork: &MockTransactionUnitOfWork{NotificationRepo: notificationRepo}})
+
+		notifications, err := service.GetNotifications(1)
+		assert.NoError(t, err)
+		assert.Len(t, notifications, 2)
+		assert.Equal(t, uint(7), notifications[0].Id)
+		assert.Equal(t, "You can now afford pink-hoody", notifications[0].Message)
+		assert.Nil(t, notifications[0].ReadAt)
+		assert.Equal(t, now, *notifications[1].ReadAt)
+	})
+
+	t.Run("RepositoryError", func(t *testing.T) {
+		notificationRepo := &MockNotificationRepository{}
+		notificationRepo.On("GetNotifications", uint(1)).Return([]entity.Notification(nil), errors.New("db error"))
+		service := NewNotificationService(&MockUnitOfWork{transactionUnitOfWork: &MockTransactionUnitOfWork{NotificationRepo: notificationRepo}})
+
+		_, err := service.GetNotifications(1)
+		assert.EqualError(t, err, "error getting notifications")
+	})
+}
+
+func TestNotificationService_MarkNotificationRead(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	notificationRepo := &MockNotificationRepository{}
+	notificationRepo.On("MarkNotificationRead", uint(1), uint(7), now).Return(true, nil)
+	// Notification 8 belongs to another user, so the repository finds nothing to mark for user 1.
+	notificationRepo.On("MarkNotificationRead", uint(1), uint(8), now).Return(false, nil)
+	notificationRepo.On("MarkNotificationRead", uint(1), uint(9), now).Return(false, errors.New("db error"))
+	service := NewNotificationService(&MockUnitOfWork{transactionUnitOfWork: &MockTransactionUnitOfWork{NotificationRepo: notificationRepo}})
+	service.now = func() time.Time { return now }
+
+	assert.NoError(t, service.MarkNotificationRead(1, 7))
+	assert.EqualError(t, service.MarkNotificationRead(1, 8), "notification not found")
+	assert.EqualError(t, service.MarkNotificationRead(1, 9), "failed to update notification")
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"fmt"
+	"merch_shop/internal/config"
+	"merch_shop/internal/entity"
+	"merch_shop/internal/model"
+	"merch_shop/internal/provider"
+	"strings"
+	"time"
+)
+
+type oidcProvider interface {
+	AuthCodeURL(state string, nonce string, codeVerifier string) (string, error)
+	Exchange(code string, codeVerifier string, nonce string) (provider.OIDCIdentity, error)
+}
+
+// OIDCService signs users in with an OpenID Connect identity provider. The state, nonce and PKCE
+// code verifier of a sign-in are kept in the database until the provider redirects back.
+type OIDCService struct {
+	auth AuthService
+	oidc oidcProvider
+	cfg  config.OIDC
+	now  func() time.Time
+}
+
+func NewOIDCService(auth AuthService, oidc oidcProvider, cfg config.OIDC) OIDCService {
+	return OIDCService{auth: auth, oidc: oidc, cfg: cfg, now: time.Now}
+}
+
+// BeginLogin starts a sign-in and returns the URL of the identity provider to send the user to.
+func (o OIDCService) BeginLogin() (model.OIDCLogin, error) {
+	state, err := randomToken()
+	if err != nil {
+		return model.OIDCLogin{}, fmt.Errorf("failed to generate state")
+	}
+	nonce, err := randomToken()
+	if err != nil {
+		return model.OIDCLogin{}, fmt.Errorf("failed to generate nonce")
+	}
+	codeVerifier, err := randomToken()
+	if err != nil {
+		return model.OIDCLogin{}, fmt.Errorf("failed to generate code verifier")
+	}
+	authorizationURL, err := o.oidc.AuthCodeURL(state, nonce, codeVerifier)
+	if err != nil {
+		return model.OIDCLogin{}, fmt.Errorf("identity provider is unavailable")
+	}
+
+	now := o.now()
+	oidcLoginRepository := o.auth.uow.OIDCLoginRepository()
+	if err := oidcLoginRepository.DeleteExpiredOIDCLogins(now); err != nil {
+		return model.OIDCLogin{}, fmt.Errorf("failed to delete expired sign-ins")
+	}
+	login := &entity.OIDCLogin{
+		StateHash:    hashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    now.Add(o.cfg.LoginTTL),
+	}
+	if err := oidcLoginRepository.CreateOIDCLogin(login); err != nil {
+		return model.OIDCLogin{}, fmt.Errorf("failed to create sign-in")
+	}
+	return model.OIDCLogin{AuthorizationURL: authorizationURL}, nil
+}
+
+// CompleteLogin redeems the authorization code the identity provider redirected back with. Users are
+// matched by subject, then by verified email, which links the existing account; unknown users are
+// registered when sign-up is allowed.
+func (o OIDCService) CompleteLogin(state string, code string) (model.AuthResponse, error) {
+	login, err := o.auth.uow.OIDCLoginRepository().TakeOIDCLogin(hashToken(state))
+	if err != nil {
+		return model.AuthResponse{}, fmt.Errorf("failed to find sign-in")
+	}
+	if login == nil || !o.now().Before(login.ExpiresAt) {
+		return model.AuthResponse{}, fmt.Errorf("sign-in is invalid or expired")
+	}
+	identity, err := o.oidc.Exchange(code, login.CodeVerifier, login.Nonce)
+	if err != nil {
+		return model.AuthResponse{}, fmt.Errorf("sign-in with the identity provider failed")
+	}
+	user, err := o.findOrCreateUser(identity)
+	if err != nil {
+		return model.AuthResponse{}, err
+	}
+	return o.auth.signIn(user)
+}
+
+func (o OIDCService) findOrCreateUser(identity provider.OIDCIdentity) (*entity.User, error) {
+	userRepository := o.auth.uow.UserRepository()
+	user, err := userRepository.FindUserByOIDCSubject(identity.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user")
+	}
+	if user != nil {
+		return user, nil
+	}
+
+	if identity.Email != "" && identity.EmailVerified {
+		user, err = userRepository.FindUserByEmail(identity.Email)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find user")
+		}
+		if user != nil {
+			if user.OIDCSubject != nil || user.Role == entity.RoleService {
+				return nil, fmt.Errorf("account cannot be linked to this identity")
+			}
+			user.OIDCSubject = &identity.Subject
+			if err := userRepository.UpdateUserColumns(user, "oidc_subject"); err != nil {
+				return nil, fmt.Errorf("failed to update user")
+			}
+			return user, nil
+		}
+	}
+
+	if !o.cfg.AllowSignUp {
+		return nil, fmt.Errorf("no account for this identity")
+	}
+	name := oidcUserName(identity)
+	existing, err := userRepository.FindUserByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user by username %s", name)
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("username %s is already taken", name)
+	}
+	subject := identity.Subject
+	user = &entity.User{
+		Name:        name,
+		DisplayName: identity.Name,
+		OIDCSubject: &subject,
+		Balance:     START_BALANCE,
+		Role:        entity.RoleUser,
+	}
+	if identity.EmailVerified {
+		user.Email = identity.Email
+	}
+	if err := userRepository.CreateUser(user); err != nil {
+		return nil, fmt.Errorf("failed to create user")
+	}
+	return user, nil
+}
+
+// oidcUserName picks the username of a user registered through SSO.
+func oidcUserName(identity provider.OIDCIdentity) string {
+	if identity.PreferredUsername != "" {
+		return identity.PreferredUsername
+	}
+	if local, _, found := strings.Cut(identity.Email, "@"); found && local != "" {
+		return local
+	}
+	return identity.Subject
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+	"merch_shop/internal/config"
+	"merch_shop/internal/entity"
+	"merch_shop/internal/provider"
+	"testing"
+	"time"
+)
+
+type MockOIDCLoginRepository struct {
+	mock.Mock
+}
+
+func (m *MockOIDCLoginRepository) CreateOIDCLogin(login *entity.OIDCLogin) error {
+	args := m.Called(login)
+	return args.Error(0)
+}
+
+func (m *MockOIDCLoginRepository) TakeOIDCLogin(stateHash string) (*entity.OIDCLogin, error) {
+	args := m.Called(stateHash)
+	return args.Get(0).(*entity.OIDCLogin), args.Error(1)
+}
+
+func (m *MockOIDCLoginRepository) DeleteExpiredOIDCLogins(before time.Time) error {
+	args := m.Called(before)
+	return args.Error(0)
+}
+
+type MockOIDCProvider struct {
+	mock.Mock
+}
+
+func (m *MockOIDCProvider) AuthCodeURL(state string, nonce string, codeVerifier string) (string, error) {
+	args := m.Called(state, nonce, codeVerifier)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockOIDCProvider) Exchange(code string, codeVerifier string, nonce string) (provider.OIDCIdentity, error) {
+	args := m.Called(code, codeVerifier, nonce)
+	return args.Get(0).(provider.OIDCIdentity), args.Error(1)
+}
+
+var testOIDCConfig = config.OIDC{AllowSignUp: true, LoginTTL: 10 * time.Minute}
+
+func newTestOIDCService(userRepo *MockAuthUserRepository, loginRepo *MockOIDCLoginRepository, oidc *MockOIDCProvider, cfg config.OIDC, now time.Time) OIDCService {
+	uow := &MockAuthUnitOfWork{userRepo: userRepo, oidcLoginRepo: loginRepo}
+	auth := newTestAuthService(provider.NewJWTAuth([]byte("test_secret"), time.Hour), uow, testAuthConfig)
+	service := NewOIDCService(auth, oidc, cfg)
+	service.now = func() time.Time { return now }
+	return service
+}
+
+func TestOIDCService_BeginLogin(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	loginRepo := &MockOIDCLoginRepository{}
+	loginRepo.On("DeleteExpiredOIDCLogins", now).Return(nil)
+	loginRepo.On("CreateOIDCLogin", mock.AnythingOfType("*entity.OIDCLogin")).Return(nil)
+	oidc := &MockOIDCProvider{}
+	oidc.On("AuthCodeURL", mock.Anything, mock.Anything, mock.Anything).
+		Return("https://idp.example.com/authorize?state=abc", nil)
+
+	service := newTestOIDCService(&MockAuthUserRepository{}, loginRepo, oidc, testOIDCConfig, now)
+
+	login, err := service.BeginLogin()
+	assert.NoError(t, err)
+	assert.Equal(t, "https://idp.example.com/authorize?state=abc", login.AuthorizationURL)
+
+	args := oidc.Calls[0].Arguments
+	stored := loginRepo.Calls[1].Arguments.Get(0).(*entity.OIDCLogin)
+	assert.Equal(t, hashToken(args.String(0)), stored.StateHash)
+	assert.Equal(t, args.String(1), stored.Nonce)
+	assert.Equal(t, args.String(2), stored.CodeVerifier)
+	assert.Equal(t, now.Add(10*time.Minute), stored.ExpiresAt)
+}
+
+func TestOIDCService_CompleteLogin(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	pending := &entity.OIDCLogin{StateHash: hashToken("state"), Nonce: "nonce", CodeVerifier: "verifier", ExpiresAt: now.Add(time.Minute)}
+	identity := provider.OIDCIdentity{Subject: "sub-1", Email: "Alice@Example.com", EmailVerified: true, Name: "Alice", PreferredUsername: "alice"}
+
+	setup := func(identity provider.OIDCIdentity) (*MockAuthUserRepository, *MockOIDCLoginRepository, *MockOIDCProvider) {
+		loginRepo := &MockOIDCLoginRepository{}
+		loginRepo.On("TakeOIDCLogin", hashToken("state")).Return(pending, nil)
+		loginRepo.On("TakeOIDCLogin", mock.Anything).Return((*entity.OIDCLogin)(nil), nil)
+		oidc := &MockOIDCProvider{}
+		oidc.On("Exchange", "code", "verifier", "nonce").Return(identity, nil)
+		oidc.On("Exchange", mock.Anything, mock.Anything, mock.Anything).Return(provider.OIDCIdentity{}, errors.New("invalid_grant"))
+		return &MockAuthUserRepository{}, loginRepo, oidc
+	}
+
+	t.Run("NewUser", func(t *testing.T) {
+		userRepo, loginRepo, oidc := setup(identity)
+		userRepo.On("FindUserByOIDCSubject", "sub-1").Return((*entity.User)(nil), nil)
+		userRepo.On("FindUserByEmail", "Alice@Example.com").Return((*entity.User)(nil), nil)
+		userRepo.On("FindUserByName", "alice").Return((*entity.User)(nil), nil)
+		userRepo.On("CreateUser", mock.AnythingOfType("*entity.User")).Return(nil)
+		service := newTestOIDCService(userRepo, loginRepo, oidc, testOIDCConfig, now)
+
+		response, err := service.CompleteLogin("state", "code")
+		assert.NoError(t, err)
+		assert.NotEmpty(t, response.Token)
+		created := userRepo.Calls[len(userRepo.Calls)-1].Arguments.Get(0).(*entity.User)
+		assert.Equal(t, "alice", created.Name)
+		assert.Equal(t, "Alice", created.DisplayName)
+		assert.Equal(t, "Alice@Example.com", created.Email)
+		assert.Equal(t, "sub-1", *created.OIDCSubject)
+		assert.Equal(t, uint(START_BALANCE), created.Balance)
+		assert.Empty(t, created.PasswordHash)
+	})
+
+	t.Run("LinksVerifiedEmail", func(t *testing.T) {
+		userRepo, loginRepo, oidc := setup(identity)
+		existing := &entity.User{Model: gorm.Model{ID: 3}, Name: "alice.smith", Email: "alice@example.com", Role: entity.RoleUser}
+		userRepo.On("FindUserByOIDCSubject", "sub-1").Return((*entity.User)(nil), nil)
+		userRepo.On("FindUserByEmail", "Alice@Example.com").Return(existing, nil)
+		userRepo.On("UpdateUserColumns", existing, []string{"oidc_subject"}).Return(nil)
+		service := newTestOIDCService(userRepo, loginRepo, oidc, testOIDCConfig, now)
+
+		response, err := service.CompleteLogin("state", "code")
+		assert.NoError(t, err)
+		assert.NotEmpty(t, response.Token)
+		assert.Equal(t, "sub-1", *existing.OIDCSubject)
+		userRepo.AssertNotCalled(t, "CreateUser", mock.Anything)
+	})
+
+	t.Run("UnverifiedEmailIsNotLinked", func(t *testing.T) {
+		unverified := identity
+		unverified.EmailVerified = false
+		userRepo, loginRepo, oidc := setup(unverified)
+		userRepo.On("FindUserByOIDCSubject", "sub-1").Return((*entity.User)(nil), nil)
+		userRepo.On("FindUserByName", "alice").Return(&entity.User{Name: "alice"}, nil)
+		service := newTestOIDCService(userRepo, loginRepo, oidc, testOIDCConfig, now)
+
+		_, err := service.CompleteLogin("state", "code")
+		assert.EqualError(t, err, "username alice is already taken")
+		userRepo.AssertNotCalled(t, "FindUserByEmail", mock.Anything)
+	})
+
+	t.Run("TwoFactorChallenge", func(t *testing.T) {
+		userRepo, loginRepo, oidc := setup(identity)
+		subject := "sub-1"
+		userRepo.On("FindUserByOIDCSubject", "sub-1").
+			Return(&entity.User{Model: gorm.Model{ID: 3}, Name: "alice", OIDCSubject: &subject, TOTPEnabled: true}, nil)
+		service := newTestOIDCService(userRepo, loginRepo, oidc, testOIDCConfig, now)
+
+		response, err := service.CompleteLogin("state", "code")
+		assert.NoError(t, err)
+		assert.Empty(t, response.Token)
+		assert.True(t, response.TwoFactorRequired)
+		assert.NotEmpty(t, response.ChallengeToken)
+	})
+
+	t.Run("SignUpDisabled", func(t *testing.T) {
+		userRepo, loginRepo, oidc := setup(identity)
+		userRepo.On("FindUserByOIDCSubject", "sub-1").Return((*entity.User)(nil), nil)
+		userRepo.On("FindUserByEmail", "Alice@Example.com").Return((*entity.User)(nil), nil)
+		cfg := testOIDCConfig
+		cfg.AllowSignUp = false
+		service := newTestOIDCService(userRepo, loginRepo, oidc, cfg, now)
+
+		_, err := service.CompleteLogin("state", "code")
+		assert.EqualError(t, err, "no account for this identity")
+	})
+
+	t.Run("UnknownOrExpiredState", func(t *testing.T) {
+		userRepo, loginRepo, oidc := setup(identity)
+		service := newTestOIDCService(userRepo, loginRepo, oidc, testOIDCConfig, now)
+		_, err := service.CompleteLogin("forged", "code")
+		assert.EqualError(t, err, "sign-in is invalid or expired")
+
+		service.now = func() time.Time { return pending.ExpiresAt }
+		_, err = service.CompleteLogin("state", "code")
+		assert.EqualError(t, err, "sign-in is invalid or expired")
+		oidc.AssertNotCalled(t, "Exchange", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("ExchangeFails", func(t *testing.T) {
+		userRepo, loginRepo, oidc := setup(identity)
+		service := newTestOIDCService(userRepo, loginRepo, oidc, testOIDCConfig, now)
+		_, err := service.CompleteLogin("state", "stolen")
+		assert.EqualError(t, err, "sign-in with the identity provider failed")
+	})
+}
+
+func TestOIDCUserName(t *testing.T) {
+	assert.Equal(t, "alice", oidcUserName(provider.OIDCIdentity{Subject: "s", Email: "a@example.com", PreferredUsername: "alice"}))
+	assert.Equal(t, "bob", oidcUserName(provider.OIDCIdentity{Subject: "s", Email: "bob@example.com"}))
+	assert.Equal(t, "s", oidcUserName(provider.OIDCIdentity{Subject: "s"}))
+}
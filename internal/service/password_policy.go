@@ -0,0 +1,89 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"golang.org/x/crypto/bcrypt"
+	"merch_shop/internal/config"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxPasswordBytes is the longest password bcrypt can hash.
+const maxPasswordBytes = 72
+
+// PasswordPolicy decides which new passwords are accepted and how they are hashed.
+type PasswordPolicy struct {
+	minLength int
+	denyList  map[string]bool
+	cost      int
+	// dummyHash is compared against when there is no stored hash, so that the check takes as long.
+	dummyHash []byte
+}
+
+func NewPasswordPolicy(minLength int, denyList []string, cost int) PasswordPolicy {
+	if cost < bcrypt.MinCost {
+		cost = bcrypt.DefaultCost
+	}
+	denied := make(map[string]bool, len(denyList))
+	for _, v := range denyList {
+		denied[strings.ToLower(v)] = true
+	}
+	dummyHash, _ := bcrypt.GenerateFromPassword([]byte("dummy password"), cost)
+	return PasswordPolicy{minLength: minLength, denyList: denied, cost: cost, dummyHash: dummyHash}
+}
+
+// LoadPasswordPolicy builds the policy from the configuration, reading the deny-list file when one is set.
+// The file lists one password per line; empty lines and lines starting with # are skipped.
+func LoadPasswordPolicy(cfg config.Auth) (PasswordPolicy, error) {
+	var denyList []string
+	if cfg.PasswordDenyList != "" {
+		file, err := os.Open(cfg.PasswordDenyList)
+		if err != nil {
+			return PasswordPolicy{}, fmt.Errorf("unable to open password deny-list, %w", err)
+		}
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			denyList = append(denyList, line)
+		}
+		if err := scanner.Err(); err != nil {
+			return PasswordPolicy{}, fmt.Errorf("unable to read password deny-list, %w", err)
+		}
+	}
+	return NewPasswordPolicy(cfg.PasswordMinLength, denyList, cfg.BcryptCost), nil
+}
+
+func (p PasswordPolicy) Validate(password string) error {
+	if utf8.RuneCountInString(password) < p.minLength {
+		return fmt.Errorf("password must have at least %d characters", p.minLength)
+	}
+	if len(password) > maxPasswordBytes {
+		return fmt.Errorf("password must have at most %d bytes", maxPasswordBytes)
+	}
+	if p.denyList[strings.ToLower(password)] {
+		return fmt.Errorf("password is too common")
+	}
+	return nil
+}
+
+func (p PasswordPolicy) Hash(password string) (string, error) {
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), p.cost)
+	return string(bytes), err
+}
+
+// NeedsRehash reports whether the hash was made with another cost or is not a bcrypt hash.
+func (p PasswordPolicy) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != p.cost
+}
+
+// DummyVerify spends as much time as checking a password against a stored hash.
+func (p PasswordPolicy) DummyVerify(password string) {
+	bcrypt.CompareHashAndPassword(p.dummyHash, []byte(password))
+}
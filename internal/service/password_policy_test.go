@@ -0,0 +1,42 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+	"merch_shop/internal/config"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	policy := NewPasswordPolicy(8, []string{"Password1"}, bcrypt.MinCost)
+
+	assert.EqualError(t, policy.Validate("1"), "password must have at least 8 characters")
+	assert.EqualError(t, policy.Validate("PASSWORD1"), "password is too common")
+	assert.EqualError(t, policy.Validate(strings.Repeat("a", 73)), "password must have at most 72 bytes")
+	assert.NoError(t, policy.Validate("correct horse"))
+}
+
+func TestLoadPasswordPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "passwords.txt")
+	os.WriteFile(path, []byte("# common passwords\nqwertyuiop\n\n  letmein123  \n"), 0o600)
+
+	policy, err := LoadPasswordPolicy(config.Auth{PasswordMinLength: 8, PasswordDenyList: path, BcryptCost: bcrypt.MinCost})
+	assert.NoError(t, err)
+	assert.EqualError(t, policy.Validate("letmein123"), "password is too common")
+	assert.NoError(t, policy.Validate("# common passwords"))
+
+	_, err = LoadPasswordPolicy(config.Auth{PasswordDenyList: filepath.Join(t.TempDir(), "missing.txt")})
+	assert.Error(t, err)
+}
+
+func TestPasswordPolicy_NeedsRehash(t *testing.T) {
+	policy := NewPasswordPolicy(8, nil, bcrypt.MinCost)
+	hash, _ := policy.Hash("password")
+
+	assert.False(t, policy.NeedsRehash(hash))
+	assert.True(t, NewPasswordPolicy(8, nil, bcrypt.MinCost+1).NeedsRehash(hash))
+	assert.True(t, policy.NeedsRehash("5f4dcc3b5aa765d61d8327deb882cf99"))
+}
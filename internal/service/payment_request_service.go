@@ -0,0 +1,201 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"merch_shop/internal/config"
+	"merch_shop/internal/entity"
+	"merch_shop/internal/model"
+	"merch_shop/internal/repository"
+	"time"
+)
+
+type PaymentRequestService struct {
+	uow   repository.UnitOfWork
+	ttl   time.Duration
+	rules []config.AchievementRule
+	now   func() time.Time
+}
+
+func NewPaymentRequestService(uow repository.UnitOfWork, ttl time.Duration, rules []config.AchievementRule) *PaymentRequestService {
+	return &PaymentRequestService{uow: uow, ttl: ttl, rules: rules, now: time.Now}
+}
+
+func (p PaymentRequestService) RequestCoin(userId uint, fromUserName string, amount uint, comment string) (model.PaymentRequest, error) {
+	userRepository := p.uow.UserRepository()
+	requester, err := userRepository.FindUserById(userId)
+	if err != nil {
+		return model.PaymentRequest{}, fmt.Errorf("failed to find user")
+	}
+	if requester == nil {
+		return model.PaymentRequest{}, fmt.Errorf("user not found")
+	}
+	payer, err := userRepository.FindUserByName(fromUserName)
+	if err != nil {
+		return model.PaymentRequest{}, fmt.Errorf("failed to find user")
+	}
+	if payer == nil {
+		return model.PaymentRequest{}, fmt.Errorf("user not found")
+	}
+	if payer.ID == requester.ID {
+		return model.PaymentRequest{}, fmt.Errorf("cannot request coin from yourself")
+	}
+
+	request := entity.PaymentRequest{
+		RequesterId: requester.ID,
+		PayerId:     payer.ID,
+		Amount:      amount,
+		Comment:     comment,
+		Status:      entity.PaymentRequestPending,
+		ExpiresAt:   p.now().Add(p.ttl),
+	}
+	if err := p.uow.PaymentRequestRepository().CreatePaymentRequest(&request); err != nil {
+		return model.PaymentRequest{}, fmt.Errorf("failed to create payment request")
+	}
+	return model.PaymentRequest{
+		Id:        request.ID,
+		ToUser:    payer.Name,
+		Amount:    request.Amount,
+		Comment:   request.Comment,
+		Status:    request.Status,
+		CreatedAt: request.CreatedAt,
+		ExpiresAt: request.ExpiresAt,
+	}, nil
+}
+
+func (p PaymentRequestService) GetPaymentRequests(userId uint) (model.PaymentRequestsResponse, error) {
+	paymentRequestRepository := p.uow.PaymentRequestRepository()
+	now := p.now()
+
+	incoming, err := paymentRequestRepository.GetIncomingPaymentRequests(userId, now)
+	if err != nil {
+		return model.PaymentRequestsResponse{}, fmt.Errorf("error getting incoming payment requests")
+	}
+	outgoing, err := paymentRequestRepository.GetOutgoingPaymentRequests(userId, now)
+	if err != nil {
+		return model.PaymentRequestsResponse{}, fmt.Errorf("error getting outgoing payment requests")
+	}
+
+	incomingModel := make([]model.PaymentRequest, 0, len(incoming))
+	for _, v := range incoming {
+		incomingModel = append(incomingModel, model.PaymentRequest{
+			Id:        v.ID,
+			FromUser:  v.Requester.Name,
+			Amount:    v.Amount,
+			Comment:   v.Comment,
+			Status:    v.Status,
+			CreatedAt: v.CreatedAt,
+			ExpiresAt: v.ExpiresAt,
+		})
+	}
+	outgoingModel := make([]model.PaymentRequest, 0, len(outgoing))
+	for _, v := range outgoing {
+		outgoingModel = append(outgoingModel, model.PaymentRequest{
+			Id:        v.ID,
+			ToUser:    v.Payer.Name,
+			Amount:    v.Amount,
+			Comment:   v.Comment,
+			Status:    v.Status,
+			CreatedAt: v.CreatedAt,
+			ExpiresAt: v.ExpiresAt,
+		})
+	}
+	return model.PaymentRequestsResponse{
+		Incoming: incomingModel,
+		Outgoing: outgoingModel,
+	}, nil
+}
+
+// AcceptPaymentRequest pays an incoming request using the same transfer as SendCoin,
+// within a single transaction so the request cannot be paid twice.
+func (p PaymentRequestService) AcceptPaymentRequest(userId uint, requestId uint) error {
+	tx, err := p.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction")
+	}
+
+	request, err := p.findPendingRequest(tx, userId, requestId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if !request.ExpiresAt.After(p.now()) {
+		request.Status = entity.PaymentRequestExpired
+		if err := tx.PaymentRequestRepository().UpdatePaymentRequest(request); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to update payment request")
+		}
+		tx.Commit()
+		return fmt.Errorf("payment request expired")
+	}
+
+	userRepository := tx.UserRepository()
+	payer, err := userRepository.FindUserById(request.PayerId)
+	if err != nil || payer == nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to find user")
+	}
+	requester, err := userRepository.FindUserById(request.RequesterId)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to find user")
+	}
+	if requester == nil {
+		tx.Rollback()
+		return fmt.Errorf("user not found")
+	}
+
+	transaction, err := transferCoins(tx, payer, requester, request.Amount, p.now())
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	request.Status = entity.PaymentRequestAccepted
+	request.TransactionId = &transaction.ID
+	if err := tx.PaymentRequestRepository().UpdatePaymentRequest(request); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to update payment request")
+	}
+	for _, user := range []*entity.User{payer, requester} {
+		if _, err := awardAchievements(tx, p.rules, user, p.now()); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	tx.Commit()
+	return nil
+}
+
+func (p PaymentRequestService) DeclinePaymentRequest(userId uint, requestId uint) error {
+	tx, err := p.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction")
+	}
+
+	request, err := p.findPendingRequest(tx, userId, requestId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	request.Status = entity.PaymentRequestDeclined
+	if err := tx.PaymentRequestRepository().UpdatePaymentRequest(request); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to update payment request")
+	}
+	tx.Commit()
+	return nil
+}
+
+func (p PaymentRequestService) findPendingRequest(tx repository.UnitOfWork, userId uint, requestId uint) (*entity.PaymentRequest, error) {
+	request, err := tx.PaymentRequestRepository().FindPaymentRequestById(requestId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find payment request")
+	}
+	if request == nil || request.PayerId != userId {
+		return nil, fmt.Errorf("payment request not found")
+	}
+	if request.Status != entity.PaymentRequestPending {
+		return nil, fmt.Errorf("payment request is already %s", request.Status)
+	}
+	return request, nil
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"testing"
+	"time"
+)
+
+type MockPaymentRequestRepository struct {
+	mock.Mock
+}
+
+func (m *MockPaymentRequestRepository) CreatePaymentRequest(request *entity.PaymentRequest) error {
+	args := m.Called(request)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRequestRepository) UpdatePaymentRequest(request *entity.PaymentRequest) error {
+	args := m.Called(request)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRequestRepository) FindPaymentRequestById(requestId uint) (*entity.PaymentRequest, error) {
+	args := m.Called(requestId)
+	return args.Get(0).(*entity.PaymentRequest), args.Error(1)
+}
+
+func (m *MockPaymentRequestRepository) GetIncomingPaymentRequests(userId uint, now time.Time) ([]entity.PaymentRequest, error) {
+	args := m.Called(userId, now)
+	return args.Get(0).([]entity.PaymentRequest), args.Error(1)
+}
+
+func (m *MockPaymentRequestRepository) GetOutgoingPaymentRequests(userId uint, now time.Time) ([]entity.PaymentRequest, error) {
+	args := m.Called(userId, now)
+	return args.Get(0).([]entity.PaymentRequest), args.Error(1)
+}
+
+func TestPaymentRequestService_RequestCoin(t *testing.T) {
+	t.Run("SelfRequest", func(t *testing.T) {
+		user := &entity.User{Model: gorm.Model{ID: 1}, Name: "user1"}
+
+		userRepo := &MockUserRepository{}
+		userRepo.On("FindUserById", uint(1)).Return(user, nil)
+		userRepo.On("FindUserByName", "user1").Return(user, nil)
+
+		tuow := &MockTransactionUnitOfWork{UserRepo: userRepo}
+		service := NewPaymentRequestService(&MockUnitOfWork{transactionUnitOfWork: tuow}, time.Hour, nil)
+
+		_, err := service.RequestCoin(1, "user1", 50, "")
+		assert.EqualError(t, err, "cannot request coin from yourself")
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		requester := &entity.User{Model: gorm.Model{ID: 1}, Name: "user1"}
+		payer := &entity.User{Model: gorm.Model{ID: 2}, Name: "user2"}
+
+		userRepo := &MockUserRepository{}
+		userRepo.On("FindUserById", uint(1)).Return(requester, nil)
+		userRepo.On("FindUserByName", "user2").Return(payer, nil)
+
+		paymentRequestRepo := &MockPaymentRequestRepository{}
+		paymentRequestRepo.On("CreatePaymentRequest", mock.MatchedBy(func(r *entity.PaymentRequest) bool {
+			return r.RequesterId == 1 && r.PayerId == 2 && r.Amount == 50 && r.ExpiresAt.Equal(now.Add(time.Hour))
+		})).Return(nil)
+
+		tuow := &MockTransactionUnitOfWork{UserRepo: userRepo, PaymentRequestRepo: paymentRequestRepo}
+		service := NewPaymentRequestService(&MockUnitOfWork{transactionUnitOfWork: tuow}, time.Hour, nil)
+		service.now = func() time.Time { return now }
+
+		res, err := service.RequestCoin(1, "user2", 50, "pizza")
+		assert.NoError(t, err)
+		assert.Equal(t, "user2", res.ToUser)
+		assert.Equal(t, entity.PaymentRequestPending, res.Status)
+		paymentRequestRepo.AssertExpectations(t)
+	})
+}
+
+func TestPaymentRequestService_AcceptPaymentRequest(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("NotPayer", func(t *testing.T) {
+		request := &entity.PaymentRequest{Model: gorm.Model{ID: 5}, RequesterId: 1, PayerId: 2, Amount: 50,
+			Status: entity.PaymentRequestPending, ExpiresAt: now.Add(time.Hour)}
+
+		paymentRequestRepo := &MockPaymentRequestRepository{}
+		paymentRequestRepo.On("FindPaymentRequestById", uint(5)).Return(request, nil)
+
+		tuow := &MockTransactionUnitOfWork{PaymentRequestRepo: paymentRequestRepo}
+		service := NewPaymentRequestService(&MockUnitOfWork{transactionUnitOfWork: tuow}, time.Hour, nil)
+		service.now = func() time.Time { return now }
+
+		err := service.AcceptPaymentRequest(1, 5)
+		assert.EqualError(t, err, "payment request not found")
+		assert.True(t, tuow.rollbackCalled)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		request := &entity.PaymentRequest{Model: gorm.Model{ID: 5}, RequesterId: 1, PayerId: 2, Amount: 50,
+			Status: entity.PaymentRequestPending, ExpiresAt: now.Add(-time.Minute)}
+
+		paymentRequestRepo := &MockPaymentRequestRepository{}
+		paymentRequestRepo.On("FindPaymentRequestById", uint(5)).Return(request, nil)
+		paymentRequestRepo.On("UpdatePaymentRequest", request).Return(nil)
+
+		tuow := &MockTransactionUnitOfWork{PaymentRequestRepo: paymentRequestRepo}
+		service := NewPaymentRequestService(&MockUnitOfWork{transactionUnitOfWork: tuow}, time.Hour, nil)
+		service.now = func() time.Time { return now }
+
+		err := service.AcceptPaymentRequest(2, 5)
+		assert.EqualError(t, err, "payment request expired")
+		assert.Equal(t, entity.PaymentRequestExpired, request.Status)
+		assert.True(t, tuow.commitCalled)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		request := &entity.PaymentRequest{Model: gorm.Model{ID: 5}, RequesterId: 1, PayerId: 2, Amount: 50,
+			Status: entity.PaymentRequestPending, ExpiresAt: now.Add(time.Hour)}
+		requester := &entity.User{Model: gorm.Model{ID: 1}, Balance: 0}
+		payer := &entity.User{Model: gorm.Model{ID: 2}, Balance: 100}
+
+		userRepo := &MockUserRepository{}
+		userRepo.On("FindUserById", uint(1)).Return(requester, nil)
+		userRepo.On("FindUserById", uint(2)).Return(payer, nil)
+		userRepo.On("UpdateUser", mock.Anything).Return(nil)
+
+		transactionRepo := &MockTransactionRepository{}
+		transactionRepo.On("CreateTransaction", mock.Anything).Return(nil)
+
+		paymentRequestRepo := &MockPaymentRequestRepository{}
+		paymentRequestRepo.On("FindPaymentRequestById", uint(5)).Return(request, nil)
+		paymentRequestRepo.On("UpdatePaymentRequest", request).Return(nil)
+
+		tuow := &MockTransactionUnitOfWork{
+			UserRepo:           userRepo,
+			TransactionRepo:    transactionRepo,
+			PaymentRequestRepo: paymentRequestRepo,
+			WishlistRepo:       emptyWishlist(1),
+		}
+		service := NewPaymentRequestService(&MockUnitOfWork{transactionUnitOfWork: tuow}, time.Hour, nil)
+		service.now = func() time.Time { return now }
+
+		err := service.AcceptPaymentRequest(2, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(50), payer.Balance)
+		assert.Equal(t, uint(50), requester.Balance)
+		assert.Equal(t, entity.PaymentRequestAccepted, request.Status)
+		assert.True(t, tuow.commitCalled)
+	})
+}
+
+func TestPaymentRequestService_DeclinePaymentRequest(t *testing.T) {
+	t.Run("AlreadyAccepted", func(t *testing.T) {
+		request := &entity.PaymentRequest{Model: gorm.Model{ID: 5}, RequesterId: 1, PayerId: 2,
+			Status: entity.PaymentRequestAccepted}
+
+		paymentRequestRepo := &MockPaymentRequestRepository{}
+		paymentRequestRepo.On("FindPaymentRequestById", uint(5)).Return(request, nil)
+
+		tuow := &MockTransactionUnitOfWork{PaymentRequestRepo: paymentRequestRepo}
+		service := NewPaymentRequestService(&MockUnitOfWork{transactionUnitOfWork: tuow}, time.Hour, nil)
+
+		err := service.DeclinePaymentRequest(2, 5)
+		assert.EqualError(t, err, "payment request is already accepted")
+		assert.True(t, tuow.rollbackCalled)
+	})
+}
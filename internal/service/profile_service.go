@@ -0,0 +1,193 @@
+package service
+
+import (
+	"fmt"
+	"merch_shop/internal/entity"
+	"merch_shop/internal/model"
+	"merch_shop/internal/repository"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// fuzzyCandidates caps how many users sharing the query's first letter are checked for typos.
+const fuzzyCandidates = 500
+
+type ProfileService struct {
+	uow repository.UnitOfWork
+}
+
+func NewProfileService(uow repository.UnitOfWork) *ProfileService {
+	return &ProfileService{uow: uow}
+}
+
+func (p ProfileService) GetMe(userId uint) (model.Profile, error) {
+	user, err := p.uow.UserRepository().FindUserById(userId)
+	if err != nil {
+		return model.Profile{}, fmt.Errorf("failed to find user")
+	}
+	if user == nil {
+		return model.Profile{}, fmt.Errorf("user not found")
+	}
+	return toProfileModel(*user), nil
+}
+
+func (p ProfileService) GetProfile(name string) (model.Profile, error) {
+	user, err := p.uow.UserRepository().FindUserByName(name)
+	if err != nil {
+		return model.Profile{}, fmt.Errorf("failed to find user")
+	}
+	if user == nil {
+		return model.Profile{}, fmt.Errorf("user not found")
+	}
+	return toProfileModel(*user), nil
+}
+
+// UpdateProfile changes the profile fields present in the request and leaves the others as they are.
+func (p ProfileService) UpdateProfile(userId uint, request model.ProfileRequest) (model.Profile, error) {
+	if request.AvatarURL != nil && *request.AvatarURL != "" {
+		avatarURL, err := url.Parse(*request.AvatarURL)
+		if err != nil || (avatarURL.Scheme != "http" && avatarURL.Scheme != "https") || avatarURL.Host == "" {
+			return model.Profile{}, fmt.Errorf("avatar URL is not valid")
+		}
+	}
+	userRepository := p.uow.UserRepository()
+	user, err := userRepository.FindUserById(userId)
+	if err != nil {
+		return model.Profile{}, fmt.Errorf("failed to find user")
+	}
+	if user == nil {
+		return model.Profile{}, fmt.Errorf("user not found")
+	}
+	if request.DisplayName != nil {
+		user.DisplayName = *request.DisplayName
+	}
+	if request.AvatarURL != nil {
+		user.AvatarURL = *request.AvatarURL
+	}
+	if request.Department != nil {
+		user.Department = *request.Department
+	}
+	if request.Bio != nil {
+		user.Bio = *request.Bio
+	}
+	if err := userRepository.UpdateUserColumns(user, "display_name", "avatar_url", "department", "bio"); err != nil {
+		return model.Profile{}, fmt.Errorf("failed to update user")
+	}
+	return toProfileModel(*user), nil
+}
+
+// SearchUsers suggests users by login name or display name. Prefix matches come first; when there
+// are not enough of them, names that differ from the query by a typo or two are added.
+// The searching user is left out.
+func (p ProfileService) SearchUsers(userId uint, query string, limit int) ([]model.Profile, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if utf8.RuneCountInString(query) < 2 {
+		return nil, fmt.Errorf("query must have at least 2 characters")
+	}
+	userRepository := p.uow.UserRepository()
+	users, err := userRepository.SearchUsersByPrefix(query, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("error searching users")
+	}
+	found := make(map[uint]bool, len(users))
+	profiles := make([]model.Profile, 0, limit)
+	for _, v := range users {
+		found[v.ID] = true
+		if v.ID != userId && len(profiles) < limit {
+			profiles = append(profiles, toProfileModel(v))
+		}
+	}
+	if len(profiles) == limit {
+		return profiles, nil
+	}
+
+	initial, _ := utf8.DecodeRuneInString(query)
+	candidates, err := userRepository.FindUsersByInitial(string(initial), fuzzyCandidates)
+	if err != nil {
+		return nil, fmt.Errorf("error searching users")
+	}
+	type match struct {
+		user     entity.User
+		distance int
+	}
+	matches := make([]match, 0)
+	for _, v := range candidates {
+		if found[v.ID] || v.ID == userId {
+			continue
+		}
+		if distance, ok := fuzzyDistance(query, v); ok {
+			matches = append(matches, match{user: v, distance: distance})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b match) int { return a.distance - b.distance })
+	for _, v := range matches {
+		if len(profiles) == limit {
+			break
+		}
+		profiles = append(profiles, toProfileModel(v.user))
+	}
+	return profiles, nil
+}
+
+// fuzzyDistance compares the query with the beginning of the login name and of each display name word
+// and reports the smallest edit distance, if it is small enough to be a typo.
+func fuzzyDistance(query string, user entity.User) (int, bool) {
+	maxDistance := 1
+	if utf8.RuneCountInString(query) > 4 {
+		maxDistance = 2
+	}
+	words := append([]string{user.Name}, strings.Fields(user.DisplayName)...)
+	best := maxDistance + 1
+	for _, word := range words {
+		word := []rune(strings.ToLower(word))
+		q := []rune(query)
+		for _, n := range []int{len(q) - 1, len(q), len(q) + 1} {
+			if n < 1 || n > len(word) {
+				continue
+			}
+			best = min(best, editDistance(q, word[:n]))
+		}
+	}
+	return best, best <= maxDistance
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// displayName returns the name the user chose to be shown by, falling back to the login name.
+func displayName(user entity.User) string {
+	if user.DisplayName != "" {
+		return user.DisplayName
+	}
+	return user.Name
+}
+
+func toProfileModel(user entity.User) model.Profile {
+	return model.Profile{
+		Name:        user.Name,
+		DisplayName: displayName(user),
+		AvatarURL:   user.AvatarURL,
+		Department:  user.Department,
+		Bio:         user.Bio,
+	}
+}
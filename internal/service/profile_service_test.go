@@ -0,0 +1,85 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"merch_shop/internal/model"
+	"testing"
+)
+
+func TestProfileService_UpdateProfile(t *testing.T) {
+	t.Run("ChangesPresentFields", func(t *testing.T) {
+		user := &entity.User{Model: gorm.Model{ID: 1}, Name: "jsmith42", Department: "sales", Bio: "Hi"}
+
+		userRepo := &MockUserRepository{}
+		userRepo.On("FindUserById", uint(1)).Return(user, nil)
+		userRepo.On("UpdateUserColumns", user, []string{"display_name", "avatar_url", "department", "bio"}).Return(nil)
+
+		service := NewProfileService(&MockUnitOfWork{transactionUnitOfWork: &MockTransactionUnitOfWork{UserRepo: userRepo}})
+
+		displayName, bio := "John Smith", ""
+		res, err := service.UpdateProfile(1, model.ProfileRequest{DisplayName: &displayName, Bio: &bio})
+		assert.NoError(t, err)
+		assert.Equal(t, model.Profile{Name: "jsmith42", DisplayName: "John Smith", Department: "sales"}, res)
+		userRepo.AssertExpectations(t)
+	})
+
+	t.Run("InvalidAvatarURL", func(t *testing.T) {
+		userRepo := &MockUserRepository{}
+		service := NewProfileService(&MockUnitOfWork{transactionUnitOfWork: &MockTransactionUnitOfWork{UserRepo: userRepo}})
+
+		avatarURL := "javascript:alert(1)"
+		_, err := service.UpdateProfile(1, model.ProfileRequest{AvatarURL: &avatarURL})
+		assert.EqualError(t, err, "avatar URL is not valid")
+		userRepo.AssertNotCalled(t, "UpdateUserColumns", mock.Anything, mock.Anything)
+	})
+}
+
+func TestProfileService_GetProfile(t *testing.T) {
+	userRepo := &MockUserRepository{}
+	userRepo.On("FindUserByName", "jsmith42").Return(&entity.User{Name: "jsmith42", Balance: 700, Role: entity.RoleAdmin}, nil)
+	userRepo.On("FindUserByName", "ghost").Return((*entity.User)(nil), nil)
+
+	service := NewProfileService(&MockUnitOfWork{transactionUnitOfWork: &MockTransactionUnitOfWork{UserRepo: userRepo}})
+
+	res, err := service.GetProfile("jsmith42")
+	assert.NoError(t, err)
+	assert.Equal(t, model.Profile{Name: "jsmith42", DisplayName: "jsmith42"}, res)
+
+	_, err = service.GetProfile("ghost")
+	assert.EqualError(t, err, "user not found")
+}
+
+func TestProfileService_SearchUsers(t *testing.T) {
+	t.Run("TooShort", func(t *testing.T) {
+		service := NewProfileService(&MockUnitOfWork{transactionUnitOfWork: &MockTransactionUnitOfWork{}})
+
+		_, err := service.SearchUsers(1, " j ", 10)
+		assert.EqualError(t, err, "query must have at least 2 characters")
+	})
+
+	t.Run("PrefixThenFuzzy", func(t *testing.T) {
+		me := entity.User{Model: gorm.Model{ID: 1}, Name: "jsmith42"}
+		john := entity.User{Model: gorm.Model{ID: 2}, Name: "jdoe", DisplayName: "John Doe"}
+		jsmith := entity.User{Model: gorm.Model{ID: 3}, Name: "jsmtih", DisplayName: "Jane Smith"}
+		jones := entity.User{Model: gorm.Model{ID: 4}, Name: "jones"}
+
+		userRepo := &MockUserRepository{}
+		userRepo.On("SearchUsersByPrefix", "jsmith", 3).Return([]entity.User{me}, nil)
+		userRepo.On("FindUsersByInitial", "j", fuzzyCandidates).Return([]entity.User{john, jones, jsmith, me}, nil)
+
+		service := NewProfileService(&MockUnitOfWork{transactionUnitOfWork: &MockTransactionUnitOfWork{UserRepo: userRepo}})
+
+		res, err := service.SearchUsers(1, "JSmith", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []model.Profile{{Name: "jsmtih", DisplayName: "Jane Smith"}}, res)
+	})
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance([]rune("alice"), []rune("alice")))
+	assert.Equal(t, 2, editDistance([]rune("smith"), []rune("smtih")))
+	assert.Equal(t, 1, editDistance([]rune("bob"), []rune("bobb")))
+}
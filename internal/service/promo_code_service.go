@@ -0,0 +1,132 @@
+package service
+
+import (
+	"fmt"
+	"merch_shop/internal/entity"
+	"merch_shop/internal/model"
+	"merch_shop/internal/repository"
+	"time"
+)
+
+type PromoCodeService struct {
+	uow repository.UnitOfWork
+}
+
+func NewPromoCodeService(uow repository.UnitOfWork) *PromoCodeService {
+	return &PromoCodeService{uow: uow}
+}
+
+func (p PromoCodeService) CreatePromoCode(request model.PromoCode) (model.PromoCode, error) {
+	if request.DiscountType == entity.DiscountPercent && request.Discount > 100 {
+		return model.PromoCode{}, fmt.Errorf("percentage discount cannot exceed 100")
+	}
+	if request.StartsAt != nil && request.EndsAt != nil && !request.EndsAt.After(*request.StartsAt) {
+		return model.PromoCode{}, fmt.Errorf("promo code must end after it starts")
+	}
+	if request.Item != "" && request.Category != "" {
+		return model.PromoCode{}, fmt.Errorf("promo code can be limited to an item or a category, not both")
+	}
+
+	promoCode := entity.PromoCode{
+		Code:           request.Code,
+		DiscountType:   request.DiscountType,
+		Discount:       request.Discount,
+		Category:       request.Category,
+		MaxRedemptions: request.MaxRedemptions,
+		MaxPerUser:     request.MaxPerUser,
+		StartsAt:       request.StartsAt,
+		EndsAt:         request.EndsAt,
+	}
+	if request.Item != "" {
+		item, err := p.uow.TransactionRepository().GetItemByName(request.Item)
+		if err != nil {
+			return model.PromoCode{}, fmt.Errorf("failed to find item")
+		}
+		if item == nil {
+			return model.PromoCode{}, fmt.Errorf("item not found")
+		}
+		promoCode.ItemID = &item.ID
+		promoCode.Item = item
+	}
+
+	promoCodeRepository := p.uow.PromoCodeRepository()
+	existing, err := promoCodeRepository.FindPromoCodeByCode(request.Code)
+	if err != nil {
+		return model.PromoCode{}, fmt.Errorf("failed to find promo code")
+	}
+	if existing != nil {
+		return model.PromoCode{}, fmt.Errorf("promo code already exists")
+	}
+	if err := promoCodeRepository.CreatePromoCode(&promoCode); err != nil {
+		return model.PromoCode{}, fmt.Errorf("failed to create promo code")
+	}
+	return toPromoCodeModel(promoCode), nil
+}
+
+func (p PromoCodeService) GetPromoCodes() ([]model.PromoCode, error) {
+	promoCodes, err := p.uow.PromoCodeRepository().GetPromoCodes()
+	if err != nil {
+		return nil, fmt.Errorf("error getting promo codes")
+	}
+	promoCodesModel := make([]model.PromoCode, 0, len(promoCodes))
+	for _, v := range promoCodes {
+		promoCodesModel = append(promoCodesModel, toPromoCodeModel(v))
+	}
+	return promoCodesModel, nil
+}
+
+// applyPromoCode checks that the code can be used by buyer for item right now
+// and returns the discount it gives on price.
+func applyPromoCode(tx repository.UnitOfWork, buyer *entity.User, item *entity.Item, price uint, code string, now time.Time) (*entity.PromoCode, uint, error) {
+	promoCode, err := tx.PromoCodeRepository().FindPromoCodeByCode(code)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find promo code")
+	}
+	if promoCode == nil {
+		return nil, 0, fmt.Errorf("promo code not found")
+	}
+	if promoCode.StartsAt != nil && now.Before(*promoCode.StartsAt) ||
+		promoCode.EndsAt != nil && !now.Before(*promoCode.EndsAt) {
+		return nil, 0, fmt.Errorf("promo code is not active")
+	}
+	if promoCode.ItemID != nil && *promoCode.ItemID != item.ID ||
+		promoCode.Category != "" && promoCode.Category != item.Category {
+		return nil, 0, fmt.Errorf("promo code does not apply to this item")
+	}
+	if promoCode.MaxRedemptions > 0 && promoCode.Redemptions >= promoCode.MaxRedemptions {
+		return nil, 0, fmt.Errorf("promo code redemption limit is reached")
+	}
+	if promoCode.MaxPerUser > 0 {
+		redeemed, err := tx.PurchaseRepository().CountPromoRedemptions(buyer.ID, promoCode.ID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to count promo code redemptions")
+		}
+		if redeemed >= int64(promoCode.MaxPerUser) {
+			return nil, 0, fmt.Errorf("promo code limit per user is reached")
+		}
+	}
+
+	discount := promoCode.Discount
+	if promoCode.DiscountType == entity.DiscountPercent {
+		discount = price * promoCode.Discount / 100
+	}
+	return promoCode, min(discount, price), nil
+}
+
+func toPromoCodeModel(promoCode entity.PromoCode) model.PromoCode {
+	promoCodeModel := model.PromoCode{
+		Code:           promoCode.Code,
+		DiscountType:   promoCode.DiscountType,
+		Discount:       promoCode.Discount,
+		Category:       promoCode.Category,
+		MaxRedemptions: promoCode.MaxRedemptions,
+		MaxPerUser:     promoCode.MaxPerUser,
+		Redemptions:    promoCode.Redemptions,
+		StartsAt:       promoCode.StartsAt,
+		EndsAt:         promoCode.EndsAt,
+	}
+	if promoCode.Item != nil {
+		promoCodeModel.Item = promoCode.Item.Name
+	}
+	return promoCodeModel
+}
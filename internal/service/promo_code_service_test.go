@@ -0,0 +1,162 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"merch_shop/internal/model"
+	"testing"
+	"time"
+)
+
+type MockPromoCodeRepository struct {
+	mock.Mock
+}
+
+func (m *MockPromoCodeRepository) CreatePromoCode(promoCode *entity.PromoCode) error {
+	args := m.Called(promoCode)
+	return args.Error(0)
+}
+
+func (m *MockPromoCodeRepository) FindPromoCodeByCode(code string) (*entity.PromoCode, error) {
+	args := m.Called(code)
+	return args.Get(0).(*entity.PromoCode), args.Error(1)
+}
+
+func (m *MockPromoCodeRepository) GetPromoCodes() ([]entity.PromoCode, error) {
+	args := m.Called()
+	return args.Get(0).([]entity.PromoCode), args.Error(1)
+}
+
+func (m *MockPromoCodeRepository) RedeemPromoCode(promoCodeId uint) (bool, error) {
+	args := m.Called(promoCodeId)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPromoCodeRepository) ReleasePromoCode(promoCodeId uint) error {
+	args := m.Called(promoCodeId)
+	return args.Error(0)
+}
+
+func TestPromoCodeService_CreatePromoCode(t *testing.T) {
+	t.Run("PercentOverHundred", func(t *testing.T) {
+		service := NewPromoCodeService(&MockUnitOfWork{transactionUnitOfWork: &MockTransactionUnitOfWork{}})
+
+		_, err := service.CreatePromoCode(model.PromoCode{Code: "HALF", DiscountType: entity.DiscountPercent, Discount: 150})
+		assert.EqualError(t, err, "percentage discount cannot exceed 100")
+	})
+
+	t.Run("ItemScope", func(t *testing.T) {
+		item := &entity.Item{Model: gorm.Model{ID: 3}, Name: "cup"}
+
+		transactionRepo := &MockTransactionRepository{}
+		transactionRepo.On("GetItemByName", "cup").Return(item, nil)
+
+		promoCodeRepo := &MockPromoCodeRepository{}
+		promoCodeRepo.On("FindPromoCodeByCode", "CUP10").Return((*entity.PromoCode)(nil), nil)
+		promoCodeRepo.On("CreatePromoCode", mock.MatchedBy(func(p *entity.PromoCode) bool {
+			return *p.ItemID == 3 && p.Discount == 10
+		})).Return(nil)
+
+		tuow := &MockTransactionUnitOfWork{TransactionRepo: transactionRepo, PromoCodeRepo: promoCodeRepo}
+		service := NewPromoCodeService(&MockUnitOfWork{transactionUnitOfWork: tuow})
+
+		res, err := service.CreatePromoCode(model.PromoCode{Code: "CUP10", DiscountType: entity.DiscountFixed, Discount: 10, Item: "cup"})
+		assert.NoError(t, err)
+		assert.Equal(t, "cup", res.Item)
+		promoCodeRepo.AssertExpectations(t)
+	})
+}
+
+func TestTransactionService_BuyItemPromoCode(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	item := &entity.Item{Model: gorm.Model{ID: 3}, Name: "hoody", Price: 200, Category: "apparel"}
+
+	setup := func(promoCode *entity.PromoCode) (*MockTransactionUnitOfWork, *entity.User) {
+		user := &entity.User{Model: gorm.Model{ID: 1}, Balance: 1000}
+
+		userRepo := &MockUserRepository{}
+		userRepo.On("FindUserById", uint(1)).Return(user, nil)
+		userRepo.On("UpdateUser", user).Return(nil)
+
+		transactionRepo := &MockTransactionRepository{}
+		transactionRepo.On("GetItemByName", "hoody").Return(item, nil)
+		transactionRepo.On("AddItems", uint(1), uint(3), uint(0), uint(1)).Return(nil)
+
+		itemRepo := &MockItemRepository{}
+		itemRepo.On("CountVariants", uint(3)).Return(int64(0), nil)
+		itemRepo.On("FindEffectivePrice", uint(3), mock.Anything).Return((*entity.ItemPrice)(nil), nil)
+
+		purchaseRepo := &MockPurchaseRepository{}
+		purchaseRepo.On("CountPromoRedemptions", uint(1), uint(8)).Return(int64(1), nil)
+		purchaseRepo.On("CreatePurchase", mock.Anything).Return(nil)
+
+		promoCodeRepo := &MockPromoCodeRepository{}
+		promoCodeRepo.On("FindPromoCodeByCode", "SALE").Return(promoCode, nil)
+
+		return &MockTransactionUnitOfWork{
+			UserRepo:        userRepo,
+			TransactionRepo: transactionRepo,
+			ItemRepo:        itemRepo,
+			PurchaseRepo:    purchaseRepo,
+			PromoCodeRepo:   promoCodeRepo,
+		}, user
+	}
+
+	t.Run("Expired", func(t *testing.T) {
+		endsAt := now.Add(-time.Hour)
+		tuow, _ := setup(&entity.PromoCode{Model: gorm.Model{ID: 8}, DiscountType: entity.DiscountFixed, Discount: 50, EndsAt: &endsAt})
+		service := NewTransactionService(&MockUnitOfWork{transactionUnitOfWork: tuow}, nil)
+		service.now = func() time.Time { return now }
+
+		err := service.BuyItem(1, "hoody", "", "SALE")
+		assert.EqualError(t, err, "promo code is not active")
+		assert.True(t, tuow.rollbackCalled)
+	})
+
+	t.Run("OtherCategory", func(t *testing.T) {
+		tuow, _ := setup(&entity.PromoCode{Model: gorm.Model{ID: 8}, DiscountType: entity.DiscountFixed, Discount: 50, Category: "stationery"})
+		service := NewTransactionService(&MockUnitOfWork{transactionUnitOfWork: tuow}, nil)
+		service.now = func() time.Time { return now }
+
+		err := service.BuyItem(1, "hoody", "", "SALE")
+		assert.EqualError(t, err, "promo code does not apply to this item")
+	})
+
+	t.Run("PerUserLimit", func(t *testing.T) {
+		tuow, _ := setup(&entity.PromoCode{Model: gorm.Model{ID: 8}, DiscountType: entity.DiscountFixed, Discount: 50, MaxPerUser: 1})
+		service := NewTransactionService(&MockUnitOfWork{transactionUnitOfWork: tuow}, nil)
+		service.now = func() time.Time { return now }
+
+		err := service.BuyItem(1, "hoody", "", "SALE")
+		assert.EqualError(t, err, "promo code limit per user is reached")
+	})
+
+	t.Run("RedeemedConcurrently", func(t *testing.T) {
+		tuow, user := setup(&entity.PromoCode{Model: gorm.Model{ID: 8}, DiscountType: entity.DiscountFixed, Discount: 50, MaxRedemptions: 10})
+		tuow.PromoCodeRepo.On("RedeemPromoCode", uint(8)).Return(false, nil)
+		service := NewTransactionService(&MockUnitOfWork{transactionUnitOfWork: tuow}, nil)
+		service.now = func() time.Time { return now }
+
+		err := service.BuyItem(1, "hoody", "", "SALE")
+		assert.EqualError(t, err, "promo code redemption limit is reached")
+		assert.Equal(t, uint(1000), user.Balance)
+		assert.True(t, tuow.rollbackCalled)
+	})
+
+	t.Run("PercentDiscount", func(t *testing.T) {
+		tuow, user := setup(&entity.PromoCode{Model: gorm.Model{ID: 8}, DiscountType: entity.DiscountPercent, Discount: 25, Category: "apparel"})
+		tuow.PromoCodeRepo.On("RedeemPromoCode", uint(8)).Return(true, nil)
+		service := NewTransactionService(&MockUnitOfWork{transactionUnitOfWork: tuow}, nil)
+		service.now = func() time.Time { return now }
+
+		err := service.BuyItem(1, "hoody", "", "SALE")
+		assert.NoError(t, err)
+		assert.Equal(t, uint(850), user.Balance)
+		tuow.PurchaseRepo.AssertCalled(t, "CreatePurchase", mock.MatchedBy(func(p *entity.Purchase) bool {
+			return p.Price == 150 && p.Discount == 50 && *p.PromoCodeID == 8
+		}))
+		assert.True(t, tuow.commitCalled)
+	})
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"merch_shop/internal/entity"
+	"merch_shop/internal/model"
+	"merch_shop/internal/repository"
+	"time"
+)
+
+type RefundService struct {
+	uow          repository.UnitOfWork
+	returnWindow time.Duration
+	now          func() time.Time
+}
+
+func NewRefundService(uow repository.UnitOfWork, returnWindow time.Duration) *RefundService {
+	return &RefundService{uow: uow, returnWindow: returnWindow, now: time.Now}
+}
+
+func (r RefundService) GetPurchases(userId uint) ([]model.Purchase, error) {
+	purchases, err := r.uow.PurchaseRepository().GetUserPurchases(userId)
+	if err != nil {
+		return nil, fmt.Errorf("error getting purchases")
+	}
+	return toPurchaseModels(purchases), nil
+}
+
+func (r RefundService) GetUserPurchases(userName string) ([]model.Purchase, error) {
+	user, err := r.uow.UserRepository().FindUserByName(userName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user")
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+	return r.GetPurchases(user.ID)
+}
+
+// ReturnItem returns one unit of the named item bought within the return window
+// and credits the price originally paid back to the buyer.
+func (r RefundService) ReturnItem(userId uint, name string, variant string) (model.Refund, error) {
+	tx, err := r.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return model.Refund{}, fmt.Errorf("failed to begin transaction")
+	}
+
+	item, itemVariant, err := findItem(tx, name, variant)
+	if err != nil {
+		tx.Rollback()
+		return model.Refund{}, err
+	}
+	now := r.now()
+	purchase, err := tx.PurchaseRepository().FindReturnablePurchase(userId, item.ID, variantId(itemVariant), now.Add(-r.returnWindow))
+	if err != nil {
+		tx.Rollback()
+		return model.Refund{}, fmt.Errorf("failed to find purchase")
+	}
+	if purchase == nil {
+		tx.Rollback()
+		return model.Refund{}, fmt.Errorf("no purchase of this item within the return window")
+	}
+	if err := refundPurchase(tx, purchase, nil, now); err != nil {
+		tx.Rollback()
+		return model.Refund{}, err
+	}
+
+	tx.Commit()
+	return toRefundModel(*purchase), nil
+}
+
+// RefundPurchase lets an admin refund any purchase regardless of the return window.
+func (r RefundService) RefundPurchase(adminId uint, purchaseId uint) (model.Refund, error) {
+	tx, err := r.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return model.Refund{}, fmt.Errorf("failed to begin transaction")
+	}
+
+	purchase, err := tx.PurchaseRepository().FindPurchaseById(purchaseId)
+	if err != nil {
+		tx.Rollback()
+		return model.Refund{}, fmt.Errorf("failed to find purchase")
+	}
+	if purchase == nil {
+		tx.Rollback()
+		return model.Refund{}, fmt.Errorf("purchase not found")
+	}
+	if purchase.RefundedAt != nil {
+		tx.Rollback()
+		return model.Refund{}, fmt.Errorf("purchase is already refunded")
+	}
+	if err := refundPurchase(tx, purchase, &adminId, r.now()); err != nil {
+		tx.Rollback()
+		return model.Refund{}, err
+	}
+
+	tx.Commit()
+	return toRefundModel(*purchase), nil
+}
+
+func refundPurchase(tx repository.UnitOfWork, purchase *entity.Purchase, refundedBy *uint, now time.Time) error {
+	userRepository := tx.UserRepository()
+
+	removed, err := tx.TransactionRepository().RemoveItems(purchase.OwnerId, purchase.ItemID, purchase.VariantID, 1)
+	if err != nil {
+		return fmt.Errorf("failed to remove item from inventory")
+	}
+	if !removed {
+		return fmt.Errorf("item is no longer in inventory")
+	}
+	if err := tx.ItemRepository().AddStock(purchase.ItemID, 1); err != nil {
+		return fmt.Errorf("failed to update stock")
+	}
+	if purchase.VariantID != 0 {
+		if err := tx.ItemRepository().AddVariantStock(purchase.VariantID, 1); err != nil {
+			return fmt.Errorf("failed to update stock")
+		}
+	}
+	switch {
+	case purchase.GroupPurchaseID != nil:
+		// Items bought together are refunded to everyone who pledged towards them.
+		groupPurchase, err := tx.GroupPurchaseRepository().FindGroupPurchaseById(*purchase.GroupPurchaseID)
+		if err != nil {
+			return fmt.Errorf("failed to find group purchase")
+		}
+		if groupPurchase == nil {
+			return fmt.Errorf("group purchase not found")
+		}
+		if err := returnPledges(tx, groupPurchase); err != nil {
+			return err
+		}
+	case purchase.WalletID != nil:
+		wallet, err := tx.WalletRepository().FindWalletById(*purchase.WalletID)
+		if err != nil {
+			return fmt.Errorf("failed to find wallet")
+		}
+		if wallet == nil {
+			return fmt.Errorf("wallet not found")
+		}
+		wallet.Balance += purchase.Price
+		if err := tx.WalletRepository().UpdateWallet(wallet); err != nil {
+			return fmt.Errorf("failed to update wallet")
+		}
+		// Returns are made by the owner of the item, refunds by an admin.
+		userId := purchase.OwnerId
+		if refundedBy != nil {
+			userId = *refundedBy
+		}
+		err = tx.WalletRepository().CreateWalletTransaction(&entity.WalletTransaction{
+			WalletID:   wallet.ID,
+			Type:       entity.WalletRefund,
+			UserID:     userId,
+			Amount:     purchase.Price,
+			ItemID:     &purchase.ItemID,
+			VariantID:  purchase.VariantID,
+			PurchaseID: &purchase.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create wallet transaction")
+		}
+	default:
+		buyer, err := userRepository.FindUserById(purchase.BuyerId)
+		if err != nil {
+			return fmt.Errorf("failed to find user")
+		}
+		if buyer == nil {
+			return fmt.Errorf("user not found")
+		}
+		buyer.Balance += purchase.Price
+		if err := userRepository.UpdateUser(buyer); err != nil {
+			return fmt.Errorf("failed to update user")
+		}
+	}
+	if purchase.PromoCodeID != nil {
+		// The redemption no longer counts towards the code's limits.
+		if err := tx.PromoCodeRepository().ReleasePromoCode(*purchase.PromoCodeID); err != nil {
+			return fmt.Errorf("failed to update promo code")
+		}
+	}
+	purchase.RefundedAt = &now
+	purchase.RefundedById = refundedBy
+	if purchase.FulfillmentStatus != entity.FulfillmentDelivered {
+		purchase.FulfillmentStatus = entity.FulfillmentCancelled
+		purchase.CancelledAt = &now
+	}
+	if err := tx.PurchaseRepository().UpdatePurchase(purchase); err != nil {
+		return fmt.Errorf("failed to update purchase")
+	}
+	return nil
+}
+
+func toRefundModel(purchase entity.Purchase) model.Refund {
+	refund := model.Refund{
+		PurchaseId: purchase.ID,
+		Item:       purchase.Item.Name,
+		Variant:    purchase.Variant.Name,
+		Amount:     purchase.Price,
+	}
+	if purchase.RefundedAt != nil {
+		refund.RefundedAt = *purchase.RefundedAt
+	}
+	return refund
+}
+
+func toPurchaseModels(purchases []entity.Purchase) []model.Purchase {
+	purchasesModel := make([]model.Purchase, 0, len(purchases))
+	for _, v := range purchases {
+		purchasesModel = append(purchasesModel, model.Purchase{
+			Id:          v.ID,
+			Item:        v.Item.Name,
+			Variant:     v.Variant.Name,
+			Price:       v.Price,
+			Discount:    v.Discount,
+			Status:      v.FulfillmentStatus,
+			CreatedAt:   v.CreatedAt,
+			PackedAt:    v.PackedAt,
+			ReadyAt:     v.ReadyAt,
+			DeliveredAt: v.DeliveredAt,
+			CancelledAt: v.CancelledAt,
+			RefundedAt:  v.RefundedAt,
+		})
+	}
+	return purchasesModel
+}
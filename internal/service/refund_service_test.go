@@ -0,0 +1,206 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"testing"
+	"time"
+)
+
+type MockPurchaseRepository struct {
+	mock.Mock
+}
+
+func (m *MockPurchaseRepository) CreatePurchase(purchase *entity.Purchase) error {
+	args := m.Called(purchase)
+	return args.Error(0)
+}
+
+func (m *MockPurchaseRepository) UpdatePurchase(purchase *entity.Purchase) error {
+	args := m.Called(purchase)
+	return args.Error(0)
+}
+
+func (m *MockPurchaseRepository) FindPurchaseById(purchaseId uint) (*entity.Purchase, error) {
+	args := m.Called(purchaseId)
+	return args.Get(0).(*entity.Purchase), args.Error(1)
+}
+
+func (m *MockPurchaseRepository) FindReturnablePurchase(ownerId uint, itemId uint, variantId uint, since time.Time) (*entity.Purchase, error) {
+	args := m.Called(ownerId, itemId, variantId, since)
+	return args.Get(0).(*entity.Purchase), args.Error(1)
+}
+
+func (m *MockPurchaseRepository) GetUserPurchases(ownerId uint) ([]entity.Purchase, error) {
+	args := m.Called(ownerId)
+	return args.Get(0).([]entity.Purchase), args.Error(1)
+}
+
+func (m *MockPurchaseRepository) GetRefundedPurchases(buyerId uint) ([]entity.Purchase, error) {
+	args := m.Called(buyerId)
+	return args.Get(0).([]entity.Purchase), args.Error(1)
+}
+
+func (m *MockPurchaseRepository) CountActivePurchases(buyerId uint, itemId uint) (int64, error) {
+	args := m.Called(buyerId, itemId)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPurchaseRepository) GetOpenOrders() ([]entity.Purchase, error) {
+	args := m.Called()
+	return args.Get(0).([]entity.Purchase), args.Error(1)
+}
+
+func (m *MockPurchaseRepository) CountPromoRedemptions(buyerId uint, promoCodeId uint) (int64, error) {
+	args := m.Called(buyerId, promoCodeId)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestRefundService_ReturnItem(t *testing.T) {
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	item := &entity.Item{Model: gorm.Model{ID: 3}, Name: "cup", Price: 30}
+
+	t.Run("OutsideReturnWindow", func(t *testing.T) {
+		transactionRepo := &MockTransactionRepository{}
+		transactionRepo.On("GetItemByName", "cup").Return(item, nil)
+
+		purchaseRepo := &MockPurchaseRepository{}
+		purchaseRepo.On("FindReturnablePurchase", uint(1), uint(3), uint(0), now.Add(-time.Hour*24)).Return((*entity.Purchase)(nil), nil)
+
+		tuow := &MockTransactionUnitOfWork{TransactionRepo: transactionRepo, PurchaseRepo: purchaseRepo}
+		service := NewRefundService(&MockUnitOfWork{transactionUnitOfWork: tuow}, time.Hour*24)
+		service.now = func() time.Time { return now }
+
+		_, err := service.ReturnItem(1, "cup", "")
+		assert.EqualError(t, err, "no purchase of this item within the return window")
+		assert.True(t, tuow.rollbackCalled)
+	})
+
+	t.Run("RefundsOriginalPrice", func(t *testing.T) {
+		user := &entity.User{Model: gorm.Model{ID: 1}, Balance: 100}
+		purchase := &entity.Purchase{Model: gorm.Model{ID: 9}, BuyerId: 1, OwnerId: 1, ItemID: 3, Item: *item, Price: 20}
+
+		userRepo := &MockUserRepository{}
+		userRepo.On("FindUserById", uint(1)).Return(user, nil)
+		userRepo.On("UpdateUser", user).Return(nil)
+
+		transactionRepo := &MockTransactionRepository{}
+		transactionRepo.On("GetItemByName", "cup").Return(item, nil)
+		transactionRepo.On("RemoveItems", uint(1), uint(3), uint(0), uint(1)).Return(true, nil)
+
+		purchaseRepo := &MockPurchaseRepository{}
+		purchaseRepo.On("FindReturnablePurchase", uint(1), uint(3), uint(0), now.Add(-time.Hour*24)).Return(purchase, nil)
+		purchaseRepo.On("UpdatePurchase", purchase).Return(nil)
+
+		itemRepo := &MockItemRepository{}
+		itemRepo.On("AddStock", uint(3), uint(1)).Return(nil)
+
+		tuow := &MockTransactionUnitOfWork{
+			UserRepo:        userRepo,
+			TransactionRepo: transactionRepo,
+			ItemRepo:        itemRepo,
+			PurchaseRepo:    purchaseRepo,
+		}
+		service := NewRefundService(&MockUnitOfWork{transactionUnitOfWork: tuow}, time.Hour*24)
+		service.now = func() time.Time { return now }
+
+		refund, err := service.ReturnItem(1, "cup", "")
+		assert.NoError(t, err)
+		assert.Equal(t, uint(20), refund.Amount)
+		assert.Equal(t, uint(120), user.Balance)
+		assert.Equal(t, now, *purchase.RefundedAt)
+		assert.Nil(t, purchase.RefundedById)
+		assert.True(t, tuow.commitCalled)
+	})
+}
+
+func TestRefundService_RefundPurchase(t *testing.T) {
+	t.Run("AlreadyRefunded", func(t *testing.T) {
+		refundedAt := time.Now()
+		purchase := &entity.Purchase{Model: gorm.Model{ID: 9}, RefundedAt: &refundedAt}
+
+		purchaseRepo := &MockPurchaseRepository{}
+		purchaseRepo.On("FindPurchaseById", uint(9)).Return(purchase, nil)
+
+		tuow := &MockTransactionUnitOfWork{PurchaseRepo: purchaseRepo}
+		service := NewRefundService(&MockUnitOfWork{transactionUnitOfWork: tuow}, time.Hour)
+
+		_, err := service.RefundPurchase(5, 9)
+		assert.EqualError(t, err, "purchase is already refunded")
+		assert.True(t, tuow.rollbackCalled)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		buyer := &entity.User{Model: gorm.Model{ID: 1}, Balance: 0}
+		purchase := &entity.Purchase{Model: gorm.Model{ID: 9}, BuyerId: 1, OwnerId: 2, ItemID: 3, Price: 50}
+
+		userRepo := &MockUserRepository{}
+		userRepo.On("FindUserById", uint(1)).Return(buyer, nil)
+		userRepo.On("UpdateUser", buyer).Return(nil)
+
+		transactionRepo := &MockTransactionRepository{}
+		transactionRepo.On("RemoveItems", uint(2), uint(3), uint(0), uint(1)).Return(true, nil)
+
+		purchaseRepo := &MockPurchaseRepository{}
+		purchaseRepo.On("FindPurchaseById", uint(9)).Return(purchase, nil)
+		purchaseRepo.On("UpdatePurchase", purchase).Return(nil)
+
+		itemRepo := &MockItemRepository{}
+		itemRepo.On("AddStock", uint(3), uint(1)).Return(nil)
+
+		tuow := &MockTransactionUnitOfWork{
+			UserRepo:        userRepo,
+			TransactionRepo: transactionRepo,
+			ItemRepo:        itemRepo,
+			PurchaseRepo:    purchaseRepo,
+		}
+		service := NewRefundService(&MockUnitOfWork{transactionUnitOfWork: tuow}, time.Hour)
+
+		_, err := service.RefundPurchase(5, 9)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(50), buyer.Balance)
+		assert.Equal(t, uint(5), *purchase.RefundedById)
+		assert.True(t, tuow.commitCalled)
+	})
+
+	t.Run("RefundsWallet", func(t *testing.T) {
+		walletId := uint(4)
+		wallet := &entity.Wallet{Model: gorm.Model{ID: 4}, Name: "platform", Balance: 100}
+		purchase := &entity.Purchase{Model: gorm.Model{ID: 9}, BuyerId: 1, OwnerId: 2, ItemID: 3, VariantID: 6, Price: 50, WalletID: &walletId}
+
+		transactionRepo := &MockTransactionRepository{}
+		transactionRepo.On("RemoveItems", uint(2), uint(3), uint(6), uint(1)).Return(true, nil)
+
+		purchaseRepo := &MockPurchaseRepository{}
+		purchaseRepo.On("FindPurchaseById", uint(9)).Return(purchase, nil)
+		purchaseRepo.On("UpdatePurchase", purchase).Return(nil)
+
+		itemRepo := &MockItemRepository{}
+		itemRepo.On("AddStock", uint(3), uint(1)).Return(nil)
+		itemRepo.On("AddVariantStock", uint(6), uint(1)).Return(nil)
+
+		walletRepo := &MockWalletRepository{}
+		walletRepo.On("FindWalletById", uint(4)).Return(wallet, nil)
+		walletRepo.On("UpdateWallet", wallet).Return(nil)
+		walletRepo.On("CreateWalletTransaction", mock.MatchedBy(func(transaction *entity.WalletTransaction) bool {
+			return transaction.WalletID == 4 && transaction.Type == entity.WalletRefund && transaction.UserID == 5 &&
+				transaction.Amount == 50 && *transaction.ItemID == 3 && transaction.VariantID == 6 && *transaction.PurchaseID == 9
+		})).Return(nil)
+
+		tuow := &MockTransactionUnitOfWork{
+			TransactionRepo: transactionRepo,
+			ItemRepo:        itemRepo,
+			PurchaseRepo:    purchaseRepo,
+			WalletRepo:      walletRepo,
+		}
+		service := NewRefundService(&MockUnitOfWork{transactionUnitOfWork: tuow}, time.Hour)
+
+		_, err := service.RefundPurchase(5, 9)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(150), wallet.Balance)
+		walletRepo.AssertExpectations(t)
+		assert.True(t, tuow.commitCalled)
+	})
+}
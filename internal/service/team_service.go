@@ -0,0 +1,237 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"merch_shop/internal/entity"
+	"merch_shop/internal/model"
+	"merch_shop/internal/repository"
+)
+
+type TeamService struct {
+	uow repository.UnitOfWork
+}
+
+func NewTeamService(uow repository.UnitOfWork) *TeamService {
+	return &TeamService{uow: uow}
+}
+
+func (t TeamService) GetTeams() ([]model.Team, error) {
+	teams, err := t.uow.TeamRepository().GetTeams()
+	if err != nil {
+		return nil, fmt.Errorf("error getting teams")
+	}
+	teamsModel := make([]model.Team, 0, len(teams))
+	for _, v := range teams {
+		teamsModel = append(teamsModel, toTeamModel(v))
+	}
+	return teamsModel, nil
+}
+
+func (t TeamService) GetTeam(name string) (model.Team, error) {
+	team, err := findTeam(t.uow, name)
+	if err != nil {
+		return model.Team{}, err
+	}
+	return toTeamModel(*team), nil
+}
+
+func (t TeamService) CreateTeam(name string, department string) (model.Team, error) {
+	teamRepository := t.uow.TeamRepository()
+	existing, err := teamRepository.FindTeamByName(name)
+	if err != nil {
+		return model.Team{}, fmt.Errorf("failed to find team")
+	}
+	if existing != nil {
+		return model.Team{}, fmt.Errorf("team already exists")
+	}
+	team := entity.Team{Name: name, Department: department}
+	if err := teamRepository.CreateTeam(&team); err != nil {
+		return model.Team{}, fmt.Errorf("failed to create team")
+	}
+	return toTeamModel(team), nil
+}
+
+// AddMember adds the user to the team, or updates whether an existing member manages it.
+func (t TeamService) AddMember(name string, userName string, manager bool) error {
+	team, user, err := findTeamAndUser(t.uow, name, userName)
+	if err != nil {
+		return err
+	}
+	teamRepository := t.uow.TeamRepository()
+	if err := teamRepository.AddTeamMember(&entity.TeamMember{TeamID: team.ID, UserID: user.ID}); err != nil {
+		return fmt.Errorf("failed to add team member")
+	}
+	if err := teamRepository.SetTeamManager(team.ID, user.ID, manager); err != nil {
+		return fmt.Errorf("failed to update team member")
+	}
+	return nil
+}
+
+func (t TeamService) RemoveMember(name string, userName string) error {
+	team, user, err := findTeamAndUser(t.uow, name, userName)
+	if err != nil {
+		return err
+	}
+	removed, err := t.uow.TeamRepository().RemoveTeamMember(team.ID, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to remove team member")
+	}
+	if !removed {
+		return fmt.Errorf("user is not a member of this team")
+	}
+	return nil
+}
+
+// ImportTeams creates missing teams and adds the listed users to them in a single transaction.
+// Either every row is imported or none; when validation fails the returned rows carry the reason
+// for each rejected line.
+func (t TeamService) ImportTeams(rows []model.TeamImportRow) ([]model.TeamImportRow, error) {
+	tx, err := t.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction")
+	}
+
+	userRepository := tx.UserRepository()
+	teamRepository := tx.TeamRepository()
+	users := make(map[string]*entity.User, len(rows))
+	valid := true
+	for i, row := range rows {
+		if row.Team == "" || row.User == "" {
+			rows[i].Error = "team and user are required"
+			valid = false
+			continue
+		}
+		user, found := users[row.User]
+		if !found {
+			user, err = userRepository.FindUserByName(row.User)
+			if err != nil {
+				tx.Rollback()
+				return nil, fmt.Errorf("failed to find user")
+			}
+			users[row.User] = user
+		}
+		if user == nil {
+			rows[i].Error = "user not found"
+			valid = false
+		}
+	}
+	if !valid {
+		tx.Rollback()
+		return rows, fmt.Errorf("import validation failed")
+	}
+
+	teams := make(map[string]*entity.Team)
+	for _, row := range rows {
+		team, found := teams[row.Team]
+		if !found {
+			team, err = teamRepository.FindTeamByName(row.Team)
+			if err != nil {
+				tx.Rollback()
+				return nil, fmt.Errorf("failed to find team")
+			}
+			if team == nil {
+				team = &entity.Team{Name: row.Team, Department: row.Department}
+				if err := teamRepository.CreateTeam(team); err != nil {
+					tx.Rollback()
+					return nil, fmt.Errorf("failed to create team")
+				}
+			}
+			teams[row.Team] = team
+		}
+		if row.Department != "" && row.Department != team.Department {
+			team.Department = row.Department
+			if err := teamRepository.UpdateTeam(team); err != nil {
+				tx.Rollback()
+				return nil, fmt.Errorf("failed to update team")
+			}
+		}
+		if err := teamRepository.AddTeamMember(&entity.TeamMember{TeamID: team.ID, UserID: users[row.User].ID}); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to add team member")
+		}
+	}
+
+	tx.Commit()
+	return rows, nil
+}
+
+// GetTeamStats reports the activity of the team's current members between the from and to days, both included.
+// Admins see the stats of every team, other users only those of the teams they manage.
+func (t TeamService) GetTeamStats(userId uint, role string, name string, query model.TeamStatsQuery) (model.TeamStats, error) {
+	if query.To.Before(query.From) {
+		return model.TeamStats{}, fmt.Errorf("date range is not valid")
+	}
+	team, err := findTeam(t.uow, name)
+	if err != nil {
+		return model.TeamStats{}, err
+	}
+	if role != entity.RoleAdmin && !isTeamManager(team, userId) {
+		return model.TeamStats{}, fmt.Errorf("only managers of this team can see its stats")
+	}
+	stats, err := t.uow.TeamRepository().GetTeamStats(team.ID, query.From, query.To.AddDate(0, 0, 1))
+	if err != nil {
+		return model.TeamStats{}, fmt.Errorf("error getting team stats")
+	}
+	return model.TeamStats{
+		Team:          team.Name,
+		From:          query.From,
+		To:            query.To,
+		Members:       len(team.Members),
+		CoinsReceived: stats.CoinsReceived,
+		CoinsGiven:    stats.CoinsGiven,
+		ItemsBought:   stats.ItemsBought,
+	}, nil
+}
+
+func findTeam(tx repository.UnitOfWork, name string) (*entity.Team, error) {
+	team, err := tx.TeamRepository().FindTeamByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find team")
+	}
+	if team == nil {
+		return nil, fmt.Errorf("team not found")
+	}
+	return team, nil
+}
+
+func findTeamAndUser(tx repository.UnitOfWork, name string, userName string) (*entity.Team, *entity.User, error) {
+	team, err := findTeam(tx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	user, err := tx.UserRepository().FindUserByName(userName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find user")
+	}
+	if user == nil {
+		return nil, nil, fmt.Errorf("user not found")
+	}
+	return team, user, nil
+}
+
+func isTeamManager(team *entity.Team, userId uint) bool {
+	for _, v := range team.Members {
+		if v.UserID == userId && v.Manager {
+			return true
+		}
+	}
+	return false
+}
+
+func toTeamModel(team entity.Team) model.Team {
+	members := make([]string, 0, len(team.Members))
+	managers := make([]string, 0)
+	for _, v := range team.Members {
+		members = append(members, v.User.Name)
+		if v.Manager {
+			managers = append(managers, v.User.Name)
+		}
+	}
+	return model.Team{
+		Name:       team.Name,
+		Department: team.Department,
+		Members:    members,
+		Managers:   managers,
+	}
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"merch_shop/internal/model"
+	"merch_shop/internal/repository"
+	"testing"
+	"time"
+)
+
+type MockTeamRepository struct {
+	mock.Mock
+}
+
+func (m *MockTeamRepository) CreateTeam(team *entity.Team) error {
+	args := m.Called(team)
+	return args.Error(0)
+}
+
+func (m *MockTeamRepository) UpdateTeam(team *entity.Team) error {
+	args := m.Called(team)
+	return args.Error(0)
+}
+
+func (m *MockTeamRepository) FindTeamByName(name string) (*entity.Team, error) {
+	args := m.Called(name)
+	return args.Get(0).(*entity.Team), args.Error(1)
+}
+
+func (m *MockTeamRepository) GetTeams() ([]entity.Team, error) {
+	args := m.Called()
+	return args.Get(0).([]entity.Team), args.Error(1)
+}
+
+func (m *MockTeamRepository) AddTeamMember(member *entity.TeamMember) error {
+	args := m.Called(member)
+	return args.Error(0)
+}
+
+func (m *MockTeamRepository) SetTeamManager(teamId uint, userId uint, manager bool) error {
+	args := m.Called(teamId, userId, manager)
+	return args.Error(0)
+}
+
+func (m *MockTeamRepository) RemoveTeamMember(teamId uint, userId uint) (bool, error) {
+	args := m.Called(teamId, userId)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTeamRepository) GetTeamStats(teamId uint, from time.Time, to time.Time) (repository.TeamStats, error) {
+	args := m.Called(teamId, from, to)
+	return args.Get(0).(repository.TeamStats), args.Error(1)
+}
+
+func TestTeamService_ImportTeams(t *testing.T) {
+	t.Run("UnknownUser", func(t *testing.T) {
+		userRepo := &MockUserRepository{}
+		userRepo.On("FindUserByName", "alice").Return(&entity.User{Model: gorm.Model{ID: 1}, Name: "alice"}, nil)
+		userRepo.On("FindUserByName", "nobody").Return((*entity.User)(nil), nil)
+
+		teamRepo := &MockTeamRepository{}
+
+		tuow := &MockTransactionUnitOfWork{UserRepo: userRepo, TeamRepo: teamRepo}
+		service := NewTeamService(&MockUnitOfWork{transactionUnitOfWork: tuow})
+
+		results, err := service.ImportTeams([]model.TeamImportRow{
+			{Line: 2, Team: "platform", User: "alice"},
+			{Line: 3, Team: "platform", User: "nobody"},
+			{Line: 4, Team: "", User: "alice"},
+		})
+		assert.EqualError(t, err, "import validation failed")
+		assert.Empty(t, results[0].Error)
+		assert.Equal(t, "user not found", results[1].Error)
+		assert.Equal(t, "team and user are required", results[2].Error)
+		teamRepo.AssertNotCalled(t, "AddTeamMember", mock.Anything)
+		assert.True(t, tuow.rollbackCalled)
+	})
+
+	t.Run("CreatesMissingTeams", func(t *testing.T) {
+		userRepo := &MockUserRepository{}
+		userRepo.On("FindUserByName", "alice").Return(&entity.User{Model: gorm.Model{ID: 1}, Name: "alice"}, nil)
+		userRepo.On("FindUserByName", "bob").Return(&entity.User{Model: gorm.Model{ID: 2}, Name: "bob"}, nil)
+
+		teamRepo := &MockTeamRepository{}
+		teamRepo.On("FindTeamByName", "platform").Return((*entity.Team)(nil), nil).Once()
+		teamRepo.On("CreateTeam", mock.MatchedBy(func(team *entity.Team) bool {
+			return team.Name == "platform" && team.Department == "engineering"
+		})).Run(func(args mock.Arguments) {
+			args.Get(0).(*entity.Team).ID = 4
+		}).Return(nil).Once()
+		teamRepo.On("AddTeamMember", &entity.TeamMember{TeamID: 4, UserID: 1}).Return(nil)
+		teamRepo.On("AddTeamMember", &entity.TeamMember{TeamID: 4, UserID: 2}).Return(nil)
+
+		tuow := &MockTransactionUnitOfWork{UserRepo: userRepo, TeamRepo: teamRepo}
+		service := NewTeamService(&MockUnitOfWork{transactionUnitOfWork: tuow})
+
+		_, err := service.ImportTeams([]model.TeamImportRow{
+			{Line: 1, Team: "platform", Department: "engineering", User: "alice"},
+			{Line: 2, Team: "platform", User: "bob"},
+		})
+		assert.NoError(t, err)
+		teamRepo.AssertExpectations(t)
+		assert.True(t, tuow.commitCalled)
+	})
+}
+
+func TestTeamService_GetTeamStats(t *testing.T) {
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	t.Run("InvalidRange", func(t *testing.T) {
+		service := NewTeamService(&MockUnitOfWork{transactionUnitOfWork: &MockTransactionUnitOfWork{}})
+
+		_, err := service.GetTeamStats(1, entity.RoleAdmin, "platform", model.TeamStatsQuery{From: to, To: from})
+		assert.EqualError(t, err, "date range is not valid")
+	})
+
+	t.Run("IncludesLastDay", func(t *testing.T) {
+		teamRepo := &MockTeamRepository{}
+		teamRepo.On("FindTeamByName", "platform").Return(&entity.Team{
+			Model:   gorm.Model{ID: 4},
+			Name:    "platform",
+			Members: []entity.TeamMember{{UserID: 1}, {UserID: 2}},
+		}, nil)
+		teamRepo.On("GetTeamStats", uint(4), from, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)).
+			Return(repository.TeamStats{CoinsReceived: 300, CoinsGiven: 120, ItemsBought: 2}, nil)
+
+		service := NewTeamService(&MockUnitOfWork{transactionUnitOfWork: &MockTransactionUnitOfWork{TeamRepo: teamRepo}})
+
+		res, err := service.GetTeamStats(9, entity.RoleAdmin, "platform", model.TeamStatsQuery{From: from, To: to})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, res.Members)
+		assert.Equal(t, uint(300), res.CoinsReceived)
+		assert.Equal(t, uint(120), res.CoinsGiven)
+		assert.Equal(t, int64(2), res.ItemsBought)
+	})
+
+	t.Run("Managers", func(t *testing.T) {
+		teamRepo := &MockTeamRepository{}
+		teamRepo.On("FindTeamByName", "platform").Return(&entity.Team{
+			Model:   gorm.Model{ID: 4},
+			Name:    "platform",
+			Members: []entity.TeamMember{{UserID: 1, Manager: true}, {UserID: 2}},
+		}, nil)
+		teamRepo.On("GetTeamStats", uint(4), mock.Anything, mock.Anything).Return(repository.TeamStats{CoinsReceived: 300}, nil)
+
+		service := NewTeamService(&MockUnitOfWork{transactionUnitOfWork: &MockTransactionUnitOfWork{TeamRepo: teamRepo}})
+
+		res, err := service.GetTeamStats(1, entity.RoleUser, "platform", model.TeamStatsQuery{From: from, To: to})
+		assert.NoError(t, err)
+		assert.Equal(t, uint(300), res.CoinsReceived)
+
+		_, err = service.GetTeamStats(2, entity.RoleUser, "platform", model.TeamStatsQuery{From: from, To: to})
+		assert.EqualError(t, err, "only managers of this team can see its stats")
+		_, err = service.GetTeamStats(3, entity.RoleUser, "platform", model.TeamStatsQuery{From: from, To: to})
+		assert.EqualError(t, err, "only managers of this team can see its stats")
+		teamRepo.AssertNumberOfCalls(t, "GetTeamStats", 1)
+	})
+}
+
+func TestTeamService_AddMember(t *testing.T) {
+	teamRepo := &MockTeamRepository{}
+	teamRepo.On("FindTeamByName", "platform").Return(&entity.Team{Model: gorm.Model{ID: 4}, Name: "platform"}, nil)
+	teamRepo.On("AddTeamMember", &entity.TeamMember{TeamID: 4, UserID: 1}).Return(nil)
+	teamRepo.On("SetTeamManager", uint(4), uint(1), true).Return(nil)
+
+	userRepo := &MockUserRepository{}
+	userRepo.On("FindUserByName", "alice").Return(&entity.User{Model: gorm.Model{ID: 1}, Name: "alice"}, nil)
+
+	service := NewTeamService(&MockUnitOfWork{transactionUnitOfWork: &MockTransactionUnitOfWork{UserRepo: userRepo, TeamRepo: teamRepo}})
+
+	assert.NoError(t, service.AddMember("platform", "alice", true))
+	teamRepo.AssertExpectations(t)
+}
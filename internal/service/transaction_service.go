@@ -3,17 +3,21 @@ package service
 import (
 	"database/sql"
 	"fmt"
+	"merch_shop/internal/config"
 	"merch_shop/internal/entity"
 	"merch_shop/internal/model"
 	"merch_shop/internal/repository"
+	"time"
 )
 
 type TransactionService struct {
-	uow repository.UnitOfWork
+	uow   repository.UnitOfWork
+	rules []config.AchievementRule
+	now   func() time.Time
 }
 
-func NewTransactionService(uow repository.UnitOfWork) *TransactionService {
-	return &TransactionService{uow: uow}
+func NewTransactionService(uow repository.UnitOfWork, rules []config.AchievementRule) *TransactionService {
+	return &TransactionService{uow: uow, rules: rules, now: time.Now}
 }
 
 func (t TransactionService) GetInfo(userId uint) (model.InfoResponse, error) {
@@ -44,35 +48,101 @@ func (t TransactionService) GetInfo(userId uint) (model.InfoResponse, error) {
 	if err != nil {
 		return model.InfoResponse{}, fmt.Errorf("error getting inventory")
 	}
+	itemIncome, err := transactionRepository.GetIncomeItemTransfers(userId)
+	if err != nil {
+		return model.InfoResponse{}, fmt.Errorf("error getting income item transfers")
+	}
+	itemOutcome, err := transactionRepository.GetOutcomeItemTransfers(userId)
+	if err != nil {
+		return model.InfoResponse{}, fmt.Errorf("error getting outcome item transfers")
+	}
+	refunds, err := tx.PurchaseRepository().GetRefundedPurchases(userId)
+	if err != nil {
+		return model.InfoResponse{}, fmt.Errorf("error getting refunds")
+	}
+	badges, err := tx.AchievementRepository().GetUserBadges(userId)
+	if err != nil {
+		return model.InfoResponse{}, fmt.Errorf("error getting badges")
+	}
 	inventoryModel := make([]model.Inventory, 0, len(inventory))
 	for _, v := range inventory {
 		inventoryModel = append(inventoryModel, model.Inventory{
 			Name:     v.Item.Name,
+			Variant:  v.Variant.Name,
 			Quantity: v.Quantity,
 		})
 	}
 	outcomeModel := make([]model.CoinHistorySent, 0, len(outcome))
 	for _, v := range outcome {
 		outcomeModel = append(outcomeModel, model.CoinHistorySent{
-			ToUser: v.ToUser.Name,
-			Amount: v.Amount,
+			ToUser:        v.ToUser.Name,
+			ToDisplayName: displayName(v.ToUser),
+			Amount:        v.Amount,
+			Wallet:        walletName(v.Wallet),
 		})
 	}
 	incomeModel := make([]model.CoinHistoryReceived, 0, len(income))
 	for _, v := range income {
 		incomeModel = append(incomeModel, model.CoinHistoryReceived{
-			FromUser: v.FromUser.Name,
-			Amount:   v.Amount,
+			FromUser:        v.FromUser.Name,
+			FromDisplayName: displayName(v.FromUser),
+			Amount:          v.Amount,
+			Wallet:          walletName(v.Wallet),
 		})
 	}
+	bonusesModel := make([]model.CoinHistoryBonus, 0)
+	for _, v := range badges {
+		if v.Bonus > 0 {
+			bonusesModel = append(bonusesModel, model.CoinHistoryBonus{Badge: v.Badge, Amount: v.Bonus, AwardedAt: v.CreatedAt})
+		}
+	}
 	coinHistoryModel := model.CoinHistory{
 		Received: incomeModel,
 		Sent:     outcomeModel,
+		Bonuses:  bonusesModel,
+	}
+	itemIncomeModel := make([]model.ItemHistoryReceived, 0, len(itemIncome))
+	for _, v := range itemIncome {
+		itemIncomeModel = append(itemIncomeModel, model.ItemHistoryReceived{
+			FromUser:        v.FromUser.Name,
+			FromDisplayName: displayName(v.FromUser),
+			Item:            v.Item.Name,
+			Variant:         v.Variant.Name,
+			Quantity:        v.Quantity,
+			Gift:            v.Gift,
+		})
+	}
+	itemOutcomeModel := make([]model.ItemHistorySent, 0, len(itemOutcome))
+	for _, v := range itemOutcome {
+		itemOutcomeModel = append(itemOutcomeModel, model.ItemHistorySent{
+			ToUser:        v.ToUser.Name,
+			ToDisplayName: displayName(v.ToUser),
+			Item:          v.Item.Name,
+			Variant:       v.Variant.Name,
+			Quantity:      v.Quantity,
+			Gift:          v.Gift,
+		})
+	}
+	itemHistoryModel := model.ItemHistory{
+		Received: itemIncomeModel,
+		Sent:     itemOutcomeModel,
+	}
+	wishlist, err := wishlistModel(tx, user, t.now())
+	if err != nil {
+		return model.InfoResponse{}, err
+	}
+	refundsModel := make([]model.Refund, 0, len(refunds))
+	for _, v := range refunds {
+		refundsModel = append(refundsModel, toRefundModel(v))
 	}
 	infoResponse := model.InfoResponse{
 		Coins:       user.Balance,
 		Inventory:   inventoryModel,
 		CoinHistory: coinHistoryModel,
+		ItemHistory: itemHistoryModel,
+		Refunds:     refundsModel,
+		Wishlist:    wishlist,
+		Badges:      badgesModel(badges),
 	}
 	return infoResponse, nil
 }
@@ -84,7 +154,6 @@ func (t TransactionService) SendCoin(userId uint, toUserName string, amount uint
 	}
 
 	userRepository := tx.UserRepository()
-	transactionRepository := tx.TransactionRepository()
 
 	fromUser, err := userRepository.FindUserById(userId)
 	if err != nil {
@@ -105,49 +174,141 @@ func (t TransactionService) SendCoin(userId uint, toUserName string, amount uint
 		return fmt.Errorf("user not found")
 	}
 
-	if toUser.ID == fromUser.ID {
+	if _, err := transferCoins(tx, fromUser, toUser, amount, t.now()); err != nil {
 		tx.Rollback()
-		return fmt.Errorf("cannot send coin to yourself")
+		return err
 	}
-	if fromUser.Balance < amount {
-		tx.Rollback()
-		return fmt.Errorf("insufficient balance")
+	for _, user := range []*entity.User{fromUser, toUser} {
+		if _, err := awardAchievements(tx, t.rules, user, t.now()); err != nil {
+			tx.Rollback()
+			return err
+		}
 	}
-	fromUser.Balance -= amount
-	toUser.Balance += amount
-	err = userRepository.UpdateUser(fromUser)
+	tx.Commit()
+	return nil
+
+}
+
+// SendCoinBatch sends coins to several recipients in a single transaction.
+// Either every transfer succeeds or none is applied; when validation fails the
+// returned results carry the reason for each rejected recipient.
+func (t TransactionService) SendCoinBatch(userId uint, transfers []model.SendCoinRequest) ([]model.SendCoinBatchResult, error) {
+	tx, err := t.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
 	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to update user")
+		return nil, fmt.Errorf("failed to begin transaction")
 	}
-	err = userRepository.UpdateUser(toUser)
+
+	userRepository := tx.UserRepository()
+	fromUser, err := userRepository.FindUserById(userId)
 	if err != nil {
 		tx.Rollback()
-		return fmt.Errorf("failed to update user")
+		return nil, fmt.Errorf("failed to find user")
+	}
+	if fromUser == nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("user not found")
+	}
+
+	results := make([]model.SendCoinBatchResult, 0, len(transfers))
+	recipients := make(map[string]*entity.User, len(transfers))
+	var total uint
+	valid := true
+	for _, transfer := range transfers {
+		result := model.SendCoinBatchResult{ToUser: transfer.ToUser, Amount: transfer.Amount}
+		toUser, found := recipients[transfer.ToUser]
+		if !found {
+			toUser, err = userRepository.FindUserByName(transfer.ToUser)
+			if err != nil {
+				tx.Rollback()
+				return nil, fmt.Errorf("failed to find user")
+			}
+			recipients[transfer.ToUser] = toUser
+		}
+		switch {
+		case toUser == nil:
+			result.Error = "user not found"
+		case toUser.ID == fromUser.ID:
+			result.Error = "cannot send coin to yourself"
+		case transfer.Amount == 0:
+			result.Error = "amount must be positive"
+		}
+		if result.Error != "" {
+			valid = false
+		}
+		total += transfer.Amount
+		results = append(results, result)
+	}
+	if !valid {
+		tx.Rollback()
+		return results, fmt.Errorf("batch validation failed")
+	}
+	if fromUser.Balance < total {
+		tx.Rollback()
+		return results, fmt.Errorf("insufficient balance")
+	}
+
+	for _, transfer := range transfers {
+		if _, err := transferCoins(tx, fromUser, recipients[transfer.ToUser], transfer.Amount, t.now()); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if _, err := awardAchievements(tx, t.rules, fromUser, t.now()); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	for _, toUser := range recipients {
+		if _, err := awardAchievements(tx, t.rules, toUser, t.now()); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	tx.Commit()
+	return results, nil
+}
+
+// transferCoins moves amount from fromUser to toUser, records the transaction and notifies
+// toUser about wishlisted items that became affordable.
+// The caller owns tx and is responsible for rolling it back on error.
+func transferCoins(tx repository.UnitOfWork, fromUser, toUser *entity.User, amount uint, now time.Time) (*entity.Transaction, error) {
+	userRepository := tx.UserRepository()
+	transactionRepository := tx.TransactionRepository()
+
+	if toUser.ID == fromUser.ID {
+		return nil, fmt.Errorf("cannot send coin to yourself")
+	}
+	if fromUser.Balance < amount {
+		return nil, fmt.Errorf("insufficient balance")
+	}
+	previousBalance := toUser.Balance
+	fromUser.Balance -= amount
+	toUser.Balance += amount
+	if err := userRepository.UpdateUser(fromUser); err != nil {
+		return nil, fmt.Errorf("failed to update user")
+	}
+	if err := userRepository.UpdateUser(toUser); err != nil {
+		return nil, fmt.Errorf("failed to update user")
 	}
 	transaction := entity.Transaction{
 		FromId: fromUser.ID,
 		ToId:   toUser.ID,
 		Amount: amount,
 	}
-	err = transactionRepository.CreateTransaction(&transaction)
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to create transaction")
+	if err := transactionRepository.CreateTransaction(&transaction); err != nil {
+		return nil, fmt.Errorf("failed to create transaction")
 	}
-	tx.Commit()
-	return nil
-
+	if err := notifyAffordable(tx, toUser, previousBalance, now); err != nil {
+		return nil, err
+	}
+	return &transaction, nil
 }
 
-func (t TransactionService) BuyItem(userId uint, name string) error {
+func (t TransactionService) BuyItem(userId uint, name string, variant string, promoCode string) error {
 	tx, err := t.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction")
 	}
-	userRepository := tx.UserRepository()
-	transactionRepository := tx.TransactionRepository()
-	user, err := userRepository.FindUserById(userId)
+	user, err := tx.UserRepository().FindUserById(userId)
 	if err != nil {
 		tx.Rollback()
 		return fmt.Errorf("failed to find user")
@@ -156,31 +317,307 @@ func (t TransactionService) BuyItem(userId uint, name string) error {
 		tx.Rollback()
 		return fmt.Errorf("user not found")
 	}
-	item, err := transactionRepository.GetItemByName(name)
+	if _, err := buyItem(tx, user, user, nil, name, variant, promoCode, t.now()); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := awardAchievements(tx, t.rules, user, t.now()); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	tx.Commit()
+	return nil
+}
+
+// GiftItem buys an item with the user's coins and puts it straight into the recipient's inventory.
+func (t TransactionService) GiftItem(userId uint, name string, variant string, toUserName string, promoCode string) error {
+	tx, err := t.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction")
+	}
+	fromUser, toUser, err := findTransferParties(tx, userId, toUserName)
 	if err != nil {
 		tx.Rollback()
-		return fmt.Errorf("failed to find item")
+		return err
 	}
-	if item == nil {
+	if _, err := buyItem(tx, fromUser, toUser, nil, name, variant, promoCode, t.now()); err != nil {
 		tx.Rollback()
-		return fmt.Errorf("item not found")
+		return err
+	}
+	if _, err := awardAchievements(tx, t.rules, fromUser, t.now()); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	tx.Commit()
+	return nil
+}
+
+// TransferItem moves owned inventory units to another user.
+func (t TransactionService) TransferItem(userId uint, name string, variant string, toUserName string, quantity uint) error {
+	tx, err := t.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction")
 	}
-	if user.Balance < item.Price {
+	transactionRepository := tx.TransactionRepository()
+	fromUser, toUser, err := findTransferParties(tx, userId, toUserName)
+	if err != nil {
 		tx.Rollback()
-		return fmt.Errorf("insufficient balance")
+		return err
 	}
-	user.Balance -= item.Price
-	err = userRepository.UpdateUser(user)
+	item, itemVariant, err := findItem(tx, name, variant)
 	if err != nil {
 		tx.Rollback()
-		return fmt.Errorf("failed to update user")
+		return err
 	}
-	err = transactionRepository.AddItem(user.ID, item.ID)
+	removed, err := transactionRepository.RemoveItems(fromUser.ID, item.ID, variantId(itemVariant), quantity)
 	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to remove item from inventory")
+	}
+	if !removed {
+		tx.Rollback()
+		return fmt.Errorf("not enough items in inventory")
+	}
+	if err := transactionRepository.AddItems(toUser.ID, item.ID, variantId(itemVariant), quantity); err != nil {
 		tx.Rollback()
 		return fmt.Errorf("failed to add item to inventory: %v", err)
 	}
+	err = transactionRepository.CreateItemTransfer(&entity.ItemTransfer{
+		FromId:    fromUser.ID,
+		ToId:      toUser.ID,
+		ItemID:    item.ID,
+		VariantID: variantId(itemVariant),
+		Quantity:  quantity,
+	})
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to create item transfer")
+	}
 
 	tx.Commit()
 	return nil
 }
+
+func findTransferParties(tx repository.UnitOfWork, userId uint, toUserName string) (*entity.User, *entity.User, error) {
+	userRepository := tx.UserRepository()
+	fromUser, err := userRepository.FindUserById(userId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find user")
+	}
+	if fromUser == nil {
+		return nil, nil, fmt.Errorf("user not found")
+	}
+	toUser, err := userRepository.FindUserByName(toUserName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find user")
+	}
+	if toUser == nil {
+		return nil, nil, fmt.Errorf("user not found")
+	}
+	if toUser.ID == fromUser.ID {
+		return nil, nil, fmt.Errorf("cannot send item to yourself")
+	}
+	return fromUser, toUser, nil
+}
+
+// findItem looks up an item and, when variantName is set, one of its variants.
+func findItem(tx repository.UnitOfWork, name string, variantName string) (*entity.Item, *entity.ItemVariant, error) {
+	item, err := tx.TransactionRepository().GetItemByName(name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find item")
+	}
+	if item == nil {
+		return nil, nil, fmt.Errorf("item not found")
+	}
+	if variantName == "" {
+		return item, nil, nil
+	}
+	variant, err := tx.ItemRepository().FindVariant(item.ID, variantName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find variant")
+	}
+	if variant == nil {
+		return nil, nil, fmt.Errorf("variant not found")
+	}
+	return item, variant, nil
+}
+
+func variantId(variant *entity.ItemVariant) uint {
+	if variant == nil {
+		return 0
+	}
+	return variant.ID
+}
+
+func promoCodeId(promoCode *entity.PromoCode) *uint {
+	if promoCode == nil {
+		return nil
+	}
+	return &promoCode.ID
+}
+
+// variantPrice applies the variant's price delta, never going below zero.
+func variantPrice(price uint, variant *entity.ItemVariant) uint {
+	if variant == nil {
+		return price
+	}
+	if variant.PriceDelta < 0 && uint(-variant.PriceDelta) > price {
+		return 0
+	}
+	return uint(int(price) + variant.PriceDelta)
+}
+
+// buyItem charges buyer, or the wallet when one is given, for the named item, less the promo code discount
+// when a code is given, and adds it to owner's inventory. A purchase for someone else is recorded as a gift
+// in the item history.
+func buyItem(tx repository.UnitOfWork, buyer, owner *entity.User, wallet *entity.Wallet, name string, variantName string, code string, now time.Time) (*entity.Purchase, error) {
+	userRepository := tx.UserRepository()
+	transactionRepository := tx.TransactionRepository()
+	item, variant, err := findItem(tx, name, variantName)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAvailable(tx, item, variant); err != nil {
+		return nil, err
+	}
+	if err := checkPurchaseLimit(tx, buyer.ID, item); err != nil {
+		return nil, err
+	}
+	basePrice, err := effectivePrice(tx, item, now)
+	if err != nil {
+		return nil, err
+	}
+	price := variantPrice(basePrice, variant)
+	var promoCode *entity.PromoCode
+	var discount uint
+	if code != "" {
+		promoCode, discount, err = applyPromoCode(tx, buyer, item, price, code, now)
+		if err != nil {
+			return nil, err
+		}
+		price -= discount
+	}
+	balance := buyer.Balance
+	if wallet != nil {
+		balance = wallet.Balance
+	}
+	if balance < price {
+		return nil, fmt.Errorf("insufficient balance")
+	}
+	if err := takeStock(tx, item, variant); err != nil {
+		return nil, err
+	}
+	if promoCode != nil {
+		// The conditional increment keeps concurrent purchases from exceeding the redemption limit.
+		redeemed, err := tx.PromoCodeRepository().RedeemPromoCode(promoCode.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to redeem promo code")
+		}
+		if !redeemed {
+			return nil, fmt.Errorf("promo code redemption limit is reached")
+		}
+	}
+	if wallet != nil {
+		wallet.Balance -= price
+		if err := tx.WalletRepository().UpdateWallet(wallet); err != nil {
+			return nil, fmt.Errorf("failed to update wallet")
+		}
+	} else {
+		buyer.Balance -= price
+		if err := userRepository.UpdateUser(buyer); err != nil {
+			return nil, fmt.Errorf("failed to update user")
+		}
+	}
+	if err := transactionRepository.AddItems(owner.ID, item.ID, variantId(variant), 1); err != nil {
+		return nil, fmt.Errorf("failed to add item to inventory: %v", err)
+	}
+	purchase := entity.Purchase{
+		BuyerId:           buyer.ID,
+		OwnerId:           owner.ID,
+		ItemID:            item.ID,
+		VariantID:         variantId(variant),
+		Price:             price,
+		Discount:          discount,
+		PromoCodeID:       promoCodeId(promoCode),
+		FulfillmentStatus: entity.FulfillmentPlaced,
+	}
+	if wallet != nil {
+		purchase.WalletID = &wallet.ID
+	}
+	if err := tx.PurchaseRepository().CreatePurchase(&purchase); err != nil {
+		return nil, fmt.Errorf("failed to create purchase")
+	}
+	if buyer.ID != owner.ID {
+		err = transactionRepository.CreateItemTransfer(&entity.ItemTransfer{
+			FromId:    buyer.ID,
+			ToId:      owner.ID,
+			ItemID:    item.ID,
+			VariantID: variantId(variant),
+			Quantity:  1,
+			Gift:      true,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create item transfer")
+		}
+	}
+	return &purchase, nil
+}
+
+// checkAvailable makes sure a variant is chosen when the item has variants and that the item is in stock.
+func checkAvailable(tx repository.UnitOfWork, item *entity.Item, variant *entity.ItemVariant) error {
+	if variant == nil {
+		variants, err := tx.ItemRepository().CountVariants(item.ID)
+		if err != nil {
+			return fmt.Errorf("failed to find variant")
+		}
+		if variants > 0 {
+			return fmt.Errorf("variant is required for this item")
+		}
+	}
+	if item.Stock != nil && *item.Stock == 0 || variant != nil && variant.Stock != nil && *variant.Stock == 0 {
+		return fmt.Errorf("item is out of stock")
+	}
+	return nil
+}
+
+// checkPurchaseLimit makes sure the user has not bought as many units of the item as one user may.
+func checkPurchaseLimit(tx repository.UnitOfWork, userId uint, item *entity.Item) error {
+	if item.PurchaseLimit == 0 {
+		return nil
+	}
+	bought, err := tx.PurchaseRepository().CountActivePurchases(userId, item.ID)
+	if err != nil {
+		return fmt.Errorf("failed to count purchases")
+	}
+	if bought >= int64(item.PurchaseLimit) {
+		return fmt.Errorf("purchase limit for this item is reached")
+	}
+	return nil
+}
+
+// takeStock removes one unit from the tracked stock of the item and its variant.
+// The conditional decrements guard against concurrent purchases of the last units.
+func takeStock(tx repository.UnitOfWork, item *entity.Item, variant *entity.ItemVariant) error {
+	itemRepository := tx.ItemRepository()
+	if item.Stock != nil {
+		inStock, err := itemRepository.DecrementStock(item.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update stock")
+		}
+		if !inStock {
+			return fmt.Errorf("item is out of stock")
+		}
+	}
+	if variant != nil && variant.Stock != nil {
+		inStock, err := itemRepository.DecrementVariantStock(variant.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update stock")
+		}
+		if !inStock {
+			return fmt.Errorf("item is out of stock")
+		}
+	}
+	return nil
+}
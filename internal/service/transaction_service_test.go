@@ -9,6 +9,7 @@ import (
 	"merch_shop/internal/model"
 	"merch_shop/internal/repository"
 	"testing"
+	"time"
 )
 
 // Mock repositories and unit of work
@@ -27,6 +28,11 @@ func (m *MockUserRepository) UpdateUser(user *entity.User) error {
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) UpdateUserColumns(user *entity.User, columns ...string) error {
+	args := m.Called(user, columns)
+	return args.Error(0)
+}
+
 func (m *MockUserRepository) FindUserByName(name string) (*entity.User, error) {
 	args := m.Called(name)
 	return args.Get(0).(*entity.User), args.Error(1)
@@ -37,6 +43,31 @@ func (m *MockUserRepository) FindUserById(userId uint) (*entity.User, error) {
 	return args.Get(0).(*entity.User), args.Error(1)
 }
 
+func (m *MockUserRepository) FindUserByResetToken(tokenHash string) (*entity.User, error) {
+	args := m.Called(tokenHash)
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindUserByOIDCSubject(subject string) (*entity.User, error) {
+	args := m.Called(subject)
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindUserByEmail(email string) (*entity.User, error) {
+	args := m.Called(email)
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *MockUserRepository) SearchUsersByPrefix(prefix string, limit int) ([]entity.User, error) {
+	args := m.Called(prefix, limit)
+	return args.Get(0).([]entity.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindUsersByInitial(initial string, limit int) ([]entity.User, error) {
+	args := m.Called(initial, limit)
+	return args.Get(0).([]entity.User), args.Error(1)
+}
+
 type MockTransactionRepository struct {
 	mock.Mock
 }
@@ -46,6 +77,31 @@ func (m *MockTransactionRepository) AddItem(userId uint, itemId uint) error {
 	return args.Error(0)
 }
 
+func (m *MockTransactionRepository) AddItems(userId uint, itemId uint, variantId uint, quantity uint) error {
+	args := m.Called(userId, itemId, variantId, quantity)
+	return args.Error(0)
+}
+
+func (m *MockTransactionRepository) RemoveItems(userId uint, itemId uint, variantId uint, quantity uint) (bool, error) {
+	args := m.Called(userId, itemId, variantId, quantity)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTransactionRepository) CreateItemTransfer(transfer *entity.ItemTransfer) error {
+	args := m.Called(transfer)
+	return args.Error(0)
+}
+
+func (m *MockTransactionRepository) GetIncomeItemTransfers(userId uint) ([]entity.ItemTransfer, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]entity.ItemTransfer), args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetOutcomeItemTransfers(userId uint) ([]entity.ItemTransfer, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]entity.ItemTransfer), args.Error(1)
+}
+
 func (m *MockTransactionRepository) GetItemByName(name string) (*entity.Item, error) {
 	args := m.Called(name)
 	return args.Get(0).(*entity.Item), args.Error(1)
@@ -71,11 +127,125 @@ func (m *MockTransactionRepository) GetUserInventory(userId uint) ([]entity.Inve
 	return args.Get(0).([]entity.InventoryItem), args.Error(1)
 }
 
+type MockItemRepository struct {
+	mock.Mock
+}
+
+func (m *MockItemRepository) SetItemCategory(itemId uint, category string) error {
+	args := m.Called(itemId, category)
+	return args.Error(0)
+}
+
+func (m *MockItemRepository) SetPurchaseLimit(itemId uint, purchaseLimit uint) error {
+	args := m.Called(itemId, purchaseLimit)
+	return args.Error(0)
+}
+
+func (m *MockItemRepository) DecrementStock(itemId uint) (bool, error) {
+	args := m.Called(itemId)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockItemRepository) AddStock(itemId uint, quantity uint) error {
+	args := m.Called(itemId, quantity)
+	return args.Error(0)
+}
+
+func (m *MockItemRepository) RestockItem(itemId uint, quantity uint) error {
+	args := m.Called(itemId, quantity)
+	return args.Error(0)
+}
+
+func (m *MockItemRepository) CreateVariant(variant *entity.ItemVariant) error {
+	args := m.Called(variant)
+	return args.Error(0)
+}
+
+func (m *MockItemRepository) FindVariant(itemId uint, name string) (*entity.ItemVariant, error) {
+	args := m.Called(itemId, name)
+	return args.Get(0).(*entity.ItemVariant), args.Error(1)
+}
+
+func (m *MockItemRepository) CountVariants(itemId uint) (int64, error) {
+	args := m.Called(itemId)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockItemRepository) DecrementVariantStock(variantId uint) (bool, error) {
+	args := m.Called(variantId)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockItemRepository) AddVariantStock(variantId uint, quantity uint) error {
+	args := m.Called(variantId, quantity)
+	return args.Error(0)
+}
+
+func (m *MockItemRepository) RestockVariant(variantId uint, quantity uint) error {
+	args := m.Called(variantId, quantity)
+	return args.Error(0)
+}
+
+func (m *MockItemRepository) GetItems(category string, tags []string) ([]entity.Item, error) {
+	args := m.Called(category, tags)
+	return args.Get(0).([]entity.Item), args.Error(1)
+}
+
+func (m *MockItemRepository) ReplaceItemTags(itemId uint, tags []string) error {
+	args := m.Called(itemId, tags)
+	return args.Error(0)
+}
+
+func (m *MockItemRepository) CreateCategory(category *entity.Category) error {
+	args := m.Called(category)
+	return args.Error(0)
+}
+
+func (m *MockItemRepository) FindCategoryByName(name string) (*entity.Category, error) {
+	args := m.Called(name)
+	return args.Get(0).(*entity.Category), args.Error(1)
+}
+
+func (m *MockItemRepository) GetCategories() ([]entity.Category, error) {
+	args := m.Called()
+	return args.Get(0).([]entity.Category), args.Error(1)
+}
+
+func (m *MockItemRepository) CreateItemPrice(price *entity.ItemPrice) error {
+	args := m.Called(price)
+	return args.Error(0)
+}
+
+func (m *MockItemRepository) FindEffectivePrice(itemId uint, at time.Time) (*entity.ItemPrice, error) {
+	args := m.Called(itemId, at)
+	return args.Get(0).(*entity.ItemPrice), args.Error(1)
+}
+
+func (m *MockItemRepository) GetItemPrices(itemId uint) ([]entity.ItemPrice, error) {
+	args := m.Called(itemId)
+	return args.Get(0).([]entity.ItemPrice), args.Error(1)
+}
+
 type MockTransactionUnitOfWork struct {
-	UserRepo        *MockUserRepository
-	TransactionRepo *MockTransactionRepository
-	commitCalled    bool
-	rollbackCalled  bool
+	UserRepo           *MockUserRepository
+	TransactionRepo    *MockTransactionRepository
+	ItemRepo           *MockItemRepository
+	PaymentRequestRepo *MockPaymentRequestRepository
+	PurchaseRepo       *MockPurchaseRepository
+	PromoCodeRepo      *MockPromoCodeRepository
+	WishlistRepo       *MockWishlistRepository
+	NotificationRepo   *MockNotificationRepository
+	GroupPurchaseRepo  *MockGroupPurchaseRepository
+	WalletRepo         *MockWalletRepository
+	TeamRepo           *MockTeamRepository
+	LeaderboardRepo    *MockLeaderboardRepository
+	AchievementRepo    *MockAchievementRepository
+	LoginThrottleRepo  *MockLoginThrottleRepository
+	RecoveryCodeRepo   *MockRecoveryCodeRepository
+	APIKeyRepo         *MockAPIKeyRepository
+	OIDCLoginRepo      *MockOIDCLoginRepository
+	commitCalled       bool
+	rollbackCalled     bool
 }
 
 func (m *MockTransactionUnitOfWork) BeginTransaction(opts ...*sql.TxOptions) (repository.TransactionUnitOfWork, error) {
@@ -100,6 +270,66 @@ func (m *MockTransactionUnitOfWork) TransactionRepository() repository.Transacti
 	return m.TransactionRepo
 }
 
+func (m *MockTransactionUnitOfWork) ItemRepository() repository.ItemRepository {
+	return m.ItemRepo
+}
+
+func (m *MockTransactionUnitOfWork) PaymentRequestRepository() repository.PaymentRequestRepository {
+	return m.PaymentRequestRepo
+}
+
+func (m *MockTransactionUnitOfWork) PurchaseRepository() repository.PurchaseRepository {
+	return m.PurchaseRepo
+}
+
+func (m *MockTransactionUnitOfWork) PromoCodeRepository() repository.PromoCodeRepository {
+	return m.PromoCodeRepo
+}
+
+func (m *MockTransactionUnitOfWork) WishlistRepository() repository.WishlistRepository {
+	return m.WishlistRepo
+}
+
+func (m *MockTransactionUnitOfWork) NotificationRepository() repository.NotificationRepository {
+	return m.NotificationRepo
+}
+
+func (m *MockTransactionUnitOfWork) GroupPurchaseRepository() repository.GroupPurchaseRepository {
+	return m.GroupPurchaseRepo
+}
+
+func (m *MockTransactionUnitOfWork) WalletRepository() repository.WalletRepository {
+	return m.WalletRepo
+}
+
+func (m *MockTransactionUnitOfWork) TeamRepository() repository.TeamRepository {
+	return m.TeamRepo
+}
+
+func (m *MockTransactionUnitOfWork) LeaderboardRepository() repository.LeaderboardRepository {
+	return m.LeaderboardRepo
+}
+
+func (m *MockTransactionUnitOfWork) AchievementRepository() repository.AchievementRepository {
+	return m.AchievementRepo
+}
+
+func (m *MockTransactionUnitOfWork) LoginThrottleRepository() repository.LoginThrottleRepository {
+	return m.LoginThrottleRepo
+}
+
+func (m *MockTransactionUnitOfWork) RecoveryCodeRepository() repository.RecoveryCodeRepository {
+	return m.RecoveryCodeRepo
+}
+
+func (m *MockTransactionUnitOfWork) APIKeyRepository() repository.APIKeyRepository {
+	return m.APIKeyRepo
+}
+
+func (m *MockTransactionUnitOfWork) OIDCLoginRepository() repository.OIDCLoginRepository {
+	return m.OIDCLoginRepo
+}
+
 type MockUnitOfWork struct {
 	transactionUnitOfWork *MockTransactionUnitOfWork
 }
@@ -116,6 +346,66 @@ func (m *MockUnitOfWork) TransactionRepository() repository.TransactionRepositor
 	return m.transactionUnitOfWork.TransactionRepo
 }
 
+func (m *MockUnitOfWork) ItemRepository() repository.ItemRepository {
+	return m.transactionUnitOfWork.ItemRepo
+}
+
+func (m *MockUnitOfWork) PaymentRequestRepository() repository.PaymentRequestRepository {
+	return m.transactionUnitOfWork.PaymentRequestRepo
+}
+
+func (m *MockUnitOfWork) PurchaseRepository() repository.PurchaseRepository {
+	return m.transactionUnitOfWork.PurchaseRepo
+}
+
+func (m *MockUnitOfWork) PromoCodeRepository() repository.PromoCodeRepository {
+	return m.transactionUnitOfWork.PromoCodeRepo
+}
+
+func (m *MockUnitOfWork) WishlistRepository() repository.WishlistRepository {
+	return m.transactionUnitOfWork.WishlistRepo
+}
+
+func (m *MockUnitOfWork) NotificationRepository() repository.NotificationRepository {
+	return m.transactionUnitOfWork.NotificationRepo
+}
+
+func (m *MockUnitOfWork) GroupPurchaseRepository() repository.GroupPurchaseRepository {
+	return m.transactionUnitOfWork.GroupPurchaseRepo
+}
+
+func (m *MockUnitOfWork) WalletRepository() repository.WalletRepository {
+	return m.transactionUnitOfWork.WalletRepo
+}
+
+func (m *MockUnitOfWork) TeamRepository() repository.TeamRepository {
+	return m.transactionUnitOfWork.TeamRepo
+}
+
+func (m *MockUnitOfWork) LeaderboardRepository() repository.LeaderboardRepository {
+	return m.transactionUnitOfWork.LeaderboardRepo
+}
+
+func (m *MockUnitOfWork) AchievementRepository() repository.AchievementRepository {
+	return m.transactionUnitOfWork.AchievementRepo
+}
+
+func (m *MockUnitOfWork) LoginThrottleRepository() repository.LoginThrottleRepository {
+	return m.transactionUnitOfWork.LoginThrottleRepo
+}
+
+func (m *MockUnitOfWork) RecoveryCodeRepository() repository.RecoveryCodeRepository {
+	return m.transactionUnitOfWork.RecoveryCodeRepo
+}
+
+func (m *MockUnitOfWork) APIKeyRepository() repository.APIKeyRepository {
+	return m.transactionUnitOfWork.APIKeyRepo
+}
+
+func (m *MockUnitOfWork) OIDCLoginRepository() repository.OIDCLoginRepository {
+	return m.transactionUnitOfWork.OIDCLoginRepo
+}
+
 // Tests
 
 func TestTransactionService_GetInfo(t *testing.T) {
@@ -128,7 +418,7 @@ func TestTransactionService_GetInfo(t *testing.T) {
 			TransactionRepo: &MockTransactionRepository{},
 		}
 		uow := &MockUnitOfWork{transactionUnitOfWork: tuow}
-		service := NewTransactionService(uow)
+		service := NewTransactionService(uow, nil)
 
 		_, err := service.GetInfo(1)
 		assert.EqualError(t, err, "user not found")
@@ -140,7 +430,7 @@ func TestTransactionService_GetInfo(t *testing.T) {
 			{ToUser: entity.User{Name: "user2"}, Amount: 100},
 		}
 		income := []entity.Transaction{
-			{FromUser: entity.User{Name: "user3"}, Amount: 200},
+			{FromUser: entity.User{Name: "user3", DisplayName: "Carol Smith"}, Amount: 200},
 		}
 		inventory := []entity.InventoryItem{
 			{Item: entity.Item{Name: "item1"}, Quantity: 2},
@@ -153,13 +443,30 @@ func TestTransactionService_GetInfo(t *testing.T) {
 		transactionRepo.On("GetOutcomeTransactions", uint(1)).Return(outcome, nil)
 		transactionRepo.On("GetIncomeTransactions", uint(1)).Return(income, nil)
 		transactionRepo.On("GetUserInventory", uint(1)).Return(inventory, nil)
+		transactionRepo.On("GetIncomeItemTransfers", uint(1)).Return([]entity.ItemTransfer{
+			{FromUser: entity.User{Name: "user3"}, Item: entity.Item{Name: "cup"}, Quantity: 1, Gift: true},
+		}, nil)
+		transactionRepo.On("GetOutcomeItemTransfers", uint(1)).Return([]entity.ItemTransfer{}, nil)
+
+		purchaseRepo := &MockPurchaseRepository{}
+		purchaseRepo.On("GetRefundedPurchases", uint(1)).Return([]entity.Purchase{}, nil)
+
+		awardedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+		achievementRepo := &MockAchievementRepository{}
+		achievementRepo.On("GetUserBadges", uint(1)).Return([]entity.UserBadge{
+			{Model: gorm.Model{CreatedAt: awardedAt}, UserID: 1, Badge: "first-purchase", Description: "Bought the first item"},
+			{Model: gorm.Model{CreatedAt: awardedAt}, UserID: 1, Badge: "recognised", Description: "Received 1000 coins", Bonus: 100},
+		}, nil)
 
 		tuow := &MockTransactionUnitOfWork{
 			UserRepo:        userRepo,
 			TransactionRepo: transactionRepo,
+			PurchaseRepo:    purchaseRepo,
+			WishlistRepo:    emptyWishlist(1),
+			AchievementRepo: achievementRepo,
 		}
 		uow := &MockUnitOfWork{transactionUnitOfWork: tuow}
-		service := NewTransactionService(uow)
+		service := NewTransactionService(uow, nil)
 
 		res, err := service.GetInfo(1)
 		assert.NoError(t, err)
@@ -170,12 +477,27 @@ func TestTransactionService_GetInfo(t *testing.T) {
 			},
 			CoinHistory: model.CoinHistory{
 				Sent: []model.CoinHistorySent{
-					{ToUser: "user2", Amount: 100},
+					{ToUser: "user2", ToDisplayName: "user2", Amount: 100},
 				},
 				Received: []model.CoinHistoryReceived{
-					{FromUser: "user3", Amount: 200},
+					{FromUser: "user3", FromDisplayName: "Carol Smith", Amount: 200},
+				},
+				Bonuses: []model.CoinHistoryBonus{
+					{Badge: "recognised", Amount: 100, AwardedAt: awardedAt},
 				},
 			},
+			ItemHistory: model.ItemHistory{
+				Received: []model.ItemHistoryReceived{
+					{FromUser: "user3", FromDisplayName: "user3", Item: "cup", Quantity: 1, Gift: true},
+				},
+				Sent: []model.ItemHistorySent{},
+			},
+			Refunds:  []model.Refund{},
+			Wishlist: []model.WishlistItem{},
+			Badges: []model.Badge{
+				{Badge: "first-purchase", Description: "Bought the first item", AwardedAt: awardedAt},
+				{Badge: "recognised", Description: "Received 1000 coins", Bonus: 100, AwardedAt: awardedAt},
+			},
 		}, res)
 	})
 }
@@ -194,7 +516,7 @@ func TestTransactionService_SendCoin(t *testing.T) {
 			TransactionRepo: &MockTransactionRepository{},
 		}
 		uow := &MockUnitOfWork{transactionUnitOfWork: tuow}
-		service := NewTransactionService(uow)
+		service := NewTransactionService(uow, nil)
 
 		err := service.SendCoin(1, "user2", 100)
 		assert.EqualError(t, err, "insufficient balance")
@@ -216,9 +538,10 @@ func TestTransactionService_SendCoin(t *testing.T) {
 		tuow := &MockTransactionUnitOfWork{
 			UserRepo:        userRepo,
 			TransactionRepo: transactionRepo,
+			WishlistRepo:    emptyWishlist(2),
 		}
 		uow := &MockUnitOfWork{transactionUnitOfWork: tuow}
-		service := NewTransactionService(uow)
+		service := NewTransactionService(uow, nil)
 
 		err := service.SendCoin(1, "user2", 100)
 		assert.NoError(t, err)
@@ -228,6 +551,82 @@ func TestTransactionService_SendCoin(t *testing.T) {
 	})
 }
 
+func TestTransactionService_SendCoinBatch(t *testing.T) {
+	t.Run("UnknownRecipient", func(t *testing.T) {
+		fromUser := &entity.User{Model: gorm.Model{ID: 1}, Balance: 200}
+		toUser := &entity.User{Model: gorm.Model{ID: 2}, Name: "user2"}
+
+		userRepo := &MockUserRepository{}
+		userRepo.On("FindUserById", uint(1)).Return(fromUser, nil)
+		userRepo.On("FindUserByName", "user2").Return(toUser, nil)
+		userRepo.On("FindUserByName", "ghost").Return((*entity.User)(nil), nil)
+
+		tuow := &MockTransactionUnitOfWork{UserRepo: userRepo}
+		service := NewTransactionService(&MockUnitOfWork{transactionUnitOfWork: tuow}, nil)
+
+		results, err := service.SendCoinBatch(1, []model.SendCoinRequest{
+			{ToUser: "user2", Amount: 10},
+			{ToUser: "ghost", Amount: 10},
+		})
+		assert.EqualError(t, err, "batch validation failed")
+		assert.Equal(t, []model.SendCoinBatchResult{
+			{ToUser: "user2", Amount: 10},
+			{ToUser: "ghost", Amount: 10, Error: "user not found"},
+		}, results)
+		assert.Equal(t, uint(200), fromUser.Balance)
+		assert.True(t, tuow.rollbackCalled)
+	})
+
+	t.Run("InsufficientTotal", func(t *testing.T) {
+		fromUser := &entity.User{Model: gorm.Model{ID: 1}, Balance: 100}
+		toUser := &entity.User{Model: gorm.Model{ID: 2}, Name: "user2"}
+
+		userRepo := &MockUserRepository{}
+		userRepo.On("FindUserById", uint(1)).Return(fromUser, nil)
+		userRepo.On("FindUserByName", "user2").Return(toUser, nil)
+
+		tuow := &MockTransactionUnitOfWork{UserRepo: userRepo}
+		service := NewTransactionService(&MockUnitOfWork{transactionUnitOfWork: tuow}, nil)
+
+		_, err := service.SendCoinBatch(1, []model.SendCoinRequest{
+			{ToUser: "user2", Amount: 60},
+			{ToUser: "user2", Amount: 60},
+		})
+		assert.EqualError(t, err, "insufficient balance")
+		assert.True(t, tuow.rollbackCalled)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		fromUser := &entity.User{Model: gorm.Model{ID: 1}, Balance: 100}
+		user2 := &entity.User{Model: gorm.Model{ID: 2}, Name: "user2"}
+		user3 := &entity.User{Model: gorm.Model{ID: 3}, Name: "user3"}
+
+		userRepo := &MockUserRepository{}
+		userRepo.On("FindUserById", uint(1)).Return(fromUser, nil)
+		userRepo.On("FindUserByName", "user2").Return(user2, nil)
+		userRepo.On("FindUserByName", "user3").Return(user3, nil)
+		userRepo.On("UpdateUser", mock.Anything).Return(nil)
+
+		transactionRepo := &MockTransactionRepository{}
+		transactionRepo.On("CreateTransaction", mock.Anything).Return(nil)
+
+		tuow := &MockTransactionUnitOfWork{UserRepo: userRepo, TransactionRepo: transactionRepo, WishlistRepo: emptyWishlist(2, 3)}
+		service := NewTransactionService(&MockUnitOfWork{transactionUnitOfWork: tuow}, nil)
+
+		_, err := service.SendCoinBatch(1, []model.SendCoinRequest{
+			{ToUser: "user2", Amount: 30},
+			{ToUser: "user3", Amount: 30},
+			{ToUser: "user2", Amount: 10},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, uint(30), fromUser.Balance)
+		assert.Equal(t, uint(40), user2.Balance)
+		assert.Equal(t, uint(30), user3.Balance)
+		transactionRepo.AssertNumberOfCalls(t, "CreateTransaction", 3)
+		assert.True(t, tuow.commitCalled)
+	})
+}
+
 func TestTransactionService_BuyItem(t *testing.T) {
 	t.Run("ItemNotFound", func(t *testing.T) {
 		user := &entity.User{Model: gorm.Model{ID: 1}, Balance: 1000}
@@ -244,9 +643,9 @@ func TestTransactionService_BuyItem(t *testing.T) {
 			TransactionRepo: transactionRepo,
 		}
 		uow := &MockUnitOfWork{transactionUnitOfWork: tuow}
-		service := NewTransactionService(uow)
+		service := NewTransactionService(uow, nil)
 
-		err := service.BuyItem(1, "item1")
+		err := service.BuyItem(1, "item1", "", "")
 		assert.EqualError(t, err, "item not found")
 		assert.True(t, tuow.rollbackCalled)
 	})
@@ -261,18 +660,250 @@ func TestTransactionService_BuyItem(t *testing.T) {
 
 		transactionRepo := &MockTransactionRepository{}
 		transactionRepo.On("GetItemByName", "item1").Return(item, nil)
-		transactionRepo.On("AddItem", uint(1), uint(1)).Return(nil)
+		transactionRepo.On("AddItems", uint(1), uint(1), uint(0), uint(1)).Return(nil)
+
+		purchaseRepo := &MockPurchaseRepository{}
+		purchaseRepo.On("CreatePurchase", mock.MatchedBy(func(p *entity.Purchase) bool {
+			return p.BuyerId == 1 && p.OwnerId == 1 && p.Price == 500
+		})).Return(nil)
+
+		itemRepo := &MockItemRepository{}
+		itemRepo.On("CountVariants", uint(1)).Return(int64(0), nil)
+		itemRepo.On("FindEffectivePrice", uint(1), mock.Anything).Return((*entity.ItemPrice)(nil), nil)
 
 		tuow := &MockTransactionUnitOfWork{
 			UserRepo:        userRepo,
 			TransactionRepo: transactionRepo,
+			ItemRepo:        itemRepo,
+			PurchaseRepo:    purchaseRepo,
 		}
 		uow := &MockUnitOfWork{transactionUnitOfWork: tuow}
-		service := NewTransactionService(uow)
+		service := NewTransactionService(uow, nil)
 
-		err := service.BuyItem(1, "item1")
+		err := service.BuyItem(1, "item1", "", "")
 		assert.NoError(t, err)
 		assert.Equal(t, uint(500), user.Balance)
 		assert.True(t, tuow.commitCalled)
 	})
 }
+
+func TestTransactionService_BuyItemScheduledPrice(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	user := &entity.User{Model: gorm.Model{ID: 1}, Balance: 1000}
+	item := &entity.Item{Model: gorm.Model{ID: 3}, Name: "cup", Price: 20}
+
+	userRepo := &MockUserRepository{}
+	userRepo.On("FindUserById", uint(1)).Return(user, nil)
+	userRepo.On("UpdateUser", user).Return(nil)
+
+	transactionRepo := &MockTransactionRepository{}
+	transactionRepo.On("GetItemByName", "cup").Return(item, nil)
+	transactionRepo.On("AddItems", uint(1), uint(3), uint(0), uint(1)).Return(nil)
+
+	itemRepo := &MockItemRepository{}
+	itemRepo.On("CountVariants", uint(3)).Return(int64(0), nil)
+	itemRepo.On("FindEffectivePrice", uint(3), now).Return(&entity.ItemPrice{ItemID: 3, Price: 35}, nil)
+
+	purchaseRepo := &MockPurchaseRepository{}
+	purchaseRepo.On("CreatePurchase", mock.MatchedBy(func(p *entity.Purchase) bool {
+		return p.Price == 35
+	})).Return(nil)
+
+	tuow := &MockTransactionUnitOfWork{UserRepo: userRepo, TransactionRepo: transactionRepo, ItemRepo: itemRepo, PurchaseRepo: purchaseRepo}
+	service := NewTransactionService(&MockUnitOfWork{transactionUnitOfWork: tuow}, nil)
+	service.now = func() time.Time { return now }
+
+	err := service.BuyItem(1, "cup", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(965), user.Balance)
+	purchaseRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_BuyLimitedItem(t *testing.T) {
+	t.Run("OutOfStock", func(t *testing.T) {
+		stock := uint(1)
+		user := &entity.User{Model: gorm.Model{ID: 1}, Balance: 1000}
+		item := &entity.Item{Model: gorm.Model{ID: 3}, Name: "pink-hoody", Price: 500, Stock: &stock}
+
+		userRepo := &MockUserRepository{}
+		userRepo.On("FindUserById", uint(1)).Return(user, nil)
+
+		transactionRepo := &MockTransactionRepository{}
+		transactionRepo.On("GetItemByName", "pink-hoody").Return(item, nil)
+
+		itemRepo := &MockItemRepository{}
+		itemRepo.On("CountVariants", uint(3)).Return(int64(0), nil)
+		itemRepo.On("FindEffectivePrice", uint(3), mock.Anything).Return((*entity.ItemPrice)(nil), nil)
+		itemRepo.On("DecrementStock", uint(3)).Return(false, nil)
+
+		tuow := &MockTransactionUnitOfWork{UserRepo: userRepo, TransactionRepo: transactionRepo, ItemRepo: itemRepo}
+		service := NewTransactionService(&MockUnitOfWork{transactionUnitOfWork: tuow}, nil)
+
+		err := service.BuyItem(1, "pink-hoody", "", "")
+		assert.EqualError(t, err, "item is out of stock")
+		assert.Equal(t, uint(1000), user.Balance)
+		assert.True(t, tuow.rollbackCalled)
+	})
+
+	t.Run("PurchaseLimitReached", func(t *testing.T) {
+		user := &entity.User{Model: gorm.Model{ID: 1}, Balance: 1000}
+		item := &entity.Item{Model: gorm.Model{ID: 3}, Name: "pink-hoody", Price: 500, PurchaseLimit: 1}
+
+		userRepo := &MockUserRepository{}
+		userRepo.On("FindUserById", uint(1)).Return(user, nil)
+
+		transactionRepo := &MockTransactionRepository{}
+		transactionRepo.On("GetItemByName", "pink-hoody").Return(item, nil)
+
+		purchaseRepo := &MockPurchaseRepository{}
+		purchaseRepo.On("CountActivePurchases", uint(1), uint(3)).Return(int64(1), nil)
+
+		itemRepo := &MockItemRepository{}
+		itemRepo.On("CountVariants", uint(3)).Return(int64(0), nil)
+		itemRepo.On("FindEffectivePrice", uint(3), mock.Anything).Return((*entity.ItemPrice)(nil), nil)
+
+		tuow := &MockTransactionUnitOfWork{UserRepo: userRepo, TransactionRepo: transactionRepo, ItemRepo: itemRepo, PurchaseRepo: purchaseRepo}
+		service := NewTransactionService(&MockUnitOfWork{transactionUnitOfWork: tuow}, nil)
+
+		err := service.BuyItem(1, "pink-hoody", "", "")
+		assert.EqualError(t, err, "purchase limit for this item is reached")
+		assert.True(t, tuow.rollbackCalled)
+	})
+}
+
+func TestTransactionService_BuyItemVariant(t *testing.T) {
+	item := &entity.Item{Model: gorm.Model{ID: 3}, Name: "hoody", Price: 300}
+
+	t.Run("VariantRequired", func(t *testing.T) {
+		user := &entity.User{Model: gorm.Model{ID: 1}, Balance: 1000}
+
+		userRepo := &MockUserRepository{}
+		userRepo.On("FindUserById", uint(1)).Return(user, nil)
+
+		transactionRepo := &MockTransactionRepository{}
+		transactionRepo.On("GetItemByName", "hoody").Return(item, nil)
+
+		itemRepo := &MockItemRepository{}
+		itemRepo.On("CountVariants", uint(3)).Return(int64(2), nil)
+
+		tuow := &MockTransactionUnitOfWork{UserRepo: userRepo, TransactionRepo: transactionRepo, ItemRepo: itemRepo}
+		service := NewTransactionService(&MockUnitOfWork{transactionUnitOfWork: tuow}, nil)
+
+		err := service.BuyItem(1, "hoody", "", "")
+		assert.EqualError(t, err, "variant is required for this item")
+		assert.True(t, tuow.rollbackCalled)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		stock := uint(4)
+		user := &entity.User{Model: gorm.Model{ID: 1}, Balance: 1000}
+		variant := &entity.ItemVariant{Model: gorm.Model{ID: 7}, ItemID: 3, Name: "XL", PriceDelta: 20, Stock: &stock}
+
+		userRepo := &MockUserRepository{}
+		userRepo.On("FindUserById", uint(1)).Return(user, nil)
+		userRepo.On("UpdateUser", user).Return(nil)
+
+		transactionRepo := &MockTransactionRepository{}
+		transactionRepo.On("GetItemByName", "hoody").Return(item, nil)
+		transactionRepo.On("AddItems", uint(1), uint(3), uint(7), uint(1)).Return(nil)
+
+		itemRepo := &MockItemRepository{}
+		itemRepo.On("FindVariant", uint(3), "XL").Return(variant, nil)
+		itemRepo.On("FindEffectivePrice", uint(3), mock.Anything).Return((*entity.ItemPrice)(nil), nil)
+		itemRepo.On("DecrementVariantStock", uint(7)).Return(true, nil)
+
+		purchaseRepo := &MockPurchaseRepository{}
+		purchaseRepo.On("CreatePurchase", mock.MatchedBy(func(p *entity.Purchase) bool {
+			return p.VariantID == 7 && p.Price == 320
+		})).Return(nil)
+
+		tuow := &MockTransactionUnitOfWork{UserRepo: userRepo, TransactionRepo: transactionRepo, ItemRepo: itemRepo, PurchaseRepo: purchaseRepo}
+		service := NewTransactionService(&MockUnitOfWork{transactionUnitOfWork: tuow}, nil)
+
+		err := service.BuyItem(1, "hoody", "XL", "")
+		assert.NoError(t, err)
+		assert.Equal(t, uint(680), user.Balance)
+		purchaseRepo.AssertExpectations(t)
+		assert.True(t, tuow.commitCalled)
+	})
+}
+
+func TestTransactionService_GiftItem(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		buyer := &entity.User{Model: gorm.Model{ID: 1}, Balance: 100}
+		recipient := &entity.User{Model: gorm.Model{ID: 2}, Name: "user2", Balance: 0}
+		item := &entity.Item{Model: gorm.Model{ID: 3}, Name: "cup", Price: 20}
+
+		userRepo := &MockUserRepository{}
+		userRepo.On("FindUserById", uint(1)).Return(buyer, nil)
+		userRepo.On("FindUserByName", "user2").Return(recipient, nil)
+		userRepo.On("UpdateUser", buyer).Return(nil)
+
+		transactionRepo := &MockTransactionRepository{}
+		transactionRepo.On("GetItemByName", "cup").Return(item, nil)
+		transactionRepo.On("AddItems", uint(2), uint(3), uint(0), uint(1)).Return(nil)
+		transactionRepo.On("CreateItemTransfer", mock.MatchedBy(func(tr *entity.ItemTransfer) bool {
+			return tr.FromId == 1 && tr.ToId == 2 && tr.Gift
+		})).Return(nil)
+
+		purchaseRepo := &MockPurchaseRepository{}
+		purchaseRepo.On("CreatePurchase", mock.Anything).Return(nil)
+
+		itemRepo := &MockItemRepository{}
+		itemRepo.On("CountVariants", uint(3)).Return(int64(0), nil)
+		itemRepo.On("FindEffectivePrice", uint(3), mock.Anything).Return((*entity.ItemPrice)(nil), nil)
+
+		tuow := &MockTransactionUnitOfWork{UserRepo: userRepo, TransactionRepo: transactionRepo, ItemRepo: itemRepo, PurchaseRepo: purchaseRepo}
+		service := NewTransactionService(&MockUnitOfWork{transactionUnitOfWork: tuow}, nil)
+
+		err := service.GiftItem(1, "cup", "", "user2", "")
+		assert.NoError(t, err)
+		assert.Equal(t, uint(80), buyer.Balance)
+		assert.Equal(t, uint(0), recipient.Balance)
+		transactionRepo.AssertExpectations(t)
+		assert.True(t, tuow.commitCalled)
+	})
+}
+
+func TestTransactionService_TransferItem(t *testing.T) {
+	fromUser := &entity.User{Model: gorm.Model{ID: 1}}
+	toUser := &entity.User{Model: gorm.Model{ID: 2}, Name: "user2"}
+	item := &entity.Item{Model: gorm.Model{ID: 3}, Name: "cup"}
+
+	t.Run("NotEnoughItems", func(t *testing.T) {
+		userRepo := &MockUserRepository{}
+		userRepo.On("FindUserById", uint(1)).Return(fromUser, nil)
+		userRepo.On("FindUserByName", "user2").Return(toUser, nil)
+
+		transactionRepo := &MockTransactionRepository{}
+		transactionRepo.On("GetItemByName", "cup").Return(item, nil)
+		transactionRepo.On("RemoveItems", uint(1), uint(3), uint(0), uint(2)).Return(false, nil)
+
+		tuow := &MockTransactionUnitOfWork{UserRepo: userRepo, TransactionRepo: transactionRepo}
+		service := NewTransactionService(&MockUnitOfWork{transactionUnitOfWork: tuow}, nil)
+
+		err := service.TransferItem(1, "cup", "", "user2", 2)
+		assert.EqualError(t, err, "not enough items in inventory")
+		assert.True(t, tuow.rollbackCalled)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		userRepo := &MockUserRepository{}
+		userRepo.On("FindUserById", uint(1)).Return(fromUser, nil)
+		userRepo.On("FindUserByName", "user2").Return(toUser, nil)
+
+		transactionRepo := &MockTransactionRepository{}
+		transactionRepo.On("GetItemByName", "cup").Return(item, nil)
+		transactionRepo.On("RemoveItems", uint(1), uint(3), uint(0), uint(2)).Return(true, nil)
+		transactionRepo.On("AddItems", uint(2), uint(3), uint(0), uint(2)).Return(nil)
+		transactionRepo.On("CreateItemTransfer", mock.Anything).Return(nil)
+
+		tuow := &MockTransactionUnitOfWork{UserRepo: userRepo, TransactionRepo: transactionRepo}
+		service := NewTransactionService(&MockUnitOfWork{transactionUnitOfWork: tuow}, nil)
+
+		err := service.TransferItem(1, "cup", "", "user2", 2)
+		assert.NoError(t, err)
+		transactionRepo.AssertExpectations(t)
+		assert.True(t, tuow.commitCalled)
+	})
+}
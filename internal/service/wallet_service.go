@@ -0,0 +1,439 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"merch_shop/internal/entity"
+	"merch_shop/internal/model"
+	"merch_shop/internal/repository"
+	"time"
+)
+
+type WalletService struct {
+	uow repository.UnitOfWork
+	now func() time.Time
+}
+
+func NewWalletService(uow repository.UnitOfWork) *WalletService {
+	return &WalletService{uow: uow, now: time.Now}
+}
+
+// CreateWallet creates an empty wallet owned by the user.
+func (w WalletService) CreateWallet(userId uint, name string) (model.Wallet, error) {
+	tx, err := w.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return model.Wallet{}, fmt.Errorf("failed to begin transaction")
+	}
+
+	walletRepository := tx.WalletRepository()
+	existing, err := walletRepository.FindWalletByName(name)
+	if err != nil {
+		tx.Rollback()
+		return model.Wallet{}, fmt.Errorf("failed to find wallet")
+	}
+	if existing != nil {
+		tx.Rollback()
+		return model.Wallet{}, fmt.Errorf("wallet already exists")
+	}
+	user, err := tx.UserRepository().FindUserById(userId)
+	if err != nil {
+		tx.Rollback()
+		return model.Wallet{}, fmt.Errorf("failed to find user")
+	}
+	if user == nil {
+		tx.Rollback()
+		return model.Wallet{}, fmt.Errorf("user not found")
+	}
+	wallet := entity.Wallet{Name: name}
+	if err := walletRepository.CreateWallet(&wallet); err != nil {
+		tx.Rollback()
+		return model.Wallet{}, fmt.Errorf("failed to create wallet")
+	}
+	owner := entity.WalletMember{WalletID: wallet.ID, UserID: user.ID, User: *user, Role: entity.WalletOwner}
+	if err := walletRepository.SaveWalletMember(&owner); err != nil {
+		tx.Rollback()
+		return model.Wallet{}, fmt.Errorf("failed to add wallet member")
+	}
+	wallet.Members = []entity.WalletMember{owner}
+
+	tx.Commit()
+	return toWalletModel(wallet, entity.WalletOwner), nil
+}
+
+// GetWallets lists the wallets the user is a member of.
+func (w WalletService) GetWallets(userId uint) ([]model.Wallet, error) {
+	wallets, err := w.uow.WalletRepository().GetUserWallets(userId)
+	if err != nil {
+		return nil, fmt.Errorf("error getting wallets")
+	}
+	walletsModel := make([]model.Wallet, 0, len(wallets))
+	for _, v := range wallets {
+		walletsModel = append(walletsModel, toWalletModel(v, memberRole(&v, userId)))
+	}
+	return walletsModel, nil
+}
+
+func (w WalletService) GetWallet(userId uint, name string) (model.Wallet, error) {
+	wallet, role, err := findWalletMember(w.uow, userId, name)
+	if err != nil {
+		return model.Wallet{}, err
+	}
+	return toWalletModel(*wallet, role), nil
+}
+
+// GetWalletHistory lists the wallet's deposits, transfers, purchases and refunds, newest first.
+func (w WalletService) GetWalletHistory(userId uint, name string) ([]model.WalletTransaction, error) {
+	wallet, _, err := findWalletMember(w.uow, userId, name)
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := w.uow.WalletRepository().GetWalletTransactions(wallet.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting wallet history")
+	}
+	history := make([]model.WalletTransaction, 0, len(transactions))
+	for _, v := range transactions {
+		transaction := model.WalletTransaction{
+			Type:       v.Type,
+			User:       v.User.Name,
+			Amount:     v.Amount,
+			Variant:    v.Variant.Name,
+			PurchaseId: v.PurchaseID,
+			CreatedAt:  v.CreatedAt,
+		}
+		if v.ToUser != nil {
+			transaction.ToUser = v.ToUser.Name
+		}
+		if v.Item != nil {
+			transaction.Item = v.Item.Name
+		}
+		history = append(history, transaction)
+	}
+	return history, nil
+}
+
+// SetMember adds a user to the wallet or changes their role. Only owners manage members.
+func (w WalletService) SetMember(userId uint, name string, memberName string, role string) error {
+	tx, err := w.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction")
+	}
+
+	wallet, member, err := findManagedMember(tx, userId, name, memberName)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if role != entity.WalletOwner && isLastOwner(wallet, member.ID) {
+		tx.Rollback()
+		return fmt.Errorf("wallet must keep an owner")
+	}
+	err = tx.WalletRepository().SaveWalletMember(&entity.WalletMember{WalletID: wallet.ID, UserID: member.ID, Role: role})
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to save wallet member")
+	}
+
+	tx.Commit()
+	return nil
+}
+
+func (w WalletService) RemoveMember(userId uint, name string, memberName string) error {
+	tx, err := w.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction")
+	}
+
+	wallet, member, err := findManagedMember(tx, userId, name, memberName)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if isLastOwner(wallet, member.ID) {
+		tx.Rollback()
+		return fmt.Errorf("wallet must keep an owner")
+	}
+	removed, err := tx.WalletRepository().RemoveWalletMember(wallet.ID, member.ID)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to remove wallet member")
+	}
+	if !removed {
+		tx.Rollback()
+		return fmt.Errorf("user is not a member of this wallet")
+	}
+
+	tx.Commit()
+	return nil
+}
+
+// Deposit sends coins from the user into the wallet. Anyone may pay into a wallet.
+func (w WalletService) Deposit(userId uint, name string, amount uint) error {
+	tx, err := w.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction")
+	}
+
+	wallet, err := findWallet(tx, name)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	user, err := tx.UserRepository().FindUserById(userId)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to find user")
+	}
+	if user == nil {
+		tx.Rollback()
+		return fmt.Errorf("user not found")
+	}
+	if user.Balance < amount {
+		tx.Rollback()
+		return fmt.Errorf("insufficient balance")
+	}
+	user.Balance -= amount
+	wallet.Balance += amount
+	if err := tx.UserRepository().UpdateUser(user); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to update user")
+	}
+	if err := tx.WalletRepository().UpdateWallet(wallet); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to update wallet")
+	}
+	err = tx.WalletRepository().CreateWalletTransaction(&entity.WalletTransaction{
+		WalletID: wallet.ID,
+		Type:     entity.WalletDeposit,
+		UserID:   user.ID,
+		Amount:   amount,
+	})
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to create wallet transaction")
+	}
+
+	tx.Commit()
+	return nil
+}
+
+// SendCoin pays coins out of the wallet to any user. Only owners and spenders may spend.
+func (w WalletService) SendCoin(userId uint, name string, toUserName string, amount uint) error {
+	now := w.now()
+	tx, err := w.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction")
+	}
+
+	wallet, err := findSpendableWallet(tx, userId, name)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	toUser, err := tx.UserRepository().FindUserByName(toUserName)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to find user")
+	}
+	if toUser == nil {
+		tx.Rollback()
+		return fmt.Errorf("user not found")
+	}
+	if wallet.Balance < amount {
+		tx.Rollback()
+		return fmt.Errorf("insufficient balance")
+	}
+	previousBalance := toUser.Balance
+	wallet.Balance -= amount
+	toUser.Balance += amount
+	if err := tx.WalletRepository().UpdateWallet(wallet); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to update wallet")
+	}
+	if err := tx.UserRepository().UpdateUser(toUser); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to update user")
+	}
+	err = tx.WalletRepository().CreateWalletTransaction(&entity.WalletTransaction{
+		WalletID: wallet.ID,
+		Type:     entity.WalletSend,
+		UserID:   userId,
+		ToId:     &toUser.ID,
+		Amount:   amount,
+	})
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to create wallet transaction")
+	}
+	// The recipient's coin history and the transfer totals show the payout as coming from the spender.
+	err = tx.TransactionRepository().CreateTransaction(&entity.Transaction{
+		FromId:   userId,
+		ToId:     toUser.ID,
+		Amount:   amount,
+		WalletID: &wallet.ID,
+	})
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to create transaction")
+	}
+	if err := notifyAffordable(tx, toUser, previousBalance, now); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	tx.Commit()
+	return nil
+}
+
+// BuyItem buys an item with the wallet's coins for one of the wallet's members.
+// Only owners and spenders may spend.
+func (w WalletService) BuyItem(userId uint, name string, request model.WalletBuyRequest) error {
+	tx, err := w.uow.BeginTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction")
+	}
+
+	wallet, err := findSpendableWallet(tx, userId, name)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	var owner *entity.User
+	for _, v := range wallet.Members {
+		if v.User.Name == request.ForUser {
+			owner = &v.User
+		}
+	}
+	if owner == nil {
+		tx.Rollback()
+		return fmt.Errorf("user is not a member of this wallet")
+	}
+	buyer, err := tx.UserRepository().FindUserById(userId)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to find user")
+	}
+	if buyer == nil {
+		tx.Rollback()
+		return fmt.Errorf("user not found")
+	}
+	purchase, err := buyItem(tx, buyer, owner, wallet, request.Item, request.Variant, request.PromoCode, w.now())
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = tx.WalletRepository().CreateWalletTransaction(&entity.WalletTransaction{
+		WalletID:   wallet.ID,
+		Type:       entity.WalletPurchase,
+		UserID:     buyer.ID,
+		ToId:       &owner.ID,
+		Amount:     purchase.Price,
+		ItemID:     &purchase.ItemID,
+		VariantID:  purchase.VariantID,
+		PurchaseID: &purchase.ID,
+	})
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to create wallet transaction")
+	}
+
+	tx.Commit()
+	return nil
+}
+
+// walletName returns the name of the wallet coins were paid out of, or an empty string for a user's own coins.
+func walletName(wallet *entity.Wallet) string {
+	if wallet == nil {
+		return ""
+	}
+	return wallet.Name
+}
+
+func findWallet(tx repository.UnitOfWork, name string) (*entity.Wallet, error) {
+	wallet, err := tx.WalletRepository().FindWalletByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find wallet")
+	}
+	if wallet == nil {
+		return nil, fmt.Errorf("wallet not found")
+	}
+	return wallet, nil
+}
+
+// findWalletMember looks up the wallet and the user's role in it; non-members cannot see a wallet.
+func findWalletMember(tx repository.UnitOfWork, userId uint, name string) (*entity.Wallet, string, error) {
+	wallet, err := findWallet(tx, name)
+	if err != nil {
+		return nil, "", err
+	}
+	role := memberRole(wallet, userId)
+	if role == "" {
+		return nil, "", fmt.Errorf("you are not a member of this wallet")
+	}
+	return wallet, role, nil
+}
+
+func findSpendableWallet(tx repository.UnitOfWork, userId uint, name string) (*entity.Wallet, error) {
+	wallet, role, err := findWalletMember(tx, userId, name)
+	if err != nil {
+		return nil, err
+	}
+	if role != entity.WalletOwner && role != entity.WalletSpender {
+		return nil, fmt.Errorf("you are not allowed to spend from this wallet")
+	}
+	return wallet, nil
+}
+
+// findManagedMember checks that the user owns the wallet and looks up the member to manage.
+func findManagedMember(tx repository.UnitOfWork, userId uint, name string, memberName string) (*entity.Wallet, *entity.User, error) {
+	wallet, role, err := findWalletMember(tx, userId, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if role != entity.WalletOwner {
+		return nil, nil, fmt.Errorf("only owners can manage wallet members")
+	}
+	member, err := tx.UserRepository().FindUserByName(memberName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find user")
+	}
+	if member == nil {
+		return nil, nil, fmt.Errorf("user not found")
+	}
+	return wallet, member, nil
+}
+
+func memberRole(wallet *entity.Wallet, userId uint) string {
+	for _, v := range wallet.Members {
+		if v.UserID == userId {
+			return v.Role
+		}
+	}
+	return ""
+}
+
+// isLastOwner reports whether userId is the only owner of the wallet.
+func isLastOwner(wallet *entity.Wallet, userId uint) bool {
+	for _, v := range wallet.Members {
+		if v.Role == entity.WalletOwner && v.UserID != userId {
+			return false
+		}
+	}
+	return memberRole(wallet, userId) == entity.WalletOwner
+}
+
+func toWalletModel(wallet entity.Wallet, role string) model.Wallet {
+	members := make([]model.WalletMember, 0, len(wallet.Members))
+	for _, v := range wallet.Members {
+		members = append(members, model.WalletMember{
+			User: v.User.Name,
+			Role: v.Role,
+		})
+	}
+	return model.Wallet{
+		Name:    wallet.Name,
+		Balance: wallet.Balance,
+		Role:    role,
+		Members: members,
+	}
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"merch_shop/internal/model"
+	"testing"
+)
+
+type MockWalletRepository struct {
+	mock.Mock
+}
+
+func (m *MockWalletRepository) CreateWallet(wallet *entity.Wallet) error {
+	args := m.Called(wallet)
+	return args.Error(0)
+}
+
+func (m *MockWalletRepository) UpdateWallet(wallet *entity.Wallet) error {
+	args := m.Called(wallet)
+	return args.Error(0)
+}
+
+func (m *MockWalletRepository) FindWalletById(walletId uint) (*entity.Wallet, error) {
+	args := m.Called(walletId)
+	return args.Get(0).(*entity.Wallet), args.Error(1)
+}
+
+func (m *MockWalletRepository) FindWalletByName(name string) (*entity.Wallet, error) {
+	args := m.Called(name)
+	return args.Get(0).(*entity.Wallet), args.Error(1)
+}
+
+func (m *MockWalletRepository) GetUserWallets(userId uint) ([]entity.Wallet, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]entity.Wallet), args.Error(1)
+}
+
+func (m *MockWalletRepository) SaveWalletMember(member *entity.WalletMember) error {
+	args := m.Called(member)
+	return args.Error(0)
+}
+
+func (m *MockWalletRepository) RemoveWalletMember(walletId uint, userId uint) (bool, error) {
+	args := m.Called(walletId, userId)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockWalletRepository) CreateWalletTransaction(transaction *entity.WalletTransaction) error {
+	args := m.Called(transaction)
+	return args.Error(0)
+}
+
+func (m *MockWalletRepository) GetWalletTransactions(walletId uint) ([]entity.WalletTransaction, error) {
+	args := m.Called(walletId)
+	return args.Get(0).([]entity.WalletTransaction), args.Error(1)
+}
+
+func newTestWallet() *entity.Wallet {
+	return &entity.Wallet{
+		Model:   gorm.Model{ID: 5},
+		Name:    "platform",
+		Balance: 800,
+		Members: []entity.WalletMember{
+			{UserID: 1, User: entity.User{Model: gorm.Model{ID: 1}, Name: "alice"}, Role: entity.WalletOwner},
+			{UserID: 2, User: entity.User{Model: gorm.Model{ID: 2}, Name: "bob"}, Role: entity.WalletSpender},
+			{UserID: 3, User: entity.User{Model: gorm.Model{ID: 3}, Name: "carol"}, Role: entity.WalletViewer},
+		},
+	}
+}
+
+func TestWalletService_SendCoin(t *testing.T) {
+	t.Run("Viewer", func(t *testing.T) {
+		walletRepo := &MockWalletRepository{}
+		walletRepo.On("FindWalletByName", "platform").Return(newTestWallet(), nil)
+
+		tuow := &MockTransactionUnitOfWork{WalletRepo: walletRepo}
+		service := NewWalletService(&MockUnitOfWork{transactionUnitOfWork: tuow})
+
+		err := service.SendCoin(3, "platform", "dave", 100)
+		assert.EqualError(t, err, "you are not allowed to spend from this wallet")
+		assert.True(t, tuow.rollbackCalled)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		wallet := newTestWallet()
+		dave := &entity.User{Model: gorm.Model{ID: 4}, Name: "dave", Balance: 50}
+
+		walletRepo := &MockWalletRepository{}
+		walletRepo.On("FindWalletByName", "platform").Return(wallet, nil)
+		walletRepo.On("UpdateWallet", wallet).Return(nil)
+		walletRepo.On("CreateWalletTransaction", mock.MatchedBy(func(transaction *entity.WalletTransaction) bool {
+			return transaction.Type == entity.WalletSend && transaction.UserID == 2 && *transaction.ToId == 4 && transaction.Amount == 100
+		})).Return(nil)
+
+		userRepo := &MockUserRepository{}
+		userRepo.On("FindUserByName", "dave").Return(dave, nil)
+		userRepo.On("UpdateUser", dave).Return(nil)
+
+		transactionRepo := &MockTransactionRepository{}
+		transactionRepo.On("CreateTransaction", mock.MatchedBy(func(transaction *entity.Transaction) bool {
+			return transaction.FromId == 2 && transaction.ToId == 4 && transaction.Amount == 100 && *transaction.WalletID == wallet.ID
+		})).Return(nil)
+
+		tuow := &MockTransactionUnitOfWork{UserRepo: userRepo, TransactionRepo: transactionRepo, WalletRepo: walletRepo, WishlistRepo: emptyWishlist(4)}
+		service := NewWalletService(&MockUnitOfWork{transactionUnitOfWork: tuow})
+
+		err := service.SendCoin(2, "platform", "dave", 100)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(700), wallet.Balance)
+		assert.Equal(t, uint(150), dave.Balance)
+		walletRepo.AssertExpectations(t)
+		transactionRepo.AssertExpectations(t)
+		assert.True(t, tuow.commitCalled)
+	})
+}
+
+func TestWalletService_BuyItem(t *testing.T) {
+	t.Run("NotAMember", func(t *testing.T) {
+		walletRepo := &MockWalletRepository{}
+		walletRepo.On("FindWalletByName", "platform").Return(newTestWallet(), nil)
+
+		tuow := &MockTransactionUnitOfWork{WalletRepo: walletRepo}
+		service := NewWalletService(&MockUnitOfWork{transactionUnitOfWork: tuow})
+
+		err := service.BuyItem(2, "platform", model.WalletBuyRequest{Item: "cup", ForUser: "dave"})
+		assert.EqualError(t, err, "user is not a member of this wallet")
+	})
+
+	t.Run("ChargesWallet", func(t *testing.T) {
+		wallet := newTestWallet()
+		bob := &entity.User{Model: gorm.Model{ID: 2}, Name: "bob", Balance: 10}
+		item := &entity.Item{Model: gorm.Model{ID: 6}, Name: "cup", Price: 300}
+
+		walletRepo := &MockWalletRepository{}
+		walletRepo.On("FindWalletByName", "platform").Return(wallet, nil)
+		walletRepo.On("UpdateWallet", wallet).Return(nil)
+		walletRepo.On("CreateWalletTransaction", mock.MatchedBy(func(transaction *entity.WalletTransaction) bool {
+			return transaction.Type == entity.WalletPurchase && *transaction.ItemID == 6 && transaction.Amount == 300
+		})).Return(nil)
+
+		userRepo := &MockUserRepository{}
+		userRepo.On("FindUserById", uint(2)).Return(bob, nil)
+
+		transactionRepo := &MockTransactionRepository{}
+		transactionRepo.On("GetItemByName", "cup").Return(item, nil)
+		transactionRepo.On("AddItems", uint(3), uint(6), uint(0), uint(1)).Return(nil)
+		transactionRepo.On("CreateItemTransfer", mock.Anything).Return(nil)
+
+		itemRepo := &MockItemRepository{}
+		itemRepo.On("CountVariants", uint(6)).Return(int64(0), nil)
+		itemRepo.On("FindEffectivePrice", uint(6), mock.Anything).Return((*entity.ItemPrice)(nil), nil)
+
+		purchaseRepo := &MockPurchaseRepository{}
+		purchaseRepo.On("CreatePurchase", mock.MatchedBy(func(p *entity.Purchase) bool {
+			return p.BuyerId == 2 && p.OwnerId == 3 && *p.WalletID == 5
+		})).Return(nil)
+
+		tuow := &MockTransactionUnitOfWork{
+			UserRepo:        userRepo,
+			TransactionRepo: transactionRepo,
+			ItemRepo:        itemRepo,
+			PurchaseRepo:    purchaseRepo,
+			WalletRepo:      walletRepo,
+		}
+		service := NewWalletService(&MockUnitOfWork{transactionUnitOfWork: tuow})
+
+		err := service.BuyItem(2, "platform", model.WalletBuyRequest{Item: "cup", ForUser: "carol"})
+		assert.NoError(t, err)
+		assert.Equal(t, uint(500), wallet.Balance)
+		assert.Equal(t, uint(10), bob.Balance)
+		userRepo.AssertNotCalled(t, "UpdateUser", mock.Anything)
+		assert.True(t, tuow.commitCalled)
+	})
+}
+
+func TestWalletService_RemoveMember(t *testing.T) {
+	walletRepo := &MockWalletRepository{}
+	walletRepo.On("FindWalletByName", "platform").Return(newTestWallet(), nil)
+
+	userRepo := &MockUserRepository{}
+	userRepo.On("FindUserByName", "alice").Return(&entity.User{Model: gorm.Model{ID: 1}, Name: "alice"}, nil)
+
+	tuow := &MockTransactionUnitOfWork{UserRepo: userRepo, WalletRepo: walletRepo}
+	service := NewWalletService(&MockUnitOfWork{transactionUnitOfWork: tuow})
+
+	err := service.RemoveMember(1, "platform", "alice")
+	assert.EqualError(t, err, "wallet must keep an owner")
+	walletRepo.AssertNotCalled(t, "RemoveWalletMember", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"fmt"
+	"merch_shop/internal/entity"
+	"merch_shop/internal/model"
+	"merch_shop/internal/repository"
+	"time"
+)
+
+type WishlistService struct {
+	uow repository.UnitOfWork
+	now func() time.Time
+}
+
+func NewWishlistService(uow repository.UnitOfWork) *WishlistService {
+	return &WishlistService{uow: uow, now: time.Now}
+}
+
+func (w WishlistService) GetWishlist(userId uint) ([]model.WishlistItem, error) {
+	user, err := w.uow.UserRepository().FindUserById(userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user")
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+	return wishlistModel(w.uow, user, w.now())
+}
+
+func (w WishlistService) AddToWishlist(userId uint, name string, variant string) error {
+	item, itemVariant, err := findItem(w.uow, name, variant)
+	if err != nil {
+		return err
+	}
+	err = w.uow.WishlistRepository().AddWishlistItem(&entity.WishlistItem{
+		UserID:    userId,
+		ItemID:    item.ID,
+		VariantID: variantId(itemVariant),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add item to wishlist")
+	}
+	return nil
+}
+
+func (w WishlistService) RemoveFromWishlist(userId uint, name string, variant string) error {
+	item, itemVariant, err := findItem(w.uow, name, variant)
+	if err != nil {
+		return err
+	}
+	removed, err := w.uow.WishlistRepository().RemoveWishlistItem(userId, item.ID, variantId(itemVariant))
+	if err != nil {
+		return fmt.Errorf("failed to remove item from wishlist")
+	}
+	if !removed {
+		return fmt.Errorf("item is not in wishlist")
+	}
+	return nil
+}
+
+// wishlistModel lists the user's wishlist with current prices and the coins still missing for each item.
+func wishlistModel(tx repository.UnitOfWork, user *entity.User, now time.Time) ([]model.WishlistItem, error) {
+	wishlist, err := tx.WishlistRepository().GetWishlist(user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting wishlist")
+	}
+	wishlistModel := make([]model.WishlistItem, 0, len(wishlist))
+	for _, v := range wishlist {
+		price, err := wishlistPrice(tx, v, now)
+		if err != nil {
+			return nil, err
+		}
+		var coinsNeeded uint
+		if price > user.Balance {
+			coinsNeeded = price - user.Balance
+		}
+		wishlistModel = append(wishlistModel, model.WishlistItem{
+			Item:        v.Item.Name,
+			Variant:     v.Variant.Name,
+			Price:       price,
+			CoinsNeeded: coinsNeeded,
+		})
+	}
+	return wishlistModel, nil
+}
+
+// notifyAffordable notifies the user about wishlisted items that cost more than
+// previousBalance but no more than the current balance.
+func notifyAffordable(tx repository.UnitOfWork, user *entity.User, previousBalance uint, now time.Time) error {
+	wishlist, err := tx.WishlistRepository().GetWishlist(user.ID)
+	if err != nil {
+		return fmt.Errorf("error getting wishlist")
+	}
+	for _, v := range wishlist {
+		price, err := wishlistPrice(tx, v, now)
+		if err != nil {
+			return err
+		}
+		if price <= previousBalance || price > user.Balance {
+			continue
+		}
+		name := v.Item.Name
+		if v.VariantID != 0 {
+			name = fmt.Sprintf("%s (%s)", v.Item.Name, v.Variant.Name)
+		}
+		err = tx.NotificationRepository().CreateNotification(&entity.Notification{
+			UserID:  user.ID,
+			Message: fmt.Sprintf("You can now afford %s from your wishlist", name),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create notification")
+		}
+	}
+	return nil
+}
+
+func wishlistPrice(tx repository.UnitOfWork, entry entity.WishlistItem, now time.Time) (uint, error) {
+	price, err := effectivePrice(tx, &entry.Item, now)
+	if err != nil {
+		return 0, err
+	}
+	if entry.VariantID == 0 {
+		return price, nil
+	}
+	return variantPrice(price, &entry.Variant), nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+	"merch_shop/internal/entity"
+	"testing"
+	"time"
+)
+
+type MockWishlistRepository struct {
+	mock.Mock
+}
+
+func (m *MockWishlistRepository) AddWishlistItem(item *entity.WishlistItem) error {
+	args := m.Called(item)
+	return args.Error(0)
+}
+
+func (m *MockWishlistRepository) RemoveWishlistItem(userId uint, itemId uint, variantId uint) (bool, error) {
+	args := m.Called(userId, itemId, variantId)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockWishlistRepository) GetWishlist(userId uint) ([]entity.WishlistItem, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]entity.WishlistItem), args.Error(1)
+}
+
+// emptyWishlist returns a wishlist repository for tests that transfer coins to a user without a wishlist.
+func emptyWishlist(userIds ...uint) *MockWishlistRepository {
+	wishlistRepo := &MockWishlistRepository{}
+	for _, userId := range userIds {
+		wishlistRepo.On("GetWishlist", userId).Return([]entity.WishlistItem{}, nil)
+	}
+	return wishlistRepo
+}
+
+func TestWishlistService_GetWishlist(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	user := &entity.User{Model: gorm.Model{ID: 1}, Balance: 350}
+
+	userRepo := &MockUserRepository{}
+	userRepo.On("FindUserById", uint(1)).Return(user, nil)
+
+	wishlistRepo := &MockWishlistRepository{}
+	wishlistRepo.On("GetWishlist", uint(1)).Return([]entity.WishlistItem{
+		{ItemID: 3, Item: entity.Item{Model: gorm.Model{ID: 3}, Name: "pink-hoody", Price: 500}},
+		{ItemID: 4, Item: entity.Item{Model: gorm.Model{ID: 4}, Name: "cup", Price: 20}},
+	}, nil)
+
+	itemRepo := &MockItemRepository{}
+	itemRepo.On("FindEffectivePrice", mock.Anything, now).Return((*entity.ItemPrice)(nil), nil)
+
+	tuow := &MockTransactionUnitOfWork{UserRepo: userRepo, ItemRepo: itemRepo, WishlistRepo: wishlistRepo}
+	service := NewWishlistService(&MockUnitOfWork{transactionUnitOfWork: tuow})
+	service.now = func() time.Time { return now }
+
+	wishlist, err := service.GetWishlist(1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(150), wishlist[0].CoinsNeeded)
+	assert.Equal(t, uint(0), wishlist[1].CoinsNeeded)
+}
+
+func TestWishlistService_RemoveFromWishlist(t *testing.T) {
+	item := &entity.Item{Model: gorm.Model{ID: 3}, Name: "cup"}
+
+	transactionRepo := &MockTransactionRepository{}
+	transactionRepo.On("GetItemByName", "cup").Return(item, nil)
+
+	wishlistRepo := &MockWishlistRepository{}
+	wishlistRepo.On("RemoveWishlistItem", uint(1), uint(3), uint(0)).Return(false, nil)
+
+	tuow := &MockTransactionUnitOfWork{TransactionRepo: transactionRepo, WishlistRepo: wishlistRepo}
+	service := NewWishlistService(&MockUnitOfWork{transactionUnitOfWork: tuow})
+
+	err := service.RemoveFromWishlist(1, "cup", "")
+	assert.EqualError(t, err, "item is not in wishlist")
+}
+
+func TestTransactionService_SendCoinNotifiesAffordable(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	fromUser := &entity.User{Model: gorm.Model{ID: 1}, Name: "user1", Balance: 1000}
+	toUser := &entity.User{Model: gorm.Model{ID: 2}, Name: "user2", Balance: 450}
+
+	userRepo := &MockUserRepository{}
+	userRepo.On("FindUserById", uint(1)).Return(fromUser, nil)
+	userRepo.On("FindUserByName", "user2").Return(toUser, nil)
+	userRepo.On("UpdateUser", mock.Anything).Return(nil)
+
+	transactionRepo := &MockTransactionRepository{}
+	transactionRepo.On("CreateTransaction", mock.Anything).Return(nil)
+
+	wishlistRepo := &MockWishlistRepository{}
+	wishlistRepo.On("GetWishlist", uint(2)).Return([]entity.WishlistItem{
+		{ItemID: 3, Item: entity.Item{Model: gorm.Model{ID: 3}, Name: "pink-hoody", Price: 500}},
+		{ItemID: 4, Item: entity.Item{Model: gorm.Model{ID: 4}, Name: "cup", Price: 20}},
+		{ItemID: 5, Item: entity.Item{Model: gorm.Model{ID: 5}, Name: "powerbank", Price: 600}},
+	}, nil)
+
+	itemRepo := &MockItemRepository{}
+	itemRepo.On("FindEffectivePrice", mock.Anything, now).Return((*entity.ItemPrice)(nil), nil)
+
+	notificationRepo := &MockNotificationRepository{}
+	notificationRepo.On("CreateNotification", mock.MatchedBy(func(n *entity.Notification) bool {
+		return n.UserID == 2 && n.Message == "You can now afford pink-hoody from your wishlist"
+	})).Return(nil).Once()
+
+	tuow := &MockTransactionUnitOfWork{
+		UserRepo:         userRepo,
+		TransactionRepo:  transactionRepo,
+		ItemRepo:         itemRepo,
+		WishlistRepo:     wishlistRepo,
+		NotificationRepo: notificationRepo,
+	}
+	service := NewTransactionService(&MockUnitOfWork{transactionUnitOfWork: tuow}, nil)
+	service.now = func() time.Time { return now }
+
+	err := service.SendCoin(1, "user2", 100)
+	assert.NoError(t, err)
+	notificationRepo.AssertExpectations(t)
+	assert.True(t, tuow.commitCalled)
+}
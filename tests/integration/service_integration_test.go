@@ -2,10 +2,15 @@ package integration_test
 
 import (
 	"github.com/glebarez/sqlite"
+	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
+	"merch_shop/internal/config"
 	"merch_shop/internal/db"
 	"merch_shop/internal/entity"
+	"merch_shop/internal/model"
 	"merch_shop/internal/provider"
+	"merch_shop/internal/provider/ldaptest"
+	"merch_shop/internal/provider/oidctest"
 	"merch_shop/internal/repository"
 	"merch_shop/internal/service"
 	"testing"
@@ -31,12 +36,13 @@ func TestAuthServiceIntegration(t *testing.T) {
 	db := setupTestDB(t)
 	uow := repository.NewGormUnitOfWork(db)
 	jwtAuth := provider.NewJWTAuth([]byte(jwtSecret), 24*time.Hour)
-	authService := service.NewAuthService(jwtAuth, uow)
+	passwords := service.NewPasswordPolicy(8, nil, bcrypt.MinCost)
+	authService := service.NewAuthService(jwtAuth, uow, service.NewPasswordAuthenticator(uow, passwords), passwords, config.Auth{PasswordResetTTL: time.Hour})
 
 	t.Run("NewUserRegistration", func(t *testing.T) {
-		token, err := authService.Authenticate("newuser", "password")
+		response, err := authService.Authenticate("newuser", "password", "127.0.0.1")
 		assert.NoError(t, err)
-		assert.NotEmpty(t, token)
+		assert.NotEmpty(t, response.Token)
 
 		// Verify user creation
 		userRepo := uow.UserRepository()
@@ -48,16 +54,16 @@ func TestAuthServiceIntegration(t *testing.T) {
 
 	t.Run("ExistingUserLogin", func(t *testing.T) {
 		// Create user first
-		_, _ = authService.Authenticate("existinguser", "password")
+		_, _ = authService.Authenticate("existinguser", "password", "127.0.0.1")
 
 		t.Run("ValidCredentials", func(t *testing.T) {
-			token, err := authService.Authenticate("existinguser", "password")
+			response, err := authService.Authenticate("existinguser", "password", "127.0.0.1")
 			assert.NoError(t, err)
-			assert.NotEmpty(t, token)
+			assert.NotEmpty(t, response.Token)
 		})
 
 		t.Run("InvalidCredentials", func(t *testing.T) {
-			_, err := authService.Authenticate("existinguser", "wrongpass")
+			_, err := authService.Authenticate("existinguser", "wrongpass", "127.0.0.1")
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), "password is incorrect")
 		})
@@ -67,7 +73,7 @@ func TestAuthServiceIntegration(t *testing.T) {
 func TestTransactionServiceIntegration(t *testing.T) {
 	db := setupTestDB(t)
 	uow := repository.NewGormUnitOfWork(db)
-	service := service.NewTransactionService(uow)
+	service := service.NewTransactionService(uow, nil)
 
 	// Create test users
 	userRepo := uow.UserRepository()
@@ -115,7 +121,7 @@ func TestTransactionServiceIntegration(t *testing.T) {
 		txRepo := uow.TransactionRepository()
 		item, _ := txRepo.GetItemByName(itemName)
 
-		err := service.BuyItem(user1.ID, itemName)
+		err := service.BuyItem(user1.ID, itemName, "", "")
 		assert.NoError(t, err)
 
 		// Verify balance deduction
@@ -133,7 +139,7 @@ func TestTransactionServiceIntegration(t *testing.T) {
 func TestTransactionServiceEdgeCases(t *testing.T) {
 	db := setupTestDB(t)
 	uow := repository.NewGormUnitOfWork(db)
-	service := service.NewTransactionService(uow)
+	service := service.NewTransactionService(uow, nil)
 
 	t.Run("SendToNonExistentUser", func(t *testing.T) {
 		sender := createTestUser(t, uow, "sender1", 1000)
@@ -153,7 +159,7 @@ func TestTransactionServiceEdgeCases(t *testing.T) {
 
 	t.Run("BuyNonExistentItem", func(t *testing.T) {
 		user := createTestUser(t, uow, "buyer1", 1000)
-		err := service.BuyItem(user.ID, "unicorn")
+		err := service.BuyItem(user.ID, "unicorn", "", "")
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "item not found")
 	})
@@ -166,6 +172,161 @@ func TestTransactionServiceEdgeCases(t *testing.T) {
 	})
 }
 
+func TestPromoCodeRefundIntegration(t *testing.T) {
+	db := setupTestDB(t)
+	uow := repository.NewGormUnitOfWork(db)
+	transactionService := service.NewTransactionService(uow, nil)
+	refundService := service.NewRefundService(uow, 24*time.Hour)
+	promoCodeService := service.NewPromoCodeService(uow)
+	user := createTestUser(t, uow, "buyer", startBalance)
+
+	_, err := promoCodeService.CreatePromoCode(model.PromoCode{
+		Code:           "ONCE",
+		DiscountType:   entity.DiscountFixed,
+		Discount:       10,
+		MaxRedemptions: 1,
+		MaxPerUser:     1,
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, transactionService.BuyItem(user.ID, "t-shirt", "", "ONCE"))
+	err = transactionService.BuyItem(user.ID, "t-shirt", "", "ONCE")
+	assert.EqualError(t, err, "promo code redemption limit is reached")
+
+	// A refunded order gives its redemption back.
+	_, err = refundService.ReturnItem(user.ID, "t-shirt", "")
+	assert.NoError(t, err)
+	assert.NoError(t, transactionService.BuyItem(user.ID, "t-shirt", "", "ONCE"))
+
+	promoCode, err := uow.PromoCodeRepository().FindPromoCodeByCode("ONCE")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), promoCode.Redemptions)
+}
+
+func TestWalletPayoutIntegration(t *testing.T) {
+	db := setupTestDB(t)
+	uow := repository.NewGormUnitOfWork(db)
+	transactionService := service.NewTransactionService(uow, nil)
+	walletService := service.NewWalletService(uow)
+	owner := createTestUser(t, uow, "owner", startBalance)
+	recipient := createTestUser(t, uow, "recipient", 10)
+
+	_, err := walletService.CreateWallet(owner.ID, "platform")
+	assert.NoError(t, err)
+	assert.NoError(t, walletService.Deposit(owner.ID, "platform", 300))
+	assert.NoError(t, walletService.SendCoin(owner.ID, "platform", recipient.Name, 120))
+
+	info, err := transactionService.GetInfo(recipient.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(130), info.Coins)
+	assert.Equal(t, []model.CoinHistoryReceived{{
+		FromUser:        "owner",
+		FromDisplayName: "owner",
+		Amount:          120,
+		Wallet:          "platform",
+	}}, info.CoinHistory.Received)
+
+	// Team coins do not count as the spender's giving in leaderboards and achievements.
+	var totals []entity.DailyTransfer
+	assert.NoError(t, db.Where("to_id = ?", recipient.ID).Find(&totals).Error)
+	assert.Empty(t, totals)
+	sent, err := uow.AchievementRepository().GetUserMetric(owner.ID, repository.AchievementCoinsSent)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(0), sent)
+}
+
+func TestOIDCServiceIntegration(t *testing.T) {
+	db := setupTestDB(t)
+	uow := repository.NewGormUnitOfWork(db)
+	jwtAuth := provider.NewJWTAuth([]byte(jwtSecret), 24*time.Hour)
+	passwords := service.NewPasswordPolicy(8, nil, bcrypt.MinCost)
+	authService := service.NewAuthService(jwtAuth, uow, service.NewPasswordAuthenticator(uow, passwords), passwords, config.Auth{PasswordResetTTL: time.Hour})
+	idp := oidctest.NewServer("merch-shop", "client-secret")
+	defer idp.Close()
+	oidc := provider.NewOIDCProvider(idp.URL, "merch-shop", "client-secret", "https://shop.example.com/api/auth/oidc/callback", nil)
+	oidcService := service.NewOIDCService(authService, oidc, config.OIDC{AllowSignUp: true, LoginTTL: 10 * time.Minute})
+
+	signIn := func(identity oidctest.Identity) (string, string) {
+		login, err := oidcService.BeginLogin()
+		assert.NoError(t, err)
+		code, state, err := idp.Authorize(login.AuthorizationURL, identity)
+		assert.NoError(t, err)
+		return state, code
+	}
+	identity := oidctest.Identity{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true, PreferredUsername: "alice"}
+
+	t.Run("FirstSignInRegistersUser", func(t *testing.T) {
+		state, code := signIn(identity)
+		response, err := oidcService.CompleteLogin(state, code)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, response.Token)
+
+		user, err := uow.UserRepository().FindUserByOIDCSubject("sub-1")
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", user.Name)
+		assert.Equal(t, startBalance, user.Balance)
+
+		_, err = oidcService.CompleteLogin(state, code)
+		assert.EqualError(t, err, "sign-in is invalid or expired")
+	})
+
+	t.Run("LinksExistingUserByEmail", func(t *testing.T) {
+		user := createTestUser(t, uow, "bob", startBalance)
+		user.Email = "bob@example.com"
+		assert.NoError(t, uow.UserRepository().UpdateUser(user))
+
+		state, code := signIn(oidctest.Identity{Subject: "sub-2", Email: "Bob@Example.com", EmailVerified: true, PreferredUsername: "bobby"})
+		_, err := oidcService.CompleteLogin(state, code)
+		assert.NoError(t, err)
+
+		linked, err := uow.UserRepository().FindUserByOIDCSubject("sub-2")
+		assert.NoError(t, err)
+		assert.Equal(t, user.ID, linked.ID)
+	})
+}
+
+func TestLDAPAuthenticatorIntegration(t *testing.T) {
+	db := setupTestDB(t)
+	uow := repository.NewGormUnitOfWork(db)
+	directory := ldaptest.NewServer(
+		ldaptest.Entry{DN: "cn=merch-shop,ou=services,dc=example,dc=com", Password: "search-secret"},
+		ldaptest.Entry{
+			DN:       "uid=alice,ou=people,dc=example,dc=com",
+			Password: "alice-password",
+			Attributes: map[string][]string{
+				"uid":      {"alice"},
+				"mail":     {"alice@example.com"},
+				"memberOf": {"cn=shop-admins,ou=groups,dc=example,dc=com"},
+			},
+		},
+		ldaptest.Entry{DN: "uid=bob,ou=people,dc=example,dc=com", Password: "bob-password", Attributes: map[string][]string{"uid": {"bob"}}},
+	)
+	defer directory.Close()
+	client := provider.NewLDAPClient(directory.URL, "cn=merch-shop,ou=services,dc=example,dc=com", "search-secret", "dc=example,dc=com", "uid", time.Second)
+	authenticator, err := service.NewLDAPAuthenticator(uow, client, config.LDAP{GroupRoles: "admin=cn=shop-admins,ou=groups,dc=example,dc=com"})
+	assert.NoError(t, err)
+	jwtAuth := provider.NewJWTAuth([]byte(jwtSecret), 24*time.Hour)
+	authService := service.NewAuthService(jwtAuth, uow, authenticator, service.NewPasswordPolicy(8, nil, bcrypt.MinCost), config.Auth{PasswordResetTTL: time.Hour})
+
+	response, err := authService.Authenticate("alice", "alice-password", "127.0.0.1")
+	assert.NoError(t, err)
+	claims, err := jwtAuth.VerifyToken(response.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.RoleAdmin, claims.Role)
+
+	user, err := uow.UserRepository().FindUserByName("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice@example.com", user.Email)
+	assert.Equal(t, startBalance, user.Balance)
+
+	_, err = authService.Authenticate("alice", "wrong-password", "127.0.0.1")
+	assert.EqualError(t, err, "password is incorrect")
+
+	// Users outside the mapped groups are refused when there is no default role.
+	_, err = authService.Authenticate("bob", "bob-password", "127.0.0.1")
+	assert.EqualError(t, err, "user is not permitted to sign in")
+}
+
 func createTestUser(t *testing.T, uow repository.UnitOfWork, name string, balance uint) *entity.User {
 	user := &entity.User{
 		Name:         name,